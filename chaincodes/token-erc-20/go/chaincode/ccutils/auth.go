@@ -27,3 +27,31 @@ func GetMSPID(ctx contractapi.TransactionContextInterface) error {
 
 	return nil
 }
+
+// Get timestamp(unix seconds) of the transaction proposal
+// endorser 마다 결과가 같아야 하므로 time.Now 대신 사용
+func GetTxTimestamp(ctx contractapi.TransactionContextInterface) (int64, error) {
+
+	timestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tx timestamp: %v", err)
+	}
+
+	return timestamp.GetSeconds(), nil
+}
+
+// Get MSPID, X.509 subject and issuer of submitting client identity
+func GetX509Identity(ctx contractapi.TransactionContextInterface) (string, string, string, error) {
+
+	mspId, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get client msp id: %v", err)
+	}
+
+	cert, err := ctx.GetClientIdentity().GetX509Certificate()
+	if err != nil || cert == nil {
+		return "", "", "", fmt.Errorf("failed to get client certificate: %v", err)
+	}
+
+	return mspId, cert.Subject.String(), cert.Issuer.String(), nil
+}
@@ -2,14 +2,18 @@ package ccutils
 
 const (
 	ChaincodeSuccess            = 200
+	ChaincodeAccepted           = 202
 	ChaincodeError              = 400
 	ChaincodeErrorNotFoundAPI   = 401
+	ChaincodeErrorForbidden     = 403
 	ChaincodeServiceUnavailable = 503
 )
 
 var CodeMessage = map[int]string{
 	ChaincodeSuccess:            "Success",
+	ChaincodeAccepted:           "Accepted : pending approval",
 	ChaincodeError:              "Error",
 	ChaincodeErrorNotFoundAPI:   "Not found API",
+	ChaincodeErrorForbidden:     "Forbidden",
 	ChaincodeServiceUnavailable: "Chaincode Service Unavailable",
 }
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
+)
+
+func (s *SmartContract) GrantRole(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{access.FieldRole, access.FieldSubjectType, access.FieldSubject}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{access.FieldRole, access.FieldSubjectType, access.FieldSubject}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// attributes 는 선택 입력
+	// {"role":"issuer","subjectType":"msp","subject":"Org1MSP","attributes":{"hf.Type":"client"}}
+	attributes := make(map[string]string)
+	if _, exist := args[access.FieldAttributes]; exist {
+		err = ccutils.CheckRequireTypeObject([]string{access.FieldAttributes}, args)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		for attrName, attrValue := range args[access.FieldAttributes].(map[string]interface{}) {
+			value, ok := attrValue.(string)
+			if !ok {
+				return ccutils.GenerateErrorResponse(fmt.Errorf("attribute %s must be a string", attrName))
+			}
+			attributes[attrName] = value
+		}
+	}
+
+	roleStruct := access.RoleStruct{}
+	roleStruct.Role = args[access.FieldRole].(string)
+	roleStruct.SubjectType = args[access.FieldSubjectType].(string)
+	roleStruct.Subject = args[access.FieldSubject].(string)
+	roleStruct.Attributes = attributes
+	roleStruct.GrantedBy = ccutils.GetAddress([]byte(id))
+
+	// admin role 은 timelock (GrantAdminRole) 으로만 부여
+	if roleStruct.Role == access.RoleAdmin {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("admin role can only be granted through ScheduleAction %s", ActionGrantAdminRole))
+	}
+
+	role, err := access.GrantRole(ctx, roleStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RoleGranted", From: roleStruct.GrantedBy, To: roleStruct.Subject, Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(role)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) RevokeRole(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{access.FieldRole, access.FieldSubjectType, access.FieldSubject}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{access.FieldRole, access.FieldSubjectType, access.FieldSubject}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	role := args[access.FieldRole].(string)
+	subjectType := args[access.FieldSubjectType].(string)
+	subject := args[access.FieldSubject].(string)
+
+	// admin role 은 timelock (RevokeAdminRole) 으로만 회수
+	if role == access.RoleAdmin {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("admin role can only be revoked through ScheduleAction %s", ActionRevokeAdminRole))
+	}
+
+	err = access.RevokeRole(ctx, role, subjectType, subject)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RoleRevoked", From: ccutils.GetAddress([]byte(id)), To: subject, Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+}
+
+// 호출자가 해당 role 을 가지고 있는지 확인
+func (s *SmartContract) HasRole(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{access.FieldRole}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{access.FieldRole}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	role := args[access.FieldRole].(string)
+
+	checkBool, err := access.HasRole(ctx, role)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], checkBool)
+}
+
+func (s *SmartContract) GetRoleList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = access.GetRoleList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _grantAdminRole timelock 을 거친 admin role 부여
+func _grantAdminRole(ctx contractapi.TransactionContextInterface, actor string, subjectType string, subject string) error {
+
+	adminRole := access.RoleStruct{Role: access.RoleAdmin, SubjectType: subjectType, Subject: subject, GrantedBy: actor}
+	_, err := access.GrantRole(ctx, adminRole)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RoleGranted", From: actor, To: subject, Partition: "", Amount: 0}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+// _revokeAdminRole timelock 을 거친 admin role 회수
+func _revokeAdminRole(ctx contractapi.TransactionContextInterface, actor string, subjectType string, subject string) error {
+
+	err := access.RevokeRole(ctx, access.RoleAdmin, subjectType, subject)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RoleRevoked", From: actor, To: subject, Partition: "", Amount: 0}
+	return transferEvent.EmitTransferEvent(ctx)
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/ownership"
+)
+
+// 함수별 호출 가능한 role 목록 (admin 은 항상 허용)
+// 여기에 없는 함수는 인증서만 있으면 누구나 호출 가능
+var functionRoles = map[string][]string{
+	// access
+	"GrantRole":   {access.RoleAdmin},
+	"RevokeRole":  {access.RoleAdmin},
+	"GetRoleList": {access.RoleAuditor},
+
+	// multisig
+	"SetApprovalPolicy": {access.RoleAdmin},
+
+	// timelock
+	"SetTimelockDelay":      {access.RoleAdmin},
+	"ScheduleAction":        {access.RoleAdmin},
+	"CancelScheduledAction": {access.RoleAdmin},
+	// ExecuteScheduledAction 은 controller 에서 확인 (새 owner 는 예약한 AcceptOwnership 을 직접 실행)
+
+	// token
+	"IssueToken":      {access.RoleIssuer},
+	"UndoIssueToken":  {access.RoleIssuer},
+	"MintByPartition": {access.RoleIssuer},
+
+	// controller
+	"ControllerTransferByPartition": {access.RoleController},
+
+	// operator
+	"AuthorizeOperatorByPartition": {access.RoleIssuer},
+	"RevokeOperatorByPartition":    {access.RoleIssuer},
+	"DistributeToken":              {access.RoleIssuer, access.RoleOperator},
+	"AirDrop":                      {access.RoleIssuer, access.RoleOperator},
+
+	// wallet
+	"GetTokenWalletList": {access.RoleAuditor},
+	"GetAdminWallet":     {access.RoleAuditor},
+
+	// binding
+	"BindWallet":           {access.RoleCompliance},
+	"GetWalletBinding":     {access.RoleCompliance, access.RoleAuditor},
+	"GetWalletByIdentity":  {access.RoleCompliance, access.RoleAuditor},
+	"GetWalletBindingList": {access.RoleCompliance, access.RoleAuditor},
+
+	// recovery
+	"ApproveWalletRecovery": {access.RoleCompliance},
+	"RejectWalletRecovery":  {access.RoleCompliance},
+	"GetWalletRecoveryList": {access.RoleCompliance, access.RoleAuditor},
+
+	// pause
+	"Pause":   {access.RoleAdmin},
+	"Unpause": {access.RoleAdmin},
+
+	// kyc
+	"SetInvestor":           {access.RoleCompliance},
+	"GetInvestorList":       {access.RoleCompliance, access.RoleAuditor},
+	"SetPartitionAllowlist": {access.RoleCompliance},
+
+	// rules
+	"SetPartitionRules": {access.RoleIssuer, access.RoleCompliance},
+
+	// vesting
+	"CreateVestingSchedule":  {access.RoleIssuer},
+	"RevokeVesting":          {access.RoleIssuer},
+	"GetVestingScheduleList": {access.RoleIssuer, access.RoleAuditor},
+
+	// holders
+	"SetMaxHolders":  {access.RoleIssuer, access.RoleCompliance},
+	"RecountHolders": {access.RoleAdmin},
+
+	// limits
+	"SetInvestorLimits": {access.RoleIssuer, access.RoleCompliance},
+
+	// blocklist
+	"AddToBlocklist":         {access.RoleCompliance},
+	"RemoveFromBlocklist":    {access.RoleCompliance},
+	"GetBlocklist":           {access.RoleCompliance, access.RoleAuditor},
+	"GetBlocklistUpdateList": {access.RoleCompliance, access.RoleAuditor},
+
+	// pending transfer
+	"SetTransferApprovers": {access.RoleIssuer},
+
+	// snapshot
+	"CreateSnapshot": {access.RoleIssuer},
+
+	// dividend
+	"CreateDividend": {access.RoleIssuer},
+	"PushDividend":   {access.RoleIssuer, access.RoleOperator},
+	"CloseDividend":  {access.RoleIssuer},
+
+	// split
+	"ExecuteSplit": {access.RoleIssuer},
+
+	// governance
+	"CreateBallot": {access.RoleIssuer},
+
+	// offering
+	"CreateOffering":           {access.RoleIssuer},
+	"CancelOffering":           {access.RoleIssuer},
+	"WithdrawOfferingProceeds": {access.RoleIssuer},
+
+	// settlement
+	"SetSettlementConfig": {access.RoleIssuer},
+	"FundRedemption":      {access.RoleIssuer},
+
+	// orderbook
+	"MatchOrders": {access.RoleOperator},
+
+	// freeze
+	"Freeze":        {access.RoleCompliance},
+	"Unfreeze":      {access.RoleCompliance},
+	"GetFreezeList": {access.RoleCompliance, access.RoleAuditor},
+}
+
+// GetBeforeTransaction contractapi 가 모든 트랜잭션 실행 전에 호출하는 함수
+func (s *SmartContract) GetBeforeTransaction() interface{} {
+	return _checkFunctionRoles
+}
+
+func _checkFunctionRoles(ctx contractapi.TransactionContextInterface) error {
+
+	fn, _ := ctx.GetStub().GetFunctionAndParameters()
+
+	// config.Features 에서 꺼둔 함수는 역할과 무관하게 거부
+	err := ownership.CheckFeatureEnabled(ctx, _functionName(fn))
+	if err != nil {
+		return err
+	}
+
+	roles, exist := functionRoles[_functionName(fn)]
+	if !exist {
+		return nil
+	}
+
+	return access.CheckRole(ctx, roles...)
+}
+
+// "contract:function" 형태를 contractapi 와 같은 방식으로 함수 이름만 남김
+func _functionName(nsFcn string) string {
+
+	fn := nsFcn
+	if li := strings.LastIndex(nsFcn, ":"); li != -1 {
+		fn = nsFcn[li+1:]
+	}
+
+	if fn == "" {
+		return fn
+	}
+
+	fnRune := []rune(fn)
+	fnRune[0] = unicode.ToUpper(fnRune[0])
+
+	return string(fnRune)
+}
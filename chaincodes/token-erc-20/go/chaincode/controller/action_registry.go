@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/multisig"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/operator"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/ownership"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/recovery"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/timelock"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+)
+
+// 승인 후 실행이 가능한 privileged action 목록 (contract 함수 이름과 동일)
+const (
+	ActionMintByPartition               = "MintByPartition"
+	ActionControllerTransferByPartition = "ControllerTransferByPartition"
+	ActionAuthorizeOperatorByPartition  = "AuthorizeOperatorByPartition"
+	ActionRevokeOperatorByPartition     = "RevokeOperatorByPartition"
+	ActionUndoIssueToken                = "UndoIssueToken"
+	ActionRecoverWallet                 = "ApproveWalletRecovery"
+	// 승인 정책 변경은 대상 action 의 현재 정책으로 승인
+	ActionSetApprovalPolicy = "SetApprovalPolicy"
+
+	// timelock 을 거쳐서만 실행되는 action
+	ActionSetSupplyCap    = "SetSupplyCap"
+	ActionSetControllable = "SetControllable"
+	ActionRotateAdminKey  = "RotateAdminKey"
+	// admin role 직접 부여 / 회수 (GrantRole, RevokeRole 은 admin 을 거부)
+	ActionGrantAdminRole  = "GrantAdminRole"
+	ActionRevokeAdminRole = "RevokeAdminRole"
+	// AcceptOwnership 이 예약하며 새 owner 에게 admin role 이 넘어감
+	ActionAcceptOwnership = "AcceptOwnership"
+	// 기본 page size, timezone, feature 설정 교체
+	ActionSetContractConfig = "SetContractConfig"
+	// 지연 시간 단축 (늘리는 것은 SetTimelockDelay 로 즉시 가능)
+	ActionSetTimelockDelay = "SetTimelockDelay"
+)
+
+// actor 는 action 을 처음 요청한 주소
+type actionHandler func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error
+
+var actionHandlers = map[string]actionHandler{
+	ActionMintByPartition: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _mintByPartition(ctx, actor, args[token.FieldPartition].(string), int64(args[token.FieldAmount].(float64)))
+	},
+	ActionControllerTransferByPartition: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _controllerTransferByPartition(ctx, actor, args[token.FieldFrom].(string), args[token.FieldTo].(string), args[token.FieldPartition].(string), int64(args[token.FieldAmount].(float64)))
+	},
+	ActionAuthorizeOperatorByPartition: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _authorizeOperatorByPartition(ctx, args[operator.FieldOperator].(string), args[operator.FieldPartition].(string))
+	},
+	ActionRevokeOperatorByPartition: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _revokeOperatorByPartition(ctx, args[operator.FieldOperator].(string), args[operator.FieldPartition].(string))
+	},
+	ActionUndoIssueToken: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		_, err := _undoIssueToken(ctx, actor, args[token.FieldPartition].(string))
+		return err
+	},
+	ActionRecoverWallet: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _recoverWallet(ctx, actor, args[recovery.FieldRequestId].(string))
+	},
+	ActionSetApprovalPolicy: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _setApprovalPolicy(ctx, actor, args)
+	},
+	ActionSetSupplyCap: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _setSupplyCap(ctx, actor, args[token.FieldPartition].(string), int64(args[token.FieldSupplyCap].(float64)))
+	},
+	ActionSetControllable: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _setControllable(ctx, actor, args[token.FieldPartition].(string), args[token.FieldIsControllable].(bool))
+	},
+	ActionRotateAdminKey: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _rotateAdminKey(ctx, actor, args[access.FieldOldAdmin].(string), args[access.FieldNewAdmin].(string))
+	},
+	ActionGrantAdminRole: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _grantAdminRole(ctx, actor, args[access.FieldSubjectType].(string), args[access.FieldSubject].(string))
+	},
+	ActionRevokeAdminRole: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _revokeAdminRole(ctx, actor, args[access.FieldSubjectType].(string), args[access.FieldSubject].(string))
+	},
+	ActionAcceptOwnership: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _acceptOwnership(ctx, actor, args[ownership.FieldOwnerMSP].(string))
+	},
+	ActionSetContractConfig: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _setContractConfig(ctx, actor, args)
+	},
+	ActionSetTimelockDelay: func(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+		return _setTimelockDelay(ctx, actor, args[timelock.FieldCategory].(string), int64(args[timelock.FieldDelaySeconds].(float64)))
+	},
+}
+
+func _executeAction(ctx contractapi.TransactionContextInterface, actionType string, actor string, args map[string]interface{}) error {
+
+	handler, exist := actionHandlers[actionType]
+	if !exist {
+		return fmt.Errorf("unknown action type : %s", actionType)
+	}
+
+	return handler(ctx, actor, args)
+}
+
+// _proposeIfRequired 승인 정책이 있는 action 이면 바로 실행하지 않고 proposal 을 생성
+func _proposeIfRequired(ctx contractapi.TransactionContextInterface, actionType string, proposer string, args map[string]interface{}) (*multisig.ProposalStruct, error) {
+
+	policy, err := multisig.GetApprovalPolicy(ctx, actionType)
+	if err != nil {
+		return nil, err
+	}
+
+	if !multisig.IsApprovalRequired(policy) {
+		return nil, nil
+	}
+
+	return _propose(ctx, *policy, proposer, args)
+}
+
+// _propose policy 의 threshold / 승인 role 로 policy.ActionType 의 proposal 생성
+func _propose(ctx contractapi.TransactionContextInterface, policy multisig.ApprovalPolicyStruct, proposer string, args map[string]interface{}) (*multisig.ProposalStruct, error) {
+
+	mspId, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client msp id: %v", err)
+	}
+
+	proposal, err := multisig.CreateProposal(ctx, policy, proposer, mspId, args)
+	if err != nil {
+		return nil, err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ProposalCreated", From: proposer, To: "", Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return proposal, nil
+}
+
+func _proposalResponse(ctx contractapi.TransactionContextInterface, proposal *multisig.ProposalStruct) (*ccutils.Response, error) {
+
+	retData, err := ccutils.StructToMap(proposal)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeAccepted, ccutils.CodeMessage[ccutils.ChaincodeAccepted], retData)
+}
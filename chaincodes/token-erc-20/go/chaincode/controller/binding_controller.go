@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/binding"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// compliance 가 wallet 을 다른(재발급 받은) identity 에 바인딩
+func (s *SmartContract) BindWallet(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{binding.FieldAddress, binding.FieldMSPID, binding.FieldSubject, binding.FieldIssuer}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{binding.FieldAddress, binding.FieldMSPID, binding.FieldSubject, binding.FieldIssuer}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	address := args[binding.FieldAddress].(string)
+
+	// escrow 는 contract 만 움직일 수 있으므로 어떤 identity 에도 바인딩하지 않음
+	if wallet.IsEscrowAddress(address) {
+		return ccutils.GenerateErrorResponse(ccutils.CreateError(binding.CodeErrorEscrowAddress, fmt.Errorf(binding.ErrorCodeMessage[binding.CodeErrorEscrowAddress]+" : "+address)))
+	}
+
+	// wallet 존재 확인. 복구로 대체된 wallet 은 바인딩 불가
+	err = wallet.CheckNotSuperseded(ctx, address)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	walletBinding := binding.WalletBindingStruct{}
+	walletBinding.Address = address
+	walletBinding.MSPID = args[binding.FieldMSPID].(string)
+	walletBinding.Subject = args[binding.FieldSubject].(string)
+	walletBinding.Issuer = args[binding.FieldIssuer].(string)
+	walletBinding.BoundBy = ccutils.GetAddress([]byte(id))
+
+	newBinding, err := binding.Bind(ctx, walletBinding)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "WalletBound", From: walletBinding.BoundBy, To: address, Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newBinding)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// wallet 주소 → 인증서
+func (s *SmartContract) GetWalletBinding(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{binding.FieldAddress}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{binding.FieldAddress}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	walletBinding, err := binding.GetBindingByAddress(ctx, args[binding.FieldAddress].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if walletBinding == nil {
+		return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+	}
+
+	retData, err := ccutils.StructToMap(walletBinding)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 인증서 → wallet 주소
+func (s *SmartContract) GetWalletByIdentity(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{binding.FieldMSPID, binding.FieldSubject, binding.FieldIssuer}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{binding.FieldMSPID, binding.FieldSubject, binding.FieldIssuer}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	walletBinding, err := binding.GetBindingByIdentity(ctx, args[binding.FieldMSPID].(string), args[binding.FieldSubject].(string), args[binding.FieldIssuer].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if walletBinding == nil {
+		return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+	}
+
+	retData, err := ccutils.StructToMap(walletBinding)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetWalletBindingList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = binding.GetBindingList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _callerWallet 출금 경로에서 사용할 호출자의 wallet 주소.
+// 바인딩된 identity 면 바인딩된 wallet, 아니면 인증서에서 유도한 주소 (다른 identity 에 바인딩된 경우 거부)
+func _callerWallet(ctx contractapi.TransactionContextInterface) (string, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	mspId, subject, issuer, err := ccutils.GetX509Identity(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return binding.ResolveWallet(ctx, mspId, subject, issuer, ccutils.GetAddress([]byte(id)))
+}
@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/blocklist"
+)
+
+// 업로드한 목록의 주소를 일괄 차단. entries 예시
+// {"contentHash":"<sha256 of source list>","entries":[{"address":"A","reason":"OFAC SDN","expiresAt":0}]}
+func (s *SmartContract) AddToBlocklist(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{blocklist.FieldEntries, blocklist.FieldContentHash}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{blocklist.FieldContentHash}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	arrayParameterFields := []string{blocklist.FieldEntries}
+	err = ccutils.CheckRequireTypeArray(arrayParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	entries := []blocklist.BlockedAddressStruct{}
+	for _, value := range args[blocklist.FieldEntries].([]interface{}) {
+		entryArgs, ok := value.(map[string]interface{})
+		if !ok {
+			return ccutils.GenerateErrorResponse(fmt.Errorf("blocklist entry must be an object"))
+		}
+
+		err = ccutils.CheckRequireParameter([]string{blocklist.FieldAddress}, entryArgs)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = ccutils.CheckRequireTypeString([]string{blocklist.FieldAddress}, entryArgs)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = ccutils.CheckTypeString([]string{blocklist.FieldReason}, entryArgs)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = ccutils.CheckTypeInt64([]string{blocklist.FieldExpiresAt}, entryArgs)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		entry := blocklist.BlockedAddressStruct{Address: entryArgs[blocklist.FieldAddress].(string)}
+		if reason, exist := entryArgs[blocklist.FieldReason]; exist {
+			entry.Reason = reason.(string)
+		}
+		if expiresAt, exist := entryArgs[blocklist.FieldExpiresAt]; exist {
+			entry.ExpiresAt = int64(expiresAt.(float64))
+		}
+
+		entries = append(entries, entry)
+	}
+
+	actor := ccutils.GetAddress([]byte(id))
+
+	update, err := blocklist.AddEntries(ctx, entries, args[blocklist.FieldContentHash].(string), actor)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _blocklistUpdateResponse(ctx, update)
+}
+
+func (s *SmartContract) RemoveFromBlocklist(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{blocklist.FieldAddresses, blocklist.FieldContentHash}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{blocklist.FieldContentHash}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	addresses, err := _stringArrayArg(args, blocklist.FieldAddresses)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	actor := ccutils.GetAddress([]byte(id))
+
+	update, err := blocklist.RemoveEntries(ctx, addresses, args[blocklist.FieldContentHash].(string), actor)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _blocklistUpdateResponse(ctx, update)
+}
+
+// 온보딩 사전 확인용. 주소 목록 중 현재 차단 중인 주소와 사유 반환
+func (s *SmartContract) ScreenAddresses(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{blocklist.FieldAddresses}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	addresses, err := _stringArrayArg(args, blocklist.FieldAddresses)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	blocked, err := blocklist.Screen(ctx, addresses)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData := map[string]interface{}{blocklist.FieldBlocked: blocked}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetBlocklist(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = blocklist.GetEntryList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// 일괄 추가/삭제 이력 (원본 목록 hash 포함)
+func (s *SmartContract) GetBlocklistUpdateList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = blocklist.GetUpdateList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+func _blocklistUpdateResponse(ctx contractapi.TransactionContextInterface, update *blocklist.BlocklistUpdateStruct) (*ccutils.Response, error) {
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "BlocklistUpdated", From: update.UpdatedBy, To: update.ContentHash, Partition: update.Action, Amount: update.Count}
+	err := transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(update)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// _stringArrayArg 문자열 배열 파라미터 확인 후 []string 으로 변환
+func _stringArrayArg(args map[string]interface{}, field string) ([]string, error) {
+
+	err := ccutils.CheckRequireTypeArray([]string{field}, args)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{}
+	for _, value := range args[field].([]interface{}) {
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("check parameter type : parameter field = %v must be an array of string", field)
+		}
+		values = append(values, str)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,584 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/dividend"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/kyc"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/settlement"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/snapshot"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// 발행사가 cash partition 재원을 escrow 에 넣고 partition 의 snapshot 보유자 대상 배당 생성
+func (s *SmartContract) CreateDividend(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dividend.FieldPartition, dividend.FieldCashPartition, dividend.FieldSnapshotId, dividend.FieldTotalAmount}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dividend.FieldPartition, dividend.FieldCashPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{dividend.FieldSnapshotId, dividend.FieldTotalAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{dividend.FieldRoundingPolicy, dividend.FieldWithholdingAccount}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{dividend.FieldClaimDeadline}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckType(reflect.Map, []string{dividend.FieldWithholdingRates}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	issuer, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	distribution := dividend.DistributionStruct{}
+	distribution.Partition = args[dividend.FieldPartition].(string)
+	distribution.CashPartition = args[dividend.FieldCashPartition].(string)
+	distribution.SnapshotId = int64(args[dividend.FieldSnapshotId].(float64))
+	distribution.TotalAmount = int64(args[dividend.FieldTotalAmount].(float64))
+	distribution.Issuer = issuer
+	distribution.CreatedBy = issuer
+
+	if value, exist := args[dividend.FieldRoundingPolicy]; exist {
+		distribution.RoundingPolicy = value.(string)
+	}
+
+	if value, exist := args[dividend.FieldWithholdingAccount]; exist {
+		distribution.WithholdingAccount = value.(string)
+	}
+
+	if value, exist := args[dividend.FieldClaimDeadline]; exist {
+		distribution.ClaimDeadline = int64(value.(float64))
+	}
+
+	distribution.WithholdingRates = map[string]int64{}
+	if value, exist := args[dividend.FieldWithholdingRates]; exist {
+		for category, rate := range value.(map[string]interface{}) {
+			rateValue, ok := rate.(float64)
+			if !ok {
+				return ccutils.GenerateErrorResponse(fmt.Errorf("invalid withholding rate for category %s", category))
+			}
+			distribution.WithholdingRates[category] = int64(rateValue)
+		}
+	}
+
+	distribution.EligibleSupply, err = snapshot.TotalSupplyAt(ctx, distribution.Partition, distribution.SnapshotId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	newDistribution, err := dividend.CreateDistribution(ctx, distribution)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	escrow := wallet.EscrowAddress(dividend.EscrowName)
+	adapter, err := _settlementAdapter(ctx, newDistribution.Partition, newDistribution.CashPartition, dividend.EscrowName)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = adapter.Debit(ctx, issuer, newDistribution.TotalAmount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "DividendCreated", From: issuer, To: escrow, Partition: newDistribution.CashPartition, Amount: newDistribution.TotalAmount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newDistribution)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 보유자 본인의 배당 청구
+func (s *SmartContract) ClaimDividend(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dividend.FieldDistributionId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dividend.FieldDistributionId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	holder, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	distribution, err := dividend.GetDistribution(ctx, args[dividend.FieldDistributionId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	claims, err := _payDividends(ctx, distribution, []string{holder}, holder, false)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "DividendClaimed", From: wallet.EscrowAddress(dividend.EscrowName), To: holder, Partition: distribution.CashPartition, Amount: claims[0].Net}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(claims[0])
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 발행사/operator 의 일괄 지급. 이미 지급했거나 snapshot 잔고가 없는 보유자는 건너뜀
+func (s *SmartContract) PushDividend(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dividend.FieldDistributionId, dividend.FieldHolders}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dividend.FieldDistributionId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	holderList, err := _stringArrayArg(args, dividend.FieldHolders)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	paidBy, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	distribution, err := dividend.GetDistribution(ctx, args[dividend.FieldDistributionId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	claims, err := _payDividends(ctx, distribution, holderList, paidBy, true)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var paidNet int64
+	for _, claim := range claims {
+		paidNet += claim.Net
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "DividendPushed", From: wallet.EscrowAddress(dividend.EscrowName), To: "", Partition: distribution.CashPartition, Amount: paidNet}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], claims)
+}
+
+// 청구 기한 후 종료. 미청구분과 반올림 잔액은 발행사로 반환
+func (s *SmartContract) CloseDividend(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dividend.FieldDistributionId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dividend.FieldDistributionId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	closedBy, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	distribution, err := dividend.GetDistribution(ctx, args[dividend.FieldDistributionId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	distribution, err = dividend.CloseDistribution(ctx, *distribution, closedBy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	escrow := wallet.EscrowAddress(dividend.EscrowName)
+	if distribution.ReturnedAmount > 0 {
+		adapter, err := _settlementAdapter(ctx, distribution.Partition, distribution.CashPartition, dividend.EscrowName)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = adapter.Credit(ctx, []settlement.PaymentStruct{{To: distribution.Issuer, Amount: distribution.ReturnedAmount}})
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "DividendClosed", From: escrow, To: distribution.Issuer, Partition: distribution.CashPartition, Amount: distribution.ReturnedAmount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(distribution)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetDividend(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dividend.FieldDistributionId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dividend.FieldDistributionId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	distribution, err := dividend.GetDistribution(ctx, args[dividend.FieldDistributionId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(distribution)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetDividendList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = dividend.GetDistributionList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// 지급 기록. 아직 지급 전이면 현재 기준 예상 지급액과 claimed=false
+func (s *SmartContract) GetDividendClaim(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dividend.FieldDistributionId, dividend.FieldHolder}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dividend.FieldDistributionId, dividend.FieldHolder}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	distributionId := args[dividend.FieldDistributionId].(string)
+	holder := args[dividend.FieldHolder].(string)
+
+	claim, err := dividend.GetClaim(ctx, distributionId, holder)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	claimed := claim != nil
+	if !claimed {
+		distribution, err := dividend.GetDistribution(ctx, distributionId)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		preview, err := _allocateDividend(ctx, distribution, holder)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+		claim = &preview
+	}
+
+	retData, err := ccutils.StructToMap(claim)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+	retData[dividend.FieldClaimed] = claimed
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetDividendClaimList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = dividend.GetClaimList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _payDividends holders 의 지급액을 계산해 escrow 에서 한 번에 지급.
+// push 가 아니면 (본인 청구) 이미 지급했거나 지급액이 없을 때 에러
+func _payDividends(ctx contractapi.TransactionContextInterface, distribution *dividend.DistributionStruct, holderList []string, paidBy string, pushed bool) ([]dividend.ClaimStruct, error) {
+
+	err := dividend.CheckClaimable(ctx, *distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pause.CheckNotPaused(ctx, distribution.CashPartition, pause.OperationTransfer)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := []dividend.ClaimStruct{}
+	payees := []string{}
+	amounts := make(map[string]int64)
+	addPayment := func(payee string, amount int64) {
+		if amount <= 0 {
+			return
+		}
+		if _, exist := amounts[payee]; !exist {
+			payees = append(payees, payee)
+		}
+		amounts[payee] += amount
+	}
+
+	seen := make(map[string]bool)
+	for _, holder := range holderList {
+		if seen[holder] {
+			continue
+		}
+		seen[holder] = true
+
+		claim, err := dividend.GetClaim(ctx, distribution.DistributionId, holder)
+		if err != nil {
+			return nil, err
+		}
+
+		if claim != nil {
+			if pushed {
+				continue
+			}
+			return nil, ccutils.CreateError(dividend.CodeErrorAlreadyClaimed, fmt.Errorf(dividend.ErrorCodeMessage[dividend.CodeErrorAlreadyClaimed]+" : "+holder))
+		}
+
+		allocated, err := _allocateDividend(ctx, distribution, holder)
+		if err != nil {
+			return nil, err
+		}
+
+		if allocated.Gross == 0 {
+			if pushed {
+				continue
+			}
+			return nil, ccutils.CreateError(dividend.CodeErrorNothingToClaim, fmt.Errorf(dividend.ErrorCodeMessage[dividend.CodeErrorNothingToClaim]+" : "+holder))
+		}
+
+		// 키 분실로 복구된 wallet 의 몫은 새 wallet 으로 지급
+		payee, err := _activeWallet(ctx, holder)
+		if err != nil {
+			return nil, err
+		}
+
+		claims = append(claims, allocated)
+		addPayment(payee, allocated.Net)
+		addPayment(distribution.WithholdingAccount, allocated.Withheld)
+	}
+
+	adapter, err := _settlementAdapter(ctx, distribution.Partition, distribution.CashPartition, dividend.EscrowName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 수령인 KYC/한도 확인과 보유자 수 기록은 이 chaincode 의 cash partition 일 때만 의미가 있음
+	_, internal := adapter.(settlement.PartitionAdapter)
+
+	payments := []settlement.PaymentStruct{}
+	for _, payee := range payees {
+		if internal {
+			err = _checkCreditByPartition(ctx, payee, distribution.CashPartition, amounts[payee])
+			if err != nil {
+				return nil, err
+			}
+		}
+		payments = append(payments, settlement.PaymentStruct{To: payee, Amount: amounts[payee]})
+	}
+
+	err = adapter.Credit(ctx, payments)
+	if err != nil {
+		return nil, err
+	}
+
+	if internal {
+		for _, payment := range payments {
+			err = _recordCreditByPartition(ctx, payment.To, distribution.CashPartition, payment.Amount)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = dividend.RecordClaims(ctx, *distribution, claims, paidBy, pushed)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// _allocateDividend snapshot 잔고와 KYC category 로 holder 의 지급액 계산. distribution 합계도 갱신됨
+func _allocateDividend(ctx contractapi.TransactionContextInterface, distribution *dividend.DistributionStruct, holder string) (dividend.ClaimStruct, error) {
+
+	balance, err := snapshot.BalanceOfAt(ctx, holder, distribution.Partition, distribution.SnapshotId)
+	if err != nil {
+		return dividend.ClaimStruct{}, err
+	}
+
+	var category string
+	investor, err := kyc.GetInvestor(ctx, holder)
+	if err != nil {
+		return dividend.ClaimStruct{}, err
+	}
+	if investor != nil {
+		category = investor.Category
+	}
+
+	return distribution.Allocate(holder, balance, category), nil
+}
+
+// _activeWallet 복구로 대체된 wallet 이면 최종 대체 wallet 주소
+func _activeWallet(ctx contractapi.TransactionContextInterface, address string) (string, error) {
+
+	for {
+		holderWallet, err := wallet.GetWallet(ctx, address)
+		if err != nil {
+			return "", err
+		}
+
+		if holderWallet.SupersededBy == "" {
+			return address, nil
+		}
+		address = holderWallet.SupersededBy
+	}
+}
+
+// _fundEscrowByPartition from 의 출금 제한을 확인하고 escrow wallet 으로 이전.
+// escrow 는 수령인 KYC/한도 대상이 아니므로 _moveByPartition 을 거치지 않음
+func _fundEscrowByPartition(ctx contractapi.TransactionContextInterface, from string, escrow string, partition string, amount int64) error {
+
+	err := pause.CheckNotPaused(ctx, partition, pause.OperationTransfer)
+	if err != nil {
+		return err
+	}
+
+	err = _checkDebitByPartition(ctx, from, partition, amount)
+	if err != nil {
+		return err
+	}
+
+	transfers := []token.TransferByPartitionStruct{{From: from, To: escrow, Partition: partition, Amount: amount}}
+	return wallet.TransferBatchByPartition(ctx, from, partition, transfers)
+}
@@ -0,0 +1,399 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/dvp"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// 매도인 또는 매수인이 DvP 결제 생성. 양쪽 leg 는 각자 FundDvP 로 escrow
+func (s *SmartContract) CreateDvP(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dvp.FieldSeller, dvp.FieldBuyer, dvp.FieldPartition, dvp.FieldAmount, dvp.FieldCashPartition, dvp.FieldCashAmount, dvp.FieldExpiration}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dvp.FieldSeller, dvp.FieldBuyer, dvp.FieldPartition, dvp.FieldCashPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{dvp.FieldAmount, dvp.FieldCashAmount, dvp.FieldExpiration}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{dvp.FieldHashLock}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	settlement := dvp.DvPStruct{}
+	settlement.Seller = args[dvp.FieldSeller].(string)
+	settlement.Buyer = args[dvp.FieldBuyer].(string)
+	settlement.Partition = args[dvp.FieldPartition].(string)
+	settlement.Amount = int64(args[dvp.FieldAmount].(float64))
+	settlement.CashPartition = args[dvp.FieldCashPartition].(string)
+	settlement.CashAmount = int64(args[dvp.FieldCashAmount].(float64))
+	settlement.Expiration = int64(args[dvp.FieldExpiration].(float64))
+	settlement.CreatedBy = caller
+
+	if value, exist := args[dvp.FieldHashLock]; exist {
+		settlement.HashLock = value.(string)
+	}
+
+	if caller != settlement.Seller && caller != settlement.Buyer {
+		return ccutils.GenerateErrorResponse(ccutils.CreateError(dvp.CodeErrorNotParty, fmt.Errorf(dvp.ErrorCodeMessage[dvp.CodeErrorNotParty]+" : "+caller)))
+	}
+
+	// 실행 시점에 다시 확인하지만, 교환할 수 없는 결제는 미리 거부
+	err = _checkDvPLegs(ctx, settlement)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	created, err := dvp.CreateDvP(ctx, settlement)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "DvPCreated", From: created.Seller, To: created.Buyer, Partition: created.Partition, Amount: created.Amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(created)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 호출자 본인의 leg 를 escrow. 매도인은 증권, 매수인은 대금
+func (s *SmartContract) FundDvP(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dvp.FieldDvPId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dvp.FieldDvPId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	party, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	settlement, err := dvp.GetDvP(ctx, args[dvp.FieldDvPId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	funded, leg, err := dvp.FundLeg(ctx, *settlement, party)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	escrow := wallet.EscrowAddress(dvp.SecurityEscrowName)
+	partition, amount := funded.Partition, funded.Amount
+	if leg == dvp.LegCash {
+		escrow = wallet.EscrowAddress(dvp.CashEscrowName)
+		partition, amount = funded.CashPartition, funded.CashAmount
+	}
+
+	err = _fundEscrowByPartition(ctx, party, escrow, partition, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "DvPFunded", From: party, To: escrow, Partition: partition, Amount: amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(funded)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 양쪽 leg 가 모두 escrow 된 뒤 누구나 호출해 한 번에 교환. HTLC 모드면 secret 필요.
+// 두번째 leg 의 funding 과 같은 tx 에서 실행하면 그 당사자 wallet 을 두 번 쓰게 되므로 별도 호출
+func (s *SmartContract) ExecuteDvP(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dvp.FieldDvPId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dvp.FieldDvPId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{dvp.FieldSecret}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	executedBy, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	settlement, err := dvp.GetDvP(ctx, args[dvp.FieldDvPId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var secret string
+	if value, exist := args[dvp.FieldSecret]; exist {
+		secret = value.(string)
+	}
+
+	executed, err := dvp.Execute(ctx, *settlement, secret, executedBy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkDvPLegs(ctx, *executed)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 복구로 대체된 wallet 으로 보내면 토큰이 묶이게 됨
+	for _, party := range []string{executed.Seller, executed.Buyer} {
+		err = wallet.CheckNotSuperseded(ctx, party)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	err = _releaseDvPLeg(ctx, dvp.SecurityEscrowName, executed.Buyer, executed.Partition, executed.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _releaseDvPLeg(ctx, dvp.CashEscrowName, executed.Seller, executed.CashPartition, executed.CashAmount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _recordCreditByPartition(ctx, executed.Buyer, executed.Partition, executed.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _recordCreditByPartition(ctx, executed.Seller, executed.CashPartition, executed.CashAmount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "DvPExecuted", From: executed.Seller, To: executed.Buyer, Partition: executed.Partition, Amount: executed.Amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(executed)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 당사자의 취소 (양쪽 leg 가 모두 escrow 되기 전) 또는 만료 후 누구나 환불. escrow 된 leg 는 원래 당사자에게 반환
+func (s *SmartContract) CancelDvP(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dvp.FieldDvPId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dvp.FieldDvPId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	cancelledBy, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	settlement, err := dvp.GetDvP(ctx, args[dvp.FieldDvPId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	cancelled, err := dvp.Cancel(ctx, *settlement, cancelledBy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 키 분실로 복구된 당사자에게는 새 wallet 으로 환불
+	if cancelled.SellerFunded {
+		seller, err := _activeWallet(ctx, cancelled.Seller)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = _releaseDvPLeg(ctx, dvp.SecurityEscrowName, seller, cancelled.Partition, cancelled.Amount)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	if cancelled.BuyerFunded {
+		buyer, err := _activeWallet(ctx, cancelled.Buyer)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = _releaseDvPLeg(ctx, dvp.CashEscrowName, buyer, cancelled.CashPartition, cancelled.CashAmount)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "DvPCancelled", From: cancelled.Seller, To: cancelled.Buyer, Partition: cancelled.Partition, Amount: cancelled.Amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(cancelled)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetDvP(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{dvp.FieldDvPId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{dvp.FieldDvPId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	settlement, err := dvp.GetDvP(ctx, args[dvp.FieldDvPId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(settlement)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetDvPList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = dvp.GetDvPList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _checkDvPLegs 증권 leg (매도인 → 매수인) 와 대금 leg (매수인 → 매도인) 의 수령/rule 확인
+func _checkDvPLegs(ctx contractapi.TransactionContextInterface, settlement dvp.DvPStruct) error {
+
+	err := _checkCreditByPartition(ctx, settlement.Buyer, settlement.Partition, settlement.Amount)
+	if err != nil {
+		return err
+	}
+
+	err = _checkRules(ctx, rules.OperationTransfer, settlement.Partition, settlement.Seller, settlement.Buyer, settlement.Amount)
+	if err != nil {
+		return err
+	}
+
+	err = _checkCreditByPartition(ctx, settlement.Seller, settlement.CashPartition, settlement.CashAmount)
+	if err != nil {
+		return err
+	}
+
+	return _checkRules(ctx, rules.OperationTransfer, settlement.CashPartition, settlement.Buyer, settlement.Seller, settlement.CashAmount)
+}
+
+// _releaseDvPLeg escrow 에 있는 leg 를 to 에게 이전 (교환 또는 환불)
+func _releaseDvPLeg(ctx contractapi.TransactionContextInterface, escrowName string, to string, partition string, amount int64) error {
+
+	err := pause.CheckNotPaused(ctx, partition, pause.OperationTransfer)
+	if err != nil {
+		return err
+	}
+
+	escrow := wallet.EscrowAddress(escrowName)
+	transfers := []token.TransferByPartitionStruct{{From: escrow, To: to, Partition: partition, Amount: amount}}
+	return wallet.TransferBatchByPartition(ctx, escrow, partition, transfers)
+}
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/freeze"
+)
+
+// wallet 전체(partition 생략) 혹은 (wallet, partition) 동결. amount 가 있으면 해당 수량만 동결
+func (s *SmartContract) Freeze(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{freeze.FieldAddress, freeze.FieldReason}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{freeze.FieldAddress, freeze.FieldReason}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{freeze.FieldPartition, freeze.FieldReferenceDoc}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{freeze.FieldAmount}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	freezeStruct := freeze.FreezeStruct{}
+	freezeStruct.Address = args[freeze.FieldAddress].(string)
+	freezeStruct.Reason = args[freeze.FieldReason].(string)
+	freezeStruct.FrozenBy = ccutils.GetAddress([]byte(id))
+
+	if value, exist := args[freeze.FieldPartition]; exist {
+		freezeStruct.Partition = value.(string)
+	}
+
+	if value, exist := args[freeze.FieldReferenceDoc]; exist {
+		freezeStruct.ReferenceDoc = value.(string)
+	}
+
+	if value, exist := args[freeze.FieldAmount]; exist {
+		freezeStruct.Amount = int64(value.(float64))
+	}
+
+	newFreeze, err := freeze.Freeze(ctx, freezeStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Frozen", From: freezeStruct.FrozenBy, To: freezeStruct.Address, Partition: freezeStruct.Partition, Amount: freezeStruct.Amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newFreeze)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) Unfreeze(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{freeze.FieldAddress}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{freeze.FieldAddress}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{freeze.FieldPartition}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	address := args[freeze.FieldAddress].(string)
+	partition := freeze.PartitionAll
+	if value, exist := args[freeze.FieldPartition]; exist {
+		partition = value.(string)
+	}
+
+	err = freeze.Unfreeze(ctx, address, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Unfrozen", From: ccutils.GetAddress([]byte(id)), To: address, Partition: partition, Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+}
+
+func (s *SmartContract) GetFreeze(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{freeze.FieldAddress}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{freeze.FieldAddress}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{freeze.FieldPartition}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := freeze.PartitionAll
+	if value, exist := args[freeze.FieldPartition]; exist {
+		partition = value.(string)
+	}
+
+	freezeStruct, err := freeze.GetFreeze(ctx, args[freeze.FieldAddress].(string), partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if freezeStruct == nil {
+		return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+	}
+
+	retData, err := ccutils.StructToMap(freezeStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 현재 유효한 동결 목록 (사유, 근거 문서 포함)
+func (s *SmartContract) GetFreezeList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = freeze.GetFreezeList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
@@ -0,0 +1,547 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/governance"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/operator"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/snapshot"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+)
+
+// partition 보유자 투표 생성. snapshotId 가 없으면 지금 snapshot 을 선언해 기준일로 사용
+func (s *SmartContract) CreateBallot(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{governance.FieldPartition, governance.FieldTitle, governance.FieldOptions, governance.FieldEndAt}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{governance.FieldPartition, governance.FieldTitle}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{governance.FieldEndAt}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{governance.FieldDescription}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{governance.FieldSnapshotId, governance.FieldStartAt, governance.FieldQuorum, governance.FieldThreshold}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	options, err := _stringArrayArg(args, governance.FieldOptions)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	issuer, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	ballot := governance.BallotStruct{}
+	ballot.Partition = args[governance.FieldPartition].(string)
+	ballot.Title = args[governance.FieldTitle].(string)
+	ballot.Options = options
+	ballot.EndAt = int64(args[governance.FieldEndAt].(float64))
+	ballot.CreatedBy = issuer
+
+	if value, exist := args[governance.FieldDescription]; exist {
+		ballot.Description = value.(string)
+	}
+
+	if value, exist := args[governance.FieldStartAt]; exist {
+		ballot.StartAt = int64(value.(float64))
+	}
+
+	if value, exist := args[governance.FieldQuorum]; exist {
+		ballot.Quorum = int64(value.(float64))
+	}
+
+	if value, exist := args[governance.FieldThreshold]; exist {
+		ballot.Threshold = int64(value.(float64))
+	}
+
+	_, err = token.GetPartitionToken(ctx, ballot.Partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if value, exist := args[governance.FieldSnapshotId]; exist {
+		ballot.SnapshotId = int64(value.(float64))
+
+		ballot.EligibleSupply, err = snapshot.TotalSupplyAt(ctx, ballot.Partition, ballot.SnapshotId)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	} else {
+		newSnapshot, err := snapshot.CreateSnapshot(ctx, ballot.Partition, "ballot : "+ballot.Title, issuer)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+		ballot.SnapshotId = newSnapshot.SnapshotId
+
+		// 같은 tx 에서 선언한 snapshot 은 아직 조회되지 않으므로 현재 총발행량 사용
+		totalSupplyByPartition, err := token.TotalSupplyByPartition(ctx, ballot.Partition)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+		ballot.EligibleSupply = totalSupplyByPartition.TotalSupply
+	}
+
+	newBallot, err := governance.CreateBallot(ctx, ballot)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "BallotCreated", From: issuer, To: "", Partition: newBallot.Partition, Amount: newBallot.EligibleSupply}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newBallot)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 투표. holders 가 없으면 본인 몫(복구로 합쳐진 이전 wallet 몫 포함)만, 있으면 위임받은 holder 나 (partition operator 인 경우) 자신을 지정한 holder 몫을 함께 행사
+func (s *SmartContract) CastVote(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{governance.FieldBallotId, governance.FieldOption}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{governance.FieldBallotId, governance.FieldOption}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	ballot, err := governance.GetBallot(ctx, args[governance.FieldBallotId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = governance.CheckVotingOpen(ctx, *ballot)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	holderList := []string{}
+	if _, exist := args[governance.FieldHolders]; exist {
+		holderList, err = _stringArrayArg(args, governance.FieldHolders)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	} else {
+		holderList, err = _ownVoteHolders(ctx, *ballot, caller)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	option := args[governance.FieldOption].(string)
+
+	votes := []governance.VoteStruct{}
+	for _, holder := range holderList {
+		err = _checkVoteAuthority(ctx, ballot.Partition, caller, holder)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		weight, err := snapshot.BalanceOfAt(ctx, holder, ballot.Partition, ballot.SnapshotId)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		votes = append(votes, governance.VoteStruct{Holder: holder, Option: option, Weight: weight, CastBy: caller})
+	}
+
+	updatedBallot, err := governance.RecordVotes(ctx, *ballot, votes)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var totalWeight int64
+	for _, vote := range votes {
+		totalWeight += vote.Weight
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "VoteCast", From: caller, To: "", Partition: updatedBallot.Partition, Amount: totalWeight}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(updatedBallot)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// partition 의결권 위임. delegatee 를 빈 문자열로 보내면 위임 해제
+func (s *SmartContract) DelegateVote(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{governance.FieldPartition, governance.FieldDelegatee}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{governance.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{governance.FieldDelegatee}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	delegator, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	delegation, err := governance.SetDelegation(ctx, args[governance.FieldPartition].(string), delegator, args[governance.FieldDelegatee].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "VoteDelegated", From: delegator, To: delegation.Delegatee, Partition: delegation.Partition, Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(delegation)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// holder 가 partition operator 에게 자기 몫의 투표를 맡김. operator 를 빈 문자열로 보내면 해제
+func (s *SmartContract) AuthorizeVotingOperator(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{governance.FieldPartition, governance.FieldOperator}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{governance.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{governance.FieldOperator}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	holder, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	votingOperator, err := governance.SetVotingOperator(ctx, args[governance.FieldPartition].(string), holder, args[governance.FieldOperator].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "VotingOperatorAuthorized", From: holder, To: votingOperator.Operator, Partition: votingOperator.Partition, Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(votingOperator)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 투표 기간 후 결과 확정. 누구나 호출 가능
+func (s *SmartContract) FinalizeBallot(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{governance.FieldBallotId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{governance.FieldBallotId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	ballot, err := governance.GetBallot(ctx, args[governance.FieldBallotId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	finalized, err := governance.FinalizeBallot(ctx, *ballot, caller)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "BallotFinalized", From: "", To: "", Partition: finalized.Partition, Amount: finalized.TotalVotes}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(finalized)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 현재 집계와 (아직 확정 전이어도) quorum / threshold 적용 시 예상 결과
+func (s *SmartContract) GetBallot(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{governance.FieldBallotId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{governance.FieldBallotId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	ballot, err := governance.GetBallot(ctx, args[governance.FieldBallotId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(ballot)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if ballot.Status == governance.StatusActive {
+		status, winningOption := ballot.Result()
+		retData["projectedStatus"] = status
+		retData["projectedWinningOption"] = winningOption
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetBallotList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	return _governanceList(ctx, args, governance.GetBallotList)
+}
+
+func (s *SmartContract) GetVote(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{governance.FieldBallotId, governance.FieldHolder}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{governance.FieldBallotId, governance.FieldHolder}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	vote, err := governance.GetVote(ctx, args[governance.FieldBallotId].(string), args[governance.FieldHolder].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if vote == nil {
+		return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+	}
+
+	retData, err := ccutils.StructToMap(vote)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetVoteList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	return _governanceList(ctx, args, governance.GetVoteList)
+}
+
+func (s *SmartContract) GetVoteDelegationList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	return _governanceList(ctx, args, governance.GetDelegationList)
+}
+
+// _ownVoteHolders snapshot 잔고가 있는 caller 본인과, 복구로 caller 에 합쳐진 이전 wallet 중 아직 투표하지 않았고 snapshot 잔고가 있는 wallet
+func _ownVoteHolders(ctx contractapi.TransactionContextInterface, ballot governance.BallotStruct, caller string) ([]string, error) {
+
+	_, recoveredFrom, err := _recoveryChain(ctx, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recoveredFrom) == 0 {
+		return []string{caller}, nil
+	}
+
+	holderList := []string{}
+
+	weight, err := snapshot.BalanceOfAt(ctx, caller, ballot.Partition, ballot.SnapshotId)
+	if err != nil {
+		return nil, err
+	}
+
+	if weight > 0 {
+		holderList = append(holderList, caller)
+	}
+
+	for _, previous := range recoveredFrom {
+		vote, err := governance.GetVote(ctx, ballot.BallotId, previous)
+		if err != nil {
+			return nil, err
+		}
+
+		if vote != nil {
+			continue
+		}
+
+		weight, err := snapshot.BalanceOfAt(ctx, previous, ballot.Partition, ballot.SnapshotId)
+		if err != nil {
+			return nil, err
+		}
+
+		if weight > 0 {
+			holderList = append(holderList, previous)
+		}
+	}
+
+	// 행사할 몫이 없으면 본인 몫으로 기록해 RecordVotes 가 에러를 돌려주도록 함
+	if len(holderList) == 0 {
+		holderList = append(holderList, caller)
+	}
+
+	return holderList, nil
+}
+
+// holder 몫을 caller 가 행사할 수 있는지. 위임했으면 delegatee 만, 아니면 본인이나 holder 가 지정한 partition operator.
+// 복구로 대체된 holder 의 몫은 최종 대체 wallet 기준으로 확인
+func _checkVoteAuthority(ctx contractapi.TransactionContextInterface, partition string, caller string, holder string) error {
+
+	holder, _, err := _recoveryChain(ctx, holder)
+	if err != nil {
+		return err
+	}
+
+	delegation, err := governance.GetDelegation(ctx, partition, holder)
+	if err != nil {
+		return err
+	}
+
+	if delegation != nil {
+		if delegation.Delegatee == caller {
+			return nil
+		}
+		return ccutils.CreateError(governance.CodeErrorNotAuthorizedToVote, fmt.Errorf(governance.ErrorCodeMessage[governance.CodeErrorNotAuthorizedToVote]+" : "+holder))
+	}
+
+	if holder == caller {
+		return nil
+	}
+
+	votingOperator, err := governance.GetVotingOperator(ctx, partition, holder)
+	if err != nil {
+		return err
+	}
+
+	if votingOperator != nil && votingOperator.Operator == caller {
+		// partition operator 데이터가 없으면 에러를 돌려주므로 operator 가 아닌 것으로 처리
+		isOperator, err := operator.IsOperatorByPartition(ctx, caller, partition)
+		if err == nil && isOperator {
+			return nil
+		}
+	}
+
+	return ccutils.CreateError(governance.CodeErrorNotAuthorizedToVote, fmt.Errorf(governance.ErrorCodeMessage[governance.CodeErrorNotAuthorizedToVote]+" : "+holder))
+}
+
+func _governanceList(ctx contractapi.TransactionContextInterface, args map[string]interface{}, list func(map[string]interface{}, int32, string, contractapi.TransactionContextInterface) ([]byte, error)) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	bytes, err := list(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/hold"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/operator"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+)
+
+// 보유자 또는 partition operator 가 recipient 앞으로 잔고를 묶어 둠. 실행/해제/만료 전까지 출금 불가
+func (s *SmartContract) HoldByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{hold.FieldPartition, hold.FieldRecipient, hold.FieldNotary, hold.FieldAmount}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{hold.FieldPartition, hold.FieldRecipient, hold.FieldNotary}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{hold.FieldAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{hold.FieldHolder, hold.FieldSecretHash}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{hold.FieldExpiration}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	newHold := hold.HoldStruct{}
+	newHold.Partition = args[hold.FieldPartition].(string)
+	newHold.Recipient = args[hold.FieldRecipient].(string)
+	newHold.Notary = args[hold.FieldNotary].(string)
+	newHold.Amount = int64(args[hold.FieldAmount].(float64))
+	newHold.Holder = caller
+	newHold.CreatedBy = caller
+
+	if value, exist := args[hold.FieldHolder]; exist {
+		newHold.Holder = value.(string)
+	}
+
+	if value, exist := args[hold.FieldExpiration]; exist {
+		newHold.Expiration = int64(value.(float64))
+	}
+
+	if value, exist := args[hold.FieldSecretHash]; exist {
+		newHold.SecretHash = value.(string)
+	}
+
+	// 다른 보유자의 잔고는 partition operator 만 묶을 수 있음
+	if newHold.Holder != caller {
+		isOperator, err := operator.IsOperatorByPartition(ctx, caller, newHold.Partition)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		if !isOperator {
+			return ccutils.GenerateErrorResponse(fmt.Errorf("%s is not an operator of partition %s", caller, newHold.Partition))
+		}
+	}
+
+	// 실행 시점에 다시 확인하지만, 통과할 수 없는 hold 는 미리 거부
+	err = _checkDebitByPartition(ctx, newHold.Holder, newHold.Partition, newHold.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkCreditByPartition(ctx, newHold.Recipient, newHold.Partition, newHold.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkRules(ctx, rules.OperationTransfer, newHold.Partition, newHold.Holder, newHold.Recipient, newHold.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	created, err := hold.CreateHold(ctx, newHold)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _holdResponse(ctx, created, "HoldCreated", created.Amount)
+}
+
+// notary 가 만료 전에 hold 를 실행. amount 를 생략하면 남은 수량 전부
+func (s *SmartContract) ExecuteHold(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{hold.FieldHoldId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{hold.FieldHoldId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{hold.FieldSecret}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{hold.FieldAmount}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	notary, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	targetHold, err := hold.GetHold(ctx, args[hold.FieldHoldId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	amount := targetHold.Remaining()
+	if value, exist := args[hold.FieldAmount]; exist {
+		amount = int64(value.(float64))
+	}
+
+	var secret string
+	if value, exist := args[hold.FieldSecret]; exist {
+		secret = value.(string)
+	}
+
+	executed, err := hold.ExecuteHold(ctx, *targetHold, amount, secret, notary)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 같은 tx 에서 갱신한 hold 기록은 다시 읽히지 않으므로 자기 hold 를 빼고,
+	// 부분 실행 후에도 묶여 있을 남은 수량까지 포함해 확인
+	err = _checkDebitExceptHold(ctx, executed.Holder, executed.Partition, amount+executed.Remaining(), executed.HoldId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkRules(ctx, rules.OperationTransfer, executed.Partition, executed.Holder, executed.Recipient, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _moveByPartition(ctx, executed.Holder, executed.Recipient, executed.Partition, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _holdResponse(ctx, executed, "HoldExecuted", amount)
+}
+
+// notary 는 언제든, 그 외에는 만료 후에 hold 해제
+func (s *SmartContract) ReleaseHold(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{hold.FieldHoldId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{hold.FieldHoldId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	targetHold, err := hold.GetHold(ctx, args[hold.FieldHoldId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	released, err := hold.ReleaseHold(ctx, *targetHold, caller)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _holdResponse(ctx, released, "HoldReleased", targetHold.Remaining())
+}
+
+func (s *SmartContract) GetHold(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{hold.FieldHoldId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{hold.FieldHoldId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	targetHold, err := hold.GetHold(ctx, args[hold.FieldHoldId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(targetHold)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// holder, notary, recipient, partition, status 로 필터
+func (s *SmartContract) GetHoldList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = hold.GetHoldList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+func _holdResponse(ctx contractapi.TransactionContextInterface, targetHold *hold.HoldStruct, eventType string, amount int64) (*ccutils.Response, error) {
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: eventType, From: targetHold.Holder, To: targetHold.Recipient, Partition: targetHold.Partition, Amount: amount}
+	err := transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(targetHold)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/kyc"
+)
+
+// 투자자 KYC 정보 등록/갱신. 해지는 status 를 revoked 로 갱신
+func (s *SmartContract) SetInvestor(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{kyc.FieldAddress, kyc.FieldStatus, kyc.FieldLevel, kyc.FieldCountryCode, kyc.FieldCategory}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{kyc.FieldAddress, kyc.FieldStatus, kyc.FieldCountryCode, kyc.FieldCategory}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{kyc.FieldLevel}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 0 또는 생략 시 만료 없음
+	err = ccutils.CheckTypeInt64([]string{kyc.FieldExpiresAt}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	investor := kyc.InvestorStruct{}
+	investor.Address = args[kyc.FieldAddress].(string)
+	investor.Status = args[kyc.FieldStatus].(string)
+	investor.Level = int64(args[kyc.FieldLevel].(float64))
+	investor.CountryCode = args[kyc.FieldCountryCode].(string)
+	investor.Category = args[kyc.FieldCategory].(string)
+	investor.UpdatedBy = ccutils.GetAddress([]byte(id))
+
+	if value, exist := args[kyc.FieldExpiresAt]; exist {
+		investor.ExpiresAt = int64(value.(float64))
+	}
+
+	newInvestor, err := kyc.SetInvestor(ctx, investor)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newInvestor)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetInvestor(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{kyc.FieldAddress}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{kyc.FieldAddress}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	investor, err := kyc.GetInvestor(ctx, args[kyc.FieldAddress].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if investor == nil {
+		return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+	}
+
+	retData, err := ccutils.StructToMap(investor)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// status, countryCode, category 로 필터링 가능
+func (s *SmartContract) GetInvestorList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = kyc.GetInvestorList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// partition 의 수령인 allowlist 적용 여부와 최소 검증 레벨 설정
+func (s *SmartContract) SetPartitionAllowlist(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{kyc.FieldPartition, kyc.FieldAllowlistRequired}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{kyc.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	boolParameterFields := []string{kyc.FieldAllowlistRequired}
+	err = ccutils.CheckRequireTypeBool(boolParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{kyc.FieldMinLevel}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partitionKyc := kyc.PartitionKycStruct{}
+	partitionKyc.Partition = args[kyc.FieldPartition].(string)
+	partitionKyc.AllowlistRequired = args[kyc.FieldAllowlistRequired].(bool)
+
+	if value, exist := args[kyc.FieldMinLevel]; exist {
+		partitionKyc.MinLevel = int64(value.(float64))
+	}
+
+	newPartitionKyc, err := kyc.SetPartitionKyc(ctx, partitionKyc)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newPartitionKyc)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetPartitionAllowlist(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{kyc.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{kyc.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partitionKyc, err := kyc.GetPartitionKyc(ctx, args[kyc.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(partitionKyc)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 수령인 주소가 partition 의 allowlist 조건을 만족하는지 미리 확인
+func (s *SmartContract) CheckInvestor(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{kyc.FieldAddress, kyc.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{kyc.FieldAddress, kyc.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = kyc.CheckRecipient(ctx, args[kyc.FieldAddress].(string), args[kyc.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/limits"
+)
+
+// partition 의 투자자 유형별 보유 한도와 연간 취득 한도 설정. 0 이면 무제한
+func (s *SmartContract) SetInvestorLimits(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{limits.FieldPartition, limits.FieldCategory}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{limits.FieldPartition, limits.FieldCategory}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{limits.FieldHoldingLimit, limits.FieldAnnualLimit}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	limit := limits.CategoryLimitStruct{}
+	if value, exist := args[limits.FieldHoldingLimit]; exist {
+		limit.HoldingLimit = int64(value.(float64))
+	}
+
+	if value, exist := args[limits.FieldAnnualLimit]; exist {
+		limit.AnnualLimit = int64(value.(float64))
+	}
+
+	partitionLimits, err := limits.SetCategoryLimit(ctx, args[limits.FieldPartition].(string), args[limits.FieldCategory].(string), limit, ccutils.GetAddress([]byte(id)))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(partitionLimits)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetInvestorLimits(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{limits.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{limits.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partitionLimits, err := limits.GetLimits(ctx, args[limits.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(partitionLimits)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 최근 1년 취득 이력과 합계
+func (s *SmartContract) GetAcquisitions(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{limits.FieldHolder, limits.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{limits.FieldHolder, limits.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	record, err := limits.GetAcquisitionRecord(ctx, args[limits.FieldHolder].(string), args[limits.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(record)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+	retData[limits.FieldAcquiredInYear] = record.AcquiredSince(now - limits.RollingYearSeconds)
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
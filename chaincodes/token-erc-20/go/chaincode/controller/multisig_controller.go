@@ -0,0 +1,361 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/multisig"
+)
+
+// action type 별 M-of-N 승인 정책 설정. threshold 0 이면 단독 실행.
+// 대상 action 에 승인 정책이 있으면(없으면 SetApprovalPolicy 의 정책) 그 정책의 승인을 받아야 변경됨
+func (s *SmartContract) SetApprovalPolicy(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{multisig.FieldActionType, multisig.FieldThreshold, multisig.FieldApproverRoles}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	policy, err := _parseApprovalPolicy(args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if _, exist := actionHandlers[policy.ActionType]; !exist {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("unknown action type : %s", policy.ActionType))
+	}
+
+	governing, err := multisig.GetApprovalPolicy(ctx, policy.ActionType)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if !multisig.IsApprovalRequired(governing) {
+		governing, err = multisig.GetApprovalPolicy(ctx, ActionSetApprovalPolicy)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	proposer := ccutils.GetAddress([]byte(id))
+
+	if multisig.IsApprovalRequired(governing) {
+		governing.ActionType = ActionSetApprovalPolicy
+
+		proposal, err := _propose(ctx, *governing, proposer, args)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		return _proposalResponse(ctx, proposal)
+	}
+
+	newPolicy, err := multisig.SetApprovalPolicy(ctx, *policy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newPolicy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetApprovalPolicy(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{multisig.FieldActionType}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{multisig.FieldActionType}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	policy, err := multisig.GetApprovalPolicy(ctx, args[multisig.FieldActionType].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if policy == nil {
+		return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+	}
+
+	retData, err := ccutils.StructToMap(policy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 승인 추가. threshold 에 도달하면 proposer 의 권한으로 action 을 바로 실행
+func (s *SmartContract) ApproveProposal(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mspId, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client msp id: %v", err)
+	}
+
+	requireParameterFields := []string{multisig.FieldProposalId}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{multisig.FieldProposalId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	approver := ccutils.GetAddress([]byte(id))
+	proposalId := args[multisig.FieldProposalId].(string)
+
+	proposal, err := multisig.GetProposal(ctx, proposalId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = access.CheckRole(ctx, proposal.ApproverRoles...)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	proposal, reached, err := multisig.ApproveProposal(ctx, proposalId, approver, mspId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if reached {
+		err = _executeAction(ctx, proposal.ActionType, proposal.Proposer, proposal.Args)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		proposal, err = multisig.CloseProposal(ctx, *proposal, multisig.StatusExecuted, approver)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	} else {
+		transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ProposalApproved", From: approver, To: proposal.Proposer, Partition: "", Amount: int64(len(proposal.Approvals))}
+		err = transferEvent.EmitTransferEvent(ctx)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	retData, err := ccutils.StructToMap(proposal)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// proposer 혹은 admin 만 취소 가능
+func (s *SmartContract) CancelProposal(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{multisig.FieldProposalId}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{multisig.FieldProposalId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller := ccutils.GetAddress([]byte(id))
+	proposalId := args[multisig.FieldProposalId].(string)
+
+	proposal, err := multisig.GetProposal(ctx, proposalId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if proposal.Proposer != caller {
+		err = access.CheckRole(ctx, access.RoleAdmin)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	proposal, err = multisig.CloseProposal(ctx, *proposal, multisig.StatusCancelled, caller)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ProposalCancelled", From: caller, To: proposal.Proposer, Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(proposal)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetProposal(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{multisig.FieldProposalId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{multisig.FieldProposalId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	proposal, err := multisig.GetProposal(ctx, args[multisig.FieldProposalId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(proposal)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetProposalList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = multisig.GetProposalList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _setApprovalPolicy 승인된 정책 변경 실행
+func _setApprovalPolicy(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+
+	policy, err := _parseApprovalPolicy(args)
+	if err != nil {
+		return err
+	}
+
+	_, err = multisig.SetApprovalPolicy(ctx, *policy)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ApprovalPolicyChanged", From: actor, To: policy.ActionType, Partition: "", Amount: policy.Threshold}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+func _parseApprovalPolicy(args map[string]interface{}) (*multisig.ApprovalPolicyStruct, error) {
+
+	stringParameterFields := []string{multisig.FieldActionType}
+	err := ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return nil, err
+	}
+
+	int64ParameterFields := []string{multisig.FieldThreshold}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ccutils.CheckTypeInt64([]string{multisig.FieldExpirySeconds}, args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ccutils.CheckRequireTypeArray([]string{multisig.FieldApproverRoles}, args)
+	if err != nil {
+		return nil, err
+	}
+
+	approverRoles := []string{}
+	for _, value := range args[multisig.FieldApproverRoles].([]interface{}) {
+		role, ok := value.(string)
+		if !ok || !access.IsValidRole(role) {
+			return nil, fmt.Errorf("unknown role : %v", value)
+		}
+		approverRoles = append(approverRoles, role)
+	}
+
+	policy := multisig.ApprovalPolicyStruct{}
+	policy.ActionType = args[multisig.FieldActionType].(string)
+	policy.Threshold = int64(args[multisig.FieldThreshold].(float64))
+	policy.ApproverRoles = approverRoles
+
+	if value, exist := args[multisig.FieldDistinctMSP]; exist {
+		distinctMSP, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("check parameter type : parameter field = %v is not bool", multisig.FieldDistinctMSP)
+		}
+		policy.DistinctMSP = distinctMSP
+	}
+
+	if value, exist := args[multisig.FieldExpirySeconds]; exist {
+		policy.ExpirySeconds = int64(value.(float64))
+	}
+
+	return &policy, nil
+}
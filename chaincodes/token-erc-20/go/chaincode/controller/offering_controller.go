@@ -0,0 +1,593 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/distribute"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/offering"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/settlement"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// 발행사가 partition 공모 생성. hard cap 만큼의 토큰을 escrow 에 넣어 둠
+func (s *SmartContract) CreateOffering(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{offering.FieldPartition, offering.FieldCashPartition, offering.FieldPrice, offering.FieldOpenAt, offering.FieldCloseAt, offering.FieldSoftCap, offering.FieldHardCap}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{offering.FieldPartition, offering.FieldCashPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{offering.FieldPrice, offering.FieldOpenAt, offering.FieldCloseAt, offering.FieldSoftCap, offering.FieldHardCap}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{offering.FieldMinSubscription, offering.FieldMaxSubscription}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	issuer, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	newOffering := offering.OfferingStruct{}
+	newOffering.Partition = args[offering.FieldPartition].(string)
+	newOffering.CashPartition = args[offering.FieldCashPartition].(string)
+	newOffering.Price = int64(args[offering.FieldPrice].(float64))
+	newOffering.OpenAt = int64(args[offering.FieldOpenAt].(float64))
+	newOffering.CloseAt = int64(args[offering.FieldCloseAt].(float64))
+	newOffering.SoftCap = int64(args[offering.FieldSoftCap].(float64))
+	newOffering.HardCap = int64(args[offering.FieldHardCap].(float64))
+	newOffering.Issuer = issuer
+	newOffering.CreatedBy = issuer
+
+	if value, exist := args[offering.FieldMinSubscription]; exist {
+		newOffering.MinSubscription = int64(value.(float64))
+	}
+
+	if value, exist := args[offering.FieldMaxSubscription]; exist {
+		newOffering.MaxSubscription = int64(value.(float64))
+	}
+
+	// 이미 배분(DistributeToken) 또는 공모 배정이 끝난 partition 은 공모 불가
+	holderList, err := distribute.GetHolderList(ctx, newOffering.Partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if holderList.IsLocked {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("token holder list of partition %s is already locked", newOffering.Partition))
+	}
+
+	created, err := offering.CreateOffering(ctx, newOffering)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	escrow := wallet.EscrowAddress(offering.TokenEscrowName)
+	err = _fundEscrowByPartition(ctx, issuer, escrow, created.Partition, created.TokensOffered)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OfferingCreated", From: issuer, To: escrow, Partition: created.Partition, Amount: created.TokensOffered}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(created)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 투자자 본인의 청약. 대금은 cash partition 에서 escrow 로 이전되고 추가 청약은 합산
+func (s *SmartContract) Subscribe(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{offering.FieldOfferingId, offering.FieldAmount}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{offering.FieldOfferingId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{offering.FieldAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	investor, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	amount := int64(args[offering.FieldAmount].(float64))
+
+	targetOffering, err := offering.GetOffering(ctx, args[offering.FieldOfferingId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if investor == targetOffering.Issuer {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("issuer cannot subscribe to its own offering"))
+	}
+
+	subscription, _, err := offering.Subscribe(ctx, *targetOffering, investor, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 배정받을 토큰 기준으로 수령 가능 여부를 미리 확인. close 시 한 번 더 확인
+	err = _checkCreditByPartition(ctx, investor, targetOffering.Partition, subscription.Tokens)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkRules(ctx, rules.OperationIssuance, targetOffering.Partition, "", investor, subscription.Tokens)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	escrow := wallet.EscrowAddress(offering.CashEscrowName)
+	adapter, err := _settlementAdapter(ctx, targetOffering.Partition, targetOffering.CashPartition, offering.CashEscrowName)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = adapter.Debit(ctx, investor, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OfferingSubscribed", From: investor, To: escrow, Partition: targetOffering.CashPartition, Amount: amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(subscription)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 청약 기간 종료 또는 hard cap 도달 후 누구나 호출.
+// soft cap 을 넘으면 자동 배정하고 holder list 를 잠금, 미달이면 전액 환불
+func (s *SmartContract) CloseOffering(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{offering.FieldOfferingId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{offering.FieldOfferingId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	closedBy, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	targetOffering, err := offering.GetOffering(ctx, args[offering.FieldOfferingId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = offering.CheckClosable(ctx, *targetOffering)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	status := offering.StatusFailed
+	if targetOffering.Raised >= targetOffering.SoftCap {
+		status = offering.StatusSucceeded
+	}
+
+	closed, err := _settleOffering(ctx, targetOffering, status, closedBy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OfferingClosed", From: wallet.EscrowAddress(offering.TokenEscrowName), To: "", Partition: closed.Partition, Amount: closed.AllocatedTokens}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(closed)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 발행사가 청약 기간 중 공모 취소. 청약 대금은 전액 환불
+func (s *SmartContract) CancelOffering(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{offering.FieldOfferingId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{offering.FieldOfferingId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	cancelledBy, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	targetOffering, err := offering.GetOffering(ctx, args[offering.FieldOfferingId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if cancelledBy != targetOffering.Issuer {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("only the issuer of the offering can cancel it : %s", targetOffering.OfferingId))
+	}
+
+	cancelled, err := _settleOffering(ctx, targetOffering, offering.StatusCancelled, cancelledBy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OfferingCancelled", From: wallet.EscrowAddress(offering.CashEscrowName), To: "", Partition: cancelled.CashPartition, Amount: cancelled.RefundedAmount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(cancelled)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 성공한 공모의 배정 대금을 발행사에게 이전.
+// close 와 같은 tx 에서 발행사 wallet 을 두 번 쓰지 않도록 별도 호출
+func (s *SmartContract) WithdrawOfferingProceeds(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{offering.FieldOfferingId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{offering.FieldOfferingId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	targetOffering, err := offering.GetOffering(ctx, args[offering.FieldOfferingId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if caller != targetOffering.Issuer {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("only the issuer of the offering can withdraw the proceeds : %s", targetOffering.OfferingId))
+	}
+
+	withdrawn, err := offering.WithdrawProceeds(ctx, *targetOffering)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	escrow := wallet.EscrowAddress(offering.CashEscrowName)
+	adapter, err := _settlementAdapter(ctx, withdrawn.Partition, withdrawn.CashPartition, offering.CashEscrowName)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = adapter.Credit(ctx, []settlement.PaymentStruct{{To: withdrawn.Issuer, Amount: withdrawn.AllocatedAmount}})
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OfferingProceedsWithdrawn", From: escrow, To: withdrawn.Issuer, Partition: withdrawn.CashPartition, Amount: withdrawn.AllocatedAmount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(withdrawn)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetOffering(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{offering.FieldOfferingId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{offering.FieldOfferingId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	targetOffering, err := offering.GetOffering(ctx, args[offering.FieldOfferingId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(targetOffering)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetOfferingList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	return _offeringList(ctx, args, offering.GetOfferingList)
+}
+
+func (s *SmartContract) GetOfferingSubscription(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{offering.FieldOfferingId, offering.FieldInvestor}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{offering.FieldOfferingId, offering.FieldInvestor}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	offeringId := args[offering.FieldOfferingId].(string)
+	investor := args[offering.FieldInvestor].(string)
+
+	subscription, err := offering.GetSubscription(ctx, offeringId, investor)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if subscription == nil {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("no subscription of %s for offering %s", investor, offeringId))
+	}
+
+	retData, err := ccutils.StructToMap(subscription)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetOfferingSubscriptionList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	return _offeringList(ctx, args, offering.GetSubscriptionList)
+}
+
+// _settleOffering 청약별 배정/환불을 정해 escrow 에서 지급하고 결과 저장.
+// 토큰은 token escrow 에서 배정분과 미배정분(발행사)을, 환불은 cash escrow 에서 각각 한 번에 지급
+func _settleOffering(ctx contractapi.TransactionContextInterface, targetOffering *offering.OfferingStruct, status string, closedBy string) (*offering.OfferingStruct, error) {
+
+	subscriptions, err := offering.GetSubscriptions(ctx, targetOffering.OfferingId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 키 분실로 복구된 wallet 의 청약은 새 wallet 으로 배정/환불
+	payees := []string{}
+	payeeTokens := make(map[string]int64)
+	payeeIndexes := make(map[string][]int)
+	for i, subscription := range subscriptions {
+		payee, err := _activeWallet(ctx, subscription.Investor)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, exist := payeeTokens[payee]; !exist {
+			payees = append(payees, payee)
+		}
+		payeeTokens[payee] += subscription.Tokens
+		payeeIndexes[payee] = append(payeeIndexes[payee], i)
+	}
+
+	// 앞서 배정한 투자자로 늘어난 보유자 수를 누적해 maxHolders 등을 확인
+	batch := _newRuleBatch(targetOffering.Partition, "")
+	allocations := make(map[string]int64)
+	for _, payee := range payees {
+		reason := status
+		if status == offering.StatusSucceeded {
+			reason = ""
+			err = _checkCreditByPartition(ctx, payee, targetOffering.Partition, payeeTokens[payee])
+			if err == nil {
+				err = batch.check(ctx, rules.OperationIssuance, "", payee, payeeTokens[payee])
+			}
+			// 청약 이후 수령 불가가 되었거나 보유자 한도를 넘기는 투자자는 배정하지 않고 환불
+			if err != nil {
+				reason = err.Error()
+			}
+		}
+
+		for _, i := range payeeIndexes[payee] {
+			subscriptions[i].Recipient = payee
+			if reason == "" {
+				subscriptions[i].Status = offering.SubscriptionAllocated
+			} else {
+				subscriptions[i].Status = offering.SubscriptionRefunded
+				subscriptions[i].Reason = reason
+			}
+		}
+
+		if reason == "" {
+			allocations[payee] = payeeTokens[payee]
+		}
+	}
+
+	tokenPayees := []string{}
+	tokenAmounts := make(map[string]int64)
+	cashPayees := []string{}
+	cashAmounts := make(map[string]int64)
+	addPayment := func(payees *[]string, amounts map[string]int64, payee string, amount int64) {
+		if amount <= 0 {
+			return
+		}
+		if _, exist := amounts[payee]; !exist {
+			*payees = append(*payees, payee)
+		}
+		amounts[payee] += amount
+	}
+
+	var allocatedTokens int64
+	for _, subscription := range subscriptions {
+		if subscription.Status == offering.SubscriptionAllocated {
+			allocatedTokens += subscription.Tokens
+			addPayment(&tokenPayees, tokenAmounts, subscription.Recipient, subscription.Tokens)
+		} else {
+			addPayment(&cashPayees, cashAmounts, subscription.Recipient, subscription.Amount)
+		}
+	}
+	addPayment(&tokenPayees, tokenAmounts, targetOffering.Issuer, targetOffering.TokensOffered-allocatedTokens)
+
+	if len(tokenPayees) > 0 {
+		err = pause.CheckNotPaused(ctx, targetOffering.Partition, pause.OperationIssuance)
+		if err != nil {
+			return nil, err
+		}
+
+		escrow := wallet.EscrowAddress(offering.TokenEscrowName)
+		transfers := []token.TransferByPartitionStruct{}
+		for _, payee := range tokenPayees {
+			transfers = append(transfers, token.TransferByPartitionStruct{From: escrow, To: payee, Partition: targetOffering.Partition, Amount: tokenAmounts[payee]})
+		}
+
+		err = wallet.TransferBatchByPartition(ctx, escrow, targetOffering.Partition, transfers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 환불은 투자자 본인의 대금 반환이므로 수령인 KYC/한도 확인을 하지 않음
+	if len(cashPayees) > 0 {
+		adapter, err := _settlementAdapter(ctx, targetOffering.Partition, targetOffering.CashPartition, offering.CashEscrowName)
+		if err != nil {
+			return nil, err
+		}
+
+		payments := []settlement.PaymentStruct{}
+		for _, payee := range cashPayees {
+			payments = append(payments, settlement.PaymentStruct{To: payee, Amount: cashAmounts[payee]})
+		}
+
+		err = adapter.Credit(ctx, payments)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, payee := range payees {
+		if amount, exist := allocations[payee]; exist {
+			err = _recordCreditByPartition(ctx, payee, targetOffering.Partition, amount)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	settled, err := offering.SettleOffering(ctx, *targetOffering, subscriptions, status, closedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// 배정 결과를 holder list 에 남기고 잠가서 이후 DistributeToken/AirDrop 을 막음
+	if settled.Status == offering.StatusSucceeded {
+		err = token.LockHolderList(ctx, settled.Partition, allocations)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return settled, nil
+}
+
+func _offeringList(ctx contractapi.TransactionContextInterface, args map[string]interface{}, query func(map[string]interface{}, int32, string, contractapi.TransactionContextInterface) ([]byte, error)) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	bytes, err := query(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
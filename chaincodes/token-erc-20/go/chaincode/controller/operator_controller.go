@@ -3,15 +3,22 @@ package controller
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/blocklist"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/distribute"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/holders"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/operator"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/snapshot"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
 )
 
 func (s *SmartContract) IsOperatorByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
@@ -56,7 +63,7 @@ func (s *SmartContract) AuthorizeOperatorByPartition(ctx contractapi.Transaction
 		return nil, err
 	}
 
-	_, err = ccutils.GetID(ctx)
+	id, err := ccutils.GetID(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -76,12 +83,16 @@ func (s *SmartContract) AuthorizeOperatorByPartition(ctx contractapi.Transaction
 	operatorArg := args[operator.FieldOperator].(string)
 	partitionArg := args[operator.FieldPartition].(string)
 
-	err = token.IsIssuable(ctx, partitionArg)
+	proposal, err := _proposeIfRequired(ctx, ActionAuthorizeOperatorByPartition, ccutils.GetAddress([]byte(id)), args)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	err = operator.AuthorizeOperatorByPartition(ctx, operatorArg, partitionArg)
+	if proposal != nil {
+		return _proposalResponse(ctx, proposal)
+	}
+
+	err = _authorizeOperatorByPartition(ctx, operatorArg, partitionArg)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
@@ -89,6 +100,27 @@ func (s *SmartContract) AuthorizeOperatorByPartition(ctx contractapi.Transaction
 	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
 }
 
+func _authorizeOperatorByPartition(ctx contractapi.TransactionContextInterface, operatorArg string, partitionArg string) error {
+
+	err := token.IsIssuable(ctx, partitionArg)
+	if err != nil {
+		return err
+	}
+
+	err = blocklist.CheckNotBlocked(ctx, operatorArg)
+	if err != nil {
+		return err
+	}
+
+	err = operator.AuthorizeOperatorByPartition(ctx, operatorArg, partitionArg)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "AuthorizedOperator", From: "", To: operatorArg, Partition: partitionArg, Amount: 0}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
 func (s *SmartContract) RevokeOperatorByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
 	err := ccutils.GetMSPID(ctx)
@@ -96,7 +128,7 @@ func (s *SmartContract) RevokeOperatorByPartition(ctx contractapi.TransactionCon
 		return nil, err
 	}
 
-	_, err = ccutils.GetID(ctx)
+	id, err := ccutils.GetID(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -116,12 +148,222 @@ func (s *SmartContract) RevokeOperatorByPartition(ctx contractapi.TransactionCon
 	operatorArg := args[operator.FieldOperator].(string)
 	partitionArg := args[operator.FieldPartition].(string)
 
-	err = token.IsIssuable(ctx, partitionArg)
+	proposal, err := _proposeIfRequired(ctx, ActionRevokeOperatorByPartition, ccutils.GetAddress([]byte(id)), args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if proposal != nil {
+		return _proposalResponse(ctx, proposal)
+	}
+
+	err = _revokeOperatorByPartition(ctx, operatorArg, partitionArg)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
 
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+}
+
+func _revokeOperatorByPartition(ctx contractapi.TransactionContextInterface, operatorArg string, partitionArg string) error {
+
+	err := token.IsIssuable(ctx, partitionArg)
+	if err != nil {
+		return err
+	}
+
 	err = operator.RevokeOperatorByPartition(ctx, operatorArg, partitionArg)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RevokedOperator", From: "", To: operatorArg, Partition: partitionArg, Amount: 0}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+// ERC1400 operatorTransferByPartition. partition 에 권한이 있는 operator 가 holder 대신 이전
+func (s *SmartContract) OperatorTransferByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	err := ccutils.GetMSPID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{token.FieldFrom, token.FieldTo, token.FieldPartition, token.FieldAmount}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{token.FieldFrom, token.FieldTo, token.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{token.FieldAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// args Data
+	operatorAddress, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	from := args[token.FieldFrom].(string)
+	to := args[token.FieldTo].(string)
+	partition := args[token.FieldPartition].(string)
+	amount := int64(args[token.FieldAmount].(float64))
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("transfer amount must be a positive integer")
+	}
+
+	isOperator, err := operator.IsOperatorByPartition(ctx, operatorAddress, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if !isOperator {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("%s is not an operator of partition %s", operatorAddress, partition))
+	}
+
+	err = blocklist.CheckNotBlocked(ctx, operatorAddress)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _transferByPartition(ctx, from, to, partition, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OperatorTransfer", From: from, To: to, Partition: partition, Amount: amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+}
+
+// partition operator 가 from 의 잔고를 여러 수령인에게 한 번에 이전. from 을 한 번만 쓰므로
+// 외부 chaincode 의 결제 계정 지급(settlement CreditFunction)에 사용
+func (s *SmartContract) OperatorTransferBatchByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	err := ccutils.GetMSPID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{token.FieldFrom, token.FieldPartition, token.FieldRecipients}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{token.FieldFrom, token.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	recipientsArg, ok := args[token.FieldRecipients].(map[string]interface{})
+	if !ok || len(recipientsArg) == 0 {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("%s must be a non-empty map of recipient to amount", token.FieldRecipients))
+	}
+
+	operatorAddress, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	from := args[token.FieldFrom].(string)
+	partition := args[token.FieldPartition].(string)
+
+	isOperator, err := operator.IsOperatorByPartition(ctx, operatorAddress, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if !isOperator {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("%s is not an operator of partition %s", operatorAddress, partition))
+	}
+
+	err = blocklist.CheckNotBlocked(ctx, operatorAddress)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// endorsing peer 마다 같은 순서로 기록되도록 정렬
+	recipients := make([]string, 0, len(recipientsArg))
+	for recipient := range recipientsArg {
+		recipients = append(recipients, recipient)
+	}
+	sort.Strings(recipients)
+
+	// rule 은 앞선 수령인으로 바뀐 보유자 수와 from 의 누적 출금량을 반영해 평가
+	batch := _newRuleBatch(partition, "")
+	var total int64
+	transfers := []token.TransferByPartitionStruct{}
+	for _, recipient := range recipients {
+		amountValue, ok := recipientsArg[recipient].(float64)
+		if !ok || amountValue <= 0 || amountValue != float64(int64(amountValue)) {
+			return ccutils.GenerateErrorResponse(fmt.Errorf("invalid amount for recipient %s", recipient))
+		}
+		amount := int64(amountValue)
+
+		if recipient == from {
+			return ccutils.GenerateErrorResponse(fmt.Errorf("cannot transfer to the same wallet"))
+		}
+
+		err = wallet.CheckNotSuperseded(ctx, recipient)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = _checkCreditByPartition(ctx, recipient, partition, amount)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = batch.check(ctx, rules.OperationTransfer, from, recipient, amount)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		total += amount
+		transfers = append(transfers, token.TransferByPartitionStruct{From: from, To: recipient, Partition: partition, Amount: amount})
+	}
+
+	err = pause.CheckNotPaused(ctx, partition, pause.OperationTransfer)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkDebitByPartition(ctx, from, partition, total)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = wallet.TransferBatchByPartition(ctx, from, partition, transfers)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	for _, transfer := range transfers {
+		err = _recordCreditByPartition(ctx, transfer.To, partition, transfer.Amount)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	// tx 당 event 는 하나만 남으므로 합계로 발행
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OperatorTransferBatch", From: from, To: "", Partition: partition, Amount: total}
+	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
@@ -150,6 +392,78 @@ func (s *SmartContract) DistributeToken(ctx contractapi.TransactionContextInterf
 
 	partition := args[operator.FieldPartition].(string)
 	recipients := args[operator.FieldRecipients].(map[string]interface{})
+
+	err = pause.CheckNotPaused(ctx, partition, pause.OperationIssuance)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// endorsing peer 마다 같은 순서로 평가되도록 정렬
+	addresses := make([]string, 0, len(recipients))
+	for address := range recipients {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	// 배분 총량이 supply cap 을 넘지 않는지 먼저 확인. rule 은 앞선 수령인으로 늘어난 보유자 수를 누적해 평가
+	batch := _newRuleBatch(partition, "")
+	var totalAmount, holderDelta int64
+	for _, address := range addresses {
+		value, ok := recipients[address].(float64)
+		if !ok || value <= 0 || value != float64(int64(value)) {
+			return ccutils.GenerateErrorResponse(fmt.Errorf("invalid amount for recipient %s", address))
+		}
+		totalAmount += int64(value)
+
+		err = _checkCreditByPartition(ctx, address, partition, int64(value))
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = batch.check(ctx, rules.OperationIssuance, "", address, int64(value))
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = _recordCreditByPartition(ctx, address, partition, int64(value))
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		// checkpoint 는 분할 환산 전 저장된 값으로 남김
+		before, err := token.GetBalanceRecord(ctx, address, partition)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+		// DistributeToken 은 partition 잔고를 배분 수량으로 덮어씀
+		holderDelta += wallet.HolderDelta(address, before.Amount, int64(value))
+
+		err = snapshot.Checkpoint(ctx, address, partition, before.Amount, before.ScaleEpoch)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	err = token.CheckSupplyCap(ctx, partition, totalAmount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 수령인별 잔고 기록은 distribute service 에서 하므로 보유자 수는 여기서 한 번에 반영
+	err = holders.Apply(ctx, partition, holderDelta)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	totalSupplyByPartition, err := token.GetTotalSupplyRecord(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = snapshot.CheckpointSupply(ctx, partition, totalSupplyByPartition.TotalSupply, totalSupplyByPartition.ScaleEpoch)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
 	fmt.Println(recipients)
 
 	// json example
@@ -260,6 +574,77 @@ func (s *SmartContract) AirDrop(ctx contractapi.TransactionContextInterface, arg
 	partition := args[operator.FieldPartition].(string)
 	recipients := args[operator.FieldRecipients].(map[string]interface{})
 
+	err = pause.CheckNotPaused(ctx, partition, pause.OperationIssuance)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// endorsing peer 마다 같은 순서로 평가되도록 정렬
+	addresses := make([]string, 0, len(recipients))
+	for address := range recipients {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	// 배분 총량이 supply cap 을 넘지 않는지 먼저 확인. rule 은 앞선 수령인으로 늘어난 보유자 수를 누적해 평가
+	batch := _newRuleBatch(partition, "")
+	var totalAmount, holderDelta int64
+	for _, address := range addresses {
+		value, ok := recipients[address].(float64)
+		if !ok || value <= 0 || value != float64(int64(value)) {
+			return ccutils.GenerateErrorResponse(fmt.Errorf("invalid amount for recipient %s", address))
+		}
+		totalAmount += int64(value)
+
+		err = _checkCreditByPartition(ctx, address, partition, int64(value))
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = batch.check(ctx, rules.OperationIssuance, "", address, int64(value))
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = _recordCreditByPartition(ctx, address, partition, int64(value))
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		// checkpoint 는 분할 환산 전 저장된 값으로 남김
+		before, err := token.GetBalanceRecord(ctx, address, partition)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+		holderDelta += wallet.HolderDelta(address, before.Amount, before.Amount+int64(value))
+
+		err = snapshot.Checkpoint(ctx, address, partition, before.Amount, before.ScaleEpoch)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	err = token.CheckSupplyCap(ctx, partition, totalAmount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 수령인별 잔고 기록은 distribute service 에서 하므로 보유자 수는 여기서 한 번에 반영
+	err = holders.Apply(ctx, partition, holderDelta)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	totalSupplyByPartition, err := token.GetTotalSupplyRecord(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = snapshot.CheckpointSupply(ctx, partition, totalSupplyByPartition.TotalSupply, totalSupplyByPartition.ScaleEpoch)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	// Create allowanceKey
 	listKey, err := ctx.GetStub().CreateCompositeKey(token.DocType_AirDrop, []string{partition})
 	if err != nil {
@@ -277,7 +662,7 @@ func (s *SmartContract) AirDrop(ctx contractapi.TransactionContextInterface, arg
 		return nil, err
 	}
 
-	// 투자 종료가 되었다는 파악을 할 수 있는 트리거가 있어야 하네
+	// 공모(CloseOffering) 배정이 끝나면 holder list 가 잠기므로 투자 종료 이후의 배분은 여기서 막힘
 
 	if listStruct.IsLocked == true {
 		return nil, fmt.Errorf("already exectued")
@@ -0,0 +1,439 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/orderbook"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// 지정가 주문. 매도는 증권, 매수는 대금 (price * quantity) 을 주문 시 escrow
+func (s *SmartContract) PlaceOrder(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{orderbook.FieldSide, orderbook.FieldPartition, orderbook.FieldCashPartition, orderbook.FieldPrice, orderbook.FieldQuantity}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{orderbook.FieldSide, orderbook.FieldPartition, orderbook.FieldCashPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{orderbook.FieldPrice, orderbook.FieldQuantity}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{orderbook.FieldExpiration}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	trader, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	order := orderbook.OrderStruct{}
+	order.Side = args[orderbook.FieldSide].(string)
+	order.Trader = trader
+	order.Partition = args[orderbook.FieldPartition].(string)
+	order.CashPartition = args[orderbook.FieldCashPartition].(string)
+	order.Price = int64(args[orderbook.FieldPrice].(float64))
+	order.Quantity = int64(args[orderbook.FieldQuantity].(float64))
+
+	if value, exist := args[orderbook.FieldExpiration]; exist {
+		order.Expiration = int64(value.(float64))
+	}
+
+	// 체결 시 다시 확인하지만, 받을 수 없는 매수 주문은 미리 거부
+	if order.Side == orderbook.SideBuy {
+		err = _checkCreditByPartition(ctx, trader, order.Partition, order.Quantity)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	placed, err := orderbook.PlaceOrder(ctx, order)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	escrow := wallet.EscrowAddress(orderbook.EscrowName)
+	err = _fundEscrowByPartition(ctx, trader, escrow, placed.EscrowPartition(), placed.Escrowed)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OrderPlaced", From: trader, To: escrow, Partition: placed.EscrowPartition(), Amount: placed.Escrowed}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(placed)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 주문자의 취소 또는 만료 후 누구나 정리. 남은 escrow 는 주문자에게 반환
+func (s *SmartContract) CancelOrder(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{orderbook.FieldOrderId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{orderbook.FieldOrderId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	cancelledBy, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	order, err := orderbook.GetOrder(ctx, args[orderbook.FieldOrderId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	cancelled, err := orderbook.CancelOrder(ctx, *order, cancelledBy)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 키 분실로 복구된 wallet 의 escrow 는 새 wallet 으로 반환
+	payee, err := _activeWallet(ctx, cancelled.Trader)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if cancelled.Refunded > 0 {
+		err = pause.CheckNotPaused(ctx, cancelled.EscrowPartition(), pause.OperationTransfer)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		escrow := wallet.EscrowAddress(orderbook.EscrowName)
+		transfers := []token.TransferByPartitionStruct{{From: escrow, To: payee, Partition: cancelled.EscrowPartition(), Amount: cancelled.Refunded}}
+		err = wallet.TransferBatchByPartition(ctx, escrow, cancelled.EscrowPartition(), transfers)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OrderCancelled", From: wallet.EscrowAddress(orderbook.EscrowName), To: payee, Partition: cancelled.EscrowPartition(), Amount: cancelled.Refunded}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(cancelled)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 가격-시간 우선순위로 주문 체결. 체결마다 수령인 확인과 transfer rule 을 평가해 통과하지 못한 쌍은 건너뛰고,
+// 만료된 주문은 닫아서 환불. 모든 이전은 wallet 당 한 번의 쓰기로 반영
+func (s *SmartContract) MatchOrders(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{orderbook.FieldPartition, orderbook.FieldCashPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{orderbook.FieldPartition, orderbook.FieldCashPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{orderbook.FieldMaxFills}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := args[orderbook.FieldPartition].(string)
+	cashPartition := args[orderbook.FieldCashPartition].(string)
+
+	var maxFills int
+	if value, exist := args[orderbook.FieldMaxFills]; exist {
+		maxFills = int(value.(float64))
+	}
+
+	for _, target := range []string{partition, cashPartition} {
+		err = pause.CheckNotPaused(ctx, target, pause.OperationTransfer)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	payees := make(map[string]string)
+	payeeOf := func(trader string) (string, error) {
+		if payee, exist := payees[trader]; exist {
+			return payee, nil
+		}
+
+		// 키 분실로 복구된 wallet 의 몫은 새 wallet 으로 지급
+		payee, err := _activeWallet(ctx, trader)
+		if err != nil {
+			return "", err
+		}
+		payees[trader] = payee
+		return payee, nil
+	}
+
+	// 한 번의 체결에서 같은 수령인이 여러 번 받을 수 있으므로 누적 수량과 누적 보유자 수로 한도와 rule 확인.
+	// 매도 토큰과 매수 대금은 escrow 에서 나감
+	escrow := wallet.EscrowAddress(orderbook.EscrowName)
+	credited := map[string]map[string]int64{partition: {}, cashPartition: {}}
+	tokenRules := _newRuleBatch(partition, escrow)
+	cashRules := _newRuleBatch(cashPartition, escrow)
+	check := func(buy orderbook.OrderStruct, sell orderbook.OrderStruct, quantity int64, cashAmount int64) error {
+		buyer, err := payeeOf(buy.Trader)
+		if err != nil {
+			return err
+		}
+
+		seller, err := payeeOf(sell.Trader)
+		if err != nil {
+			return err
+		}
+
+		if buyer == seller {
+			return fmt.Errorf("cannot transfer to the same wallet")
+		}
+
+		err = _checkCreditByPartition(ctx, buyer, partition, credited[partition][buyer]+quantity)
+		if err != nil {
+			return err
+		}
+
+		err = tokenRules.evaluate(ctx, rules.OperationTransfer, sell.Trader, buyer, quantity)
+		if err != nil {
+			return err
+		}
+
+		err = _checkCreditByPartition(ctx, seller, cashPartition, credited[cashPartition][seller]+cashAmount)
+		if err != nil {
+			return err
+		}
+
+		err = cashRules.evaluate(ctx, rules.OperationTransfer, buy.Trader, seller, cashAmount)
+		if err != nil {
+			return err
+		}
+
+		// 두 쪽 모두 통과한 체결만 누적
+		err = tokenRules.add(ctx, sell.Trader, buyer, quantity)
+		if err != nil {
+			return err
+		}
+
+		err = cashRules.add(ctx, buy.Trader, seller, cashAmount)
+		if err != nil {
+			return err
+		}
+
+		credited[partition][buyer] += quantity
+		credited[cashPartition][seller] += cashAmount
+		return nil
+	}
+
+	result, err := orderbook.Match(ctx, partition, cashPartition, maxFills, check)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bidPrices := make(map[string]int64)
+	for _, order := range result.Orders {
+		if order.Side == orderbook.SideBuy {
+			bidPrices[order.OrderId] = order.Price
+		}
+	}
+
+	transfers := []token.TransferByPartitionStruct{}
+	var matched int64
+	for _, fill := range result.Fills {
+		buyer := payees[fill.Buyer]
+		seller := payees[fill.Seller]
+
+		transfers = append(transfers, token.TransferByPartitionStruct{From: escrow, To: buyer, Partition: partition, Amount: fill.Quantity})
+		transfers = append(transfers, token.TransferByPartitionStruct{From: escrow, To: seller, Partition: cashPartition, Amount: fill.CashAmount})
+
+		// 매수 주문 가격보다 낮게 체결된 차액 환불
+		improvement := (bidPrices[fill.BuyOrderId] - fill.Price) * fill.Quantity
+		if improvement > 0 {
+			transfers = append(transfers, token.TransferByPartitionStruct{From: escrow, To: buyer, Partition: cashPartition, Amount: improvement})
+		}
+
+		matched += fill.Quantity
+	}
+
+	for _, expired := range result.Expired {
+		if expired.Refunded == 0 {
+			continue
+		}
+
+		payee, err := payeeOf(expired.Trader)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+		transfers = append(transfers, token.TransferByPartitionStruct{From: escrow, To: payee, Partition: expired.EscrowPartition(), Amount: expired.Refunded})
+	}
+
+	err = wallet.SettleBatch(ctx, transfers)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	for _, target := range []string{partition, cashPartition} {
+		// endorsing peer 마다 같은 순서로 기록되도록 정렬
+		recipients := make([]string, 0, len(credited[target]))
+		for recipient := range credited[target] {
+			recipients = append(recipients, recipient)
+		}
+		sort.Strings(recipients)
+
+		for _, recipient := range recipients {
+			err = _recordCreditByPartition(ctx, recipient, target, credited[target][recipient])
+			if err != nil {
+				return ccutils.GenerateErrorResponse(err)
+			}
+		}
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OrdersMatched", From: escrow, To: "", Partition: partition, Amount: matched}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(result)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetOrder(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{orderbook.FieldOrderId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{orderbook.FieldOrderId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	order, err := orderbook.GetOrder(ctx, args[orderbook.FieldOrderId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(order)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 호가별 잔량. 매수는 높은 가격, 매도는 낮은 가격부터
+func (s *SmartContract) GetOrderBookDepth(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{orderbook.FieldPartition, orderbook.FieldCashPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{orderbook.FieldPartition, orderbook.FieldCashPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	depth, err := orderbook.GetDepth(ctx, args[orderbook.FieldPartition].(string), args[orderbook.FieldCashPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], depth)
+}
+
+// trader, side, partition, cashPartition, status 로 필터
+func (s *SmartContract) GetOrderList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	return _orderBookList(ctx, args, orderbook.GetOrderList)
+}
+
+// buyer, seller, buyOrderId, sellOrderId, partition, cashPartition 으로 필터
+func (s *SmartContract) GetOrderFillList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	return _orderBookList(ctx, args, orderbook.GetFillList)
+}
+
+func _orderBookList(ctx contractapi.TransactionContextInterface, args map[string]interface{}, query func(map[string]interface{}, int32, string, contractapi.TransactionContextInterface) ([]byte, error)) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = query(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
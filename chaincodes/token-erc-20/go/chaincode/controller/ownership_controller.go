@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/ownership"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/timelock"
+)
+
+func (s *SmartContract) TransferOwnership(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{ownership.FieldNewOwner}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ownership.FieldNewOwner}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	owner := ccutils.GetAddress([]byte(id))
+	newOwner := args[ownership.FieldNewOwner].(string)
+
+	ownershipStruct, err := ownership.TransferOwnership(ctx, owner, newOwner)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OwnershipTransferStarted", From: owner, To: newOwner, Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(ownershipStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 소유권 이전 2단계. admin role 이 함께 넘어가므로 바로 적용하지 않고 adminKey 지연 시간으로 예약.
+// 지연 시간이 지나면 새 owner 나 admin 이 ExecuteScheduledAction 으로 완료하고, 그 전에는 admin 이 취소 가능
+func (s *SmartContract) AcceptOwnership(ctx contractapi.TransactionContextInterface) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mspId, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client msp id: %v", err)
+	}
+
+	newOwner := ccutils.GetAddress([]byte(id))
+
+	err = ownership.CheckPendingOwner(ctx, newOwner)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	actionArgs := map[string]interface{}{ownership.FieldOwnerMSP: mspId}
+
+	scheduled, err := timelock.Schedule(ctx, ActionAcceptOwnership, timelock.CategoryAdminKey, newOwner, actionArgs)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 투자자 공지용 이벤트 (Amount 에 실행 가능 시간)
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ActionScheduled", From: newOwner, To: ActionAcceptOwnership, Partition: "", Amount: scheduled.ExecutableAt}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := _timelockActionData(ctx, scheduled)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// _acceptOwnership 예약된 소유권 이전 실행. 그 사이 pending owner 가 바뀌었으면 에러
+func _acceptOwnership(ctx contractapi.TransactionContextInterface, newOwner string, mspId string) error {
+
+	_, previousOwner, err := ownership.AcceptOwnership(ctx, newOwner, mspId)
+	if err != nil {
+		return err
+	}
+
+	// owner 의 admin role 도 함께 이전
+	adminRole := access.RoleStruct{Role: access.RoleAdmin, SubjectType: access.SubjectTypeAddress, Subject: newOwner, GrantedBy: previousOwner}
+	_, err = access.GrantRole(ctx, adminRole)
+	if err != nil {
+		return err
+	}
+
+	previousRole, err := access.GetRole(ctx, access.RoleAdmin, access.SubjectTypeAddress, previousOwner)
+	if err != nil {
+		return err
+	}
+
+	if previousRole != nil {
+		err = access.RevokeRole(ctx, access.RoleAdmin, access.SubjectTypeAddress, previousOwner)
+		if err != nil {
+			return err
+		}
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "OwnershipTransferred", From: previousOwner, To: newOwner, Partition: "", Amount: 0}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+// pageSize 가 없거나 0 이하이면 config 의 DefaultPageSize 사용
+func _pageSize(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (int32, error) {
+
+	if value, exist := args[ledgermanager.PageSize]; exist {
+		pageSize := int32(value.(float64))
+		if pageSize > 0 {
+			return pageSize, nil
+		}
+	}
+
+	return ownership.GetDefaultPageSize(ctx)
+}
+
+// _setContractConfig timelock 을 거친 config 교체
+func _setContractConfig(ctx contractapi.TransactionContextInterface, actor string, args map[string]interface{}) error {
+
+	config, err := ownership.ParseConfig(args)
+	if err != nil {
+		return err
+	}
+
+	_, err = ownership.SetConfig(ctx, *config)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ContractConfigChanged", From: actor, To: "", Partition: "", Amount: 0}
+	return transferEvent.EmitTransferEvent(ctx)
+}
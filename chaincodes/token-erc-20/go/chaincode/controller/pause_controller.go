@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+)
+
+// partition 생략 시 contract 전체, operations 생략 시 issuance/transfer/redemption 모두 정지
+func (s *SmartContract) Pause(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+	return _setPause(ctx, args, true)
+}
+
+func (s *SmartContract) Unpause(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+	return _setPause(ctx, args, false)
+}
+
+func (s *SmartContract) GetPauseState(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	err := ccutils.CheckTypeString([]string{pause.FieldPartition}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := pause.PartitionAll
+	if value, exist := args[pause.FieldPartition]; exist {
+		partition = value.(string)
+	}
+
+	pauseStruct, err := pause.GetPause(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(pauseStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func _setPause(ctx contractapi.TransactionContextInterface, args map[string]interface{}, paused bool) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ccutils.CheckTypeString([]string{pause.FieldPartition}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := pause.PartitionAll
+	if value, exist := args[pause.FieldPartition]; exist {
+		partition = value.(string)
+	}
+
+	operations := pause.Operations
+	if value, exist := args[pause.FieldOperations]; exist {
+		err = ccutils.CheckRequireTypeArray([]string{pause.FieldOperations}, args)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		operations = []string{}
+		for _, item := range value.([]interface{}) {
+			operation, ok := item.(string)
+			if !ok {
+				return ccutils.GenerateErrorResponse(fmt.Errorf("unknown operation : %v", item))
+			}
+			operations = append(operations, operation)
+		}
+	}
+
+	actor := ccutils.GetAddress([]byte(id))
+
+	pauseStruct, err := pause.SetPause(ctx, partition, operations, paused, actor)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	eventType := "Paused"
+	if !paused {
+		eventType = "Unpaused"
+	}
+
+	// To 에 대상 operation 목록
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: eventType, From: actor, To: strings.Join(operations, ","), Partition: partition, Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(pauseStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
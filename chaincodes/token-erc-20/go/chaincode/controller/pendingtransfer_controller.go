@@ -0,0 +1,428 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pendingtransfer"
+)
+
+// 발행사 승인이 필요한 이전 요청. 승인/거절/만료 전까지 amount 만큼 출금 불가
+func (s *SmartContract) RequestTransferByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pendingtransfer.FieldTo, pendingtransfer.FieldPartition, pendingtransfer.FieldAmount}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pendingtransfer.FieldTo, pendingtransfer.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{pendingtransfer.FieldAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{pendingtransfer.FieldExpiresAt}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	from, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request := pendingtransfer.PendingTransferStruct{}
+	request.From = from
+	request.To = args[pendingtransfer.FieldTo].(string)
+	request.Partition = args[pendingtransfer.FieldPartition].(string)
+	request.Amount = int64(args[pendingtransfer.FieldAmount].(float64))
+
+	if value, exist := args[pendingtransfer.FieldExpiresAt]; exist {
+		request.ExpiresAt = int64(value.(float64))
+	}
+
+	if request.From == request.To {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("cannot transfer to the same wallet"))
+	}
+
+	// 승인 시점에 다시 확인하지만, 통과할 수 없는 요청은 미리 거부
+	err = _checkDebitByPartition(ctx, request.From, request.Partition, request.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkCreditByPartition(ctx, request.To, request.Partition, request.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkApprovedRules(ctx, request.Partition, request.From, request.To, request.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	newRequest, err := pendingtransfer.CreateRequest(ctx, request)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "TransferRequested", From: newRequest.From, To: newRequest.To, Partition: newRequest.Partition, Amount: newRequest.Amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newRequest)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 발행사 또는 위임 승인자가 승인하면 예약을 풀고 이전 실행
+func (s *SmartContract) ApprovePendingTransfer(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pendingtransfer.FieldRequestId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pendingtransfer.FieldRequestId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request, err := pendingtransfer.GetRequest(ctx, args[pendingtransfer.FieldRequestId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	approver, err := _checkTransferApprover(ctx, request.Partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request, err = pendingtransfer.CloseRequest(ctx, *request, pendingtransfer.StatusExecuted, approver, "")
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 같은 tx 에서 갱신한 예약 기록은 다시 읽히지 않으므로 자기 예약분을 제외하고 확인
+	err = _checkDebitExceptRequest(ctx, request.From, request.Partition, request.Amount, request.RequestId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkApprovedRules(ctx, request.Partition, request.From, request.To, request.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _moveByPartition(ctx, request.From, request.To, request.Partition, request.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _pendingTransferResponse(ctx, request, "Transfer")
+}
+
+// 발행사 또는 위임 승인자의 거절. 예약 해제
+func (s *SmartContract) RejectPendingTransfer(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pendingtransfer.FieldRequestId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pendingtransfer.FieldRequestId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{pendingtransfer.FieldReason}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request, err := pendingtransfer.GetRequest(ctx, args[pendingtransfer.FieldRequestId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	rejector, err := _checkTransferApprover(ctx, request.Partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var reason string
+	if value, exist := args[pendingtransfer.FieldReason]; exist {
+		reason = value.(string)
+	}
+
+	request, err = pendingtransfer.CloseRequest(ctx, *request, pendingtransfer.StatusRejected, rejector, reason)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _pendingTransferResponse(ctx, request, "TransferRequestRejected")
+}
+
+// 요청한 holder 의 취소. 예약 해제
+func (s *SmartContract) CancelPendingTransfer(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pendingtransfer.FieldRequestId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pendingtransfer.FieldRequestId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request, err := pendingtransfer.GetRequest(ctx, args[pendingtransfer.FieldRequestId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if request.From != caller {
+		return ccutils.GenerateErrorResponse(ccutils.CreateError(ccutils.ChaincodeErrorForbidden, fmt.Errorf("only the requester can cancel the pending transfer : %s", request.RequestId)))
+	}
+
+	request, err = pendingtransfer.CloseRequest(ctx, *request, pendingtransfer.StatusCancelled, caller, "")
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _pendingTransferResponse(ctx, request, "TransferRequestCancelled")
+}
+
+// 만료된 요청 정리. 누구나 호출 가능 (만료된 예약은 이미 출금 제한에서 제외됨)
+func (s *SmartContract) ExpirePendingTransfer(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pendingtransfer.FieldRequestId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pendingtransfer.FieldRequestId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := pendingtransfer.GetRequest(ctx, args[pendingtransfer.FieldRequestId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request, err = pendingtransfer.CloseRequest(ctx, *request, pendingtransfer.StatusExpired, ccutils.GetAddress([]byte(id)), "")
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _pendingTransferResponse(ctx, request, "TransferRequestExpired")
+}
+
+func (s *SmartContract) GetPendingTransfer(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pendingtransfer.FieldRequestId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pendingtransfer.FieldRequestId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request, err := pendingtransfer.GetRequest(ctx, args[pendingtransfer.FieldRequestId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(request)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// from(holder), to, partition, status 로 필터
+func (s *SmartContract) GetPendingTransferList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = pendingtransfer.GetRequestList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// partition 의 위임 승인자 목록 교체. 빈 배열이면 issuer role 만 승인 가능
+func (s *SmartContract) SetTransferApprovers(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{pendingtransfer.FieldPartition, pendingtransfer.FieldApprovers}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pendingtransfer.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	approvers, err := _stringArrayArg(args, pendingtransfer.FieldApprovers)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	approversStruct, err := pendingtransfer.SetApprovers(ctx, args[pendingtransfer.FieldPartition].(string), approvers, ccutils.GetAddress([]byte(id)))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(approversStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetTransferApprovers(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pendingtransfer.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pendingtransfer.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	approversStruct, err := pendingtransfer.GetApprovers(ctx, args[pendingtransfer.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(approversStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// _checkTransferApprover issuer role 이거나 partition 의 위임 승인자인 호출자의 wallet 주소
+func _checkTransferApprover(ctx contractapi.TransactionContextInterface, partition string) (string, error) {
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	isIssuer, err := access.HasRole(ctx, access.RoleIssuer)
+	if err != nil {
+		return "", err
+	}
+
+	if isIssuer {
+		return caller, nil
+	}
+
+	isApprover, err := pendingtransfer.IsApprover(ctx, partition, caller)
+	if err != nil {
+		return "", err
+	}
+
+	if !isApprover {
+		return "", ccutils.CreateError(pendingtransfer.CodeErrorNotApprover, fmt.Errorf(pendingtransfer.ErrorCodeMessage[pendingtransfer.CodeErrorNotApprover]+" : "+partition))
+	}
+
+	return caller, nil
+}
+
+func _pendingTransferResponse(ctx contractapi.TransactionContextInterface, request *pendingtransfer.PendingTransferStruct, eventType string) (*ccutils.Response, error) {
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: eventType, From: request.From, To: request.To, Partition: request.Partition, Amount: request.Amount}
+	err := transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(request)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
@@ -0,0 +1,360 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/operator"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pledge"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+)
+
+// 보유자 또는 partition operator 가 잔고를 pledgee 에게 담보로 제공. 해제/집행 전까지 출금 불가
+func (s *SmartContract) PledgeByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pledge.FieldPartition, pledge.FieldPledgee, pledge.FieldAmount}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pledge.FieldPartition, pledge.FieldPledgee}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{pledge.FieldAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{pledge.FieldHolder}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{pledge.FieldGracePeriod}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	newPledge := pledge.PledgeStruct{}
+	newPledge.Partition = args[pledge.FieldPartition].(string)
+	newPledge.Pledgee = args[pledge.FieldPledgee].(string)
+	newPledge.Amount = int64(args[pledge.FieldAmount].(float64))
+	newPledge.Holder = caller
+	newPledge.CreatedBy = caller
+
+	if value, exist := args[pledge.FieldHolder]; exist {
+		newPledge.Holder = value.(string)
+	}
+
+	if value, exist := args[pledge.FieldGracePeriod]; exist {
+		newPledge.GracePeriod = int64(value.(float64))
+	}
+
+	// 다른 보유자의 잔고는 partition operator 만 담보로 제공할 수 있음
+	if newPledge.Holder != caller {
+		isOperator, err := operator.IsOperatorByPartition(ctx, caller, newPledge.Partition)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		if !isOperator {
+			return ccutils.GenerateErrorResponse(fmt.Errorf("%s is not an operator of partition %s", caller, newPledge.Partition))
+		}
+	}
+
+	err = _checkDebitByPartition(ctx, newPledge.Holder, newPledge.Partition, newPledge.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 집행 시점에 다시 확인하지만, 집행할 수 없는 질권은 미리 거부
+	err = _checkCreditByPartition(ctx, newPledge.Pledgee, newPledge.Partition, newPledge.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkRules(ctx, rules.OperationTransfer, newPledge.Partition, newPledge.Holder, newPledge.Pledgee, newPledge.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	created, err := pledge.CreatePledge(ctx, newPledge)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _pledgeResponse(ctx, created, "PledgeCreated")
+}
+
+// 질권자가 질권 해제. 채무 불이행 선언 후에도 가능
+func (s *SmartContract) ReleasePledge(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	caller, targetPledge, err := _pledgeArgs(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	released, err := pledge.ReleasePledge(ctx, *targetPledge, caller)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _pledgeResponse(ctx, released, "PledgeReleased")
+}
+
+// 질권자의 채무 불이행 선언. gracePeriod 가 지나면 EnforcePledge 가능
+func (s *SmartContract) DeclarePledgeDefault(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	err := ccutils.CheckTypeString([]string{pledge.FieldReason}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	caller, targetPledge, err := _pledgeArgs(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var reason string
+	if value, exist := args[pledge.FieldReason]; exist {
+		reason = value.(string)
+	}
+
+	defaulted, err := pledge.DeclareDefault(ctx, *targetPledge, reason, caller)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _pledgeResponse(ctx, defaulted, "PledgeDefaulted")
+}
+
+// 채무 불이행 선언 후 질권자가 담보 수량을 자기 wallet 으로 이전
+func (s *SmartContract) EnforcePledge(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	caller, targetPledge, err := _pledgeArgs(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	enforced, err := pledge.EnforcePledge(ctx, *targetPledge, caller)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 같은 tx 에서 갱신한 질권 기록은 다시 읽히지 않으므로 자기 질권을 빼고 확인
+	err = _checkDebitExceptPledge(ctx, enforced.Holder, enforced.Partition, enforced.Amount, enforced.PledgeId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkRules(ctx, rules.OperationTransfer, enforced.Partition, enforced.Holder, enforced.Pledgee, enforced.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _moveByPartition(ctx, enforced.Holder, enforced.Pledgee, enforced.Partition, enforced.Amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return _pledgeResponse(ctx, enforced, "PledgeEnforced")
+}
+
+func (s *SmartContract) GetPledge(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pledge.FieldPledgeId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pledge.FieldPledgeId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	targetPledge, err := pledge.GetPledge(ctx, args[pledge.FieldPledgeId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(targetPledge)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 질권자의 활성 질권과 partition 별 합계 (defaulted 는 불이행 선언된 수량)
+func (s *SmartContract) GetPledgeeRights(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pledge.FieldPledgee}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pledge.FieldPledgee}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{pledge.FieldPartition}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var partition string
+	if value, exist := args[pledge.FieldPartition]; exist {
+		partition = value.(string)
+	}
+
+	rights, err := pledge.GetPledgeeRights(ctx, args[pledge.FieldPledgee].(string), partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], rights)
+}
+
+// 보유자의 partition 잔고 중 담보로 묶인 수량과 나머지
+func (s *SmartContract) GetPledgedBalance(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{pledge.FieldHolder, pledge.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{pledge.FieldHolder, pledge.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	holder := args[pledge.FieldHolder].(string)
+	partition := args[pledge.FieldPartition].(string)
+
+	balance, err := token.BalanceOfByPartition(ctx, holder, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pledgedAmount, err := pledge.GetPledgedAmount(ctx, holder, partition, "")
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	free := balance - pledgedAmount
+	if free < 0 {
+		free = 0
+	}
+
+	retData := map[string]interface{}{
+		pledge.FieldHolder:    holder,
+		pledge.FieldPartition: partition,
+		pledge.FieldBalance:   balance,
+		pledge.FieldPledged:   pledgedAmount,
+		pledge.FieldFree:      free,
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// holder, pledgee, partition, status 로 필터
+func (s *SmartContract) GetPledgeList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = pledge.GetPledgeList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _pledgeArgs pledgeId 인자 확인 후 호출자와 질권
+func _pledgeArgs(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (string, *pledge.PledgeStruct, error) {
+
+	requireParameterFields := []string{pledge.FieldPledgeId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return "", nil, err
+	}
+
+	stringParameterFields := []string{pledge.FieldPledgeId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return "", nil, err
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	targetPledge, err := pledge.GetPledge(ctx, args[pledge.FieldPledgeId].(string))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return caller, targetPledge, nil
+}
+
+func _pledgeResponse(ctx contractapi.TransactionContextInterface, targetPledge *pledge.PledgeStruct, eventType string) (*ccutils.Response, error) {
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: eventType, From: targetPledge.Holder, To: targetPledge.Pledgee, Partition: targetPledge.Partition, Amount: targetPledge.Amount}
+	err := transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(targetPledge)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
@@ -0,0 +1,405 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/blocklist"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/freeze"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/governance"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/hold"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/kyc"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/limits"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/operator"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pendingtransfer"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pledge"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/recovery"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/vesting"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// 새 identity 로 CreateWallet 한 투자자가 분실한 old wallet 의 이전을 요청
+func (s *SmartContract) RequestWalletRecovery(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{recovery.FieldOldAddress}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{recovery.FieldOldAddress}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	newAddress, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = wallet.CheckNotSuperseded(ctx, newAddress)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	oldAddress := args[recovery.FieldOldAddress].(string)
+
+	err = wallet.CheckNotSuperseded(ctx, oldAddress)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	mspId, subject, issuer, err := ccutils.GetX509Identity(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request := recovery.RecoveryRequestStruct{OldAddress: oldAddress, NewAddress: newAddress, MSPID: mspId, Subject: subject, Issuer: issuer}
+
+	newRequest, err := recovery.CreateRequest(ctx, request)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RecoveryRequested", From: oldAddress, To: newAddress, Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newRequest)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// compliance 승인 (승인 정책이 있으면 M-of-N proposal 생성) 후 wallet 이전 실행
+func (s *SmartContract) ApproveWalletRecovery(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{recovery.FieldRequestId}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{recovery.FieldRequestId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	approver := ccutils.GetAddress([]byte(id))
+	requestId := args[recovery.FieldRequestId].(string)
+
+	request, err := recovery.GetRequest(ctx, requestId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if request.Status != recovery.StatusPending {
+		return ccutils.GenerateErrorResponse(ccutils.CreateError(recovery.CodeErrorRequestNotPending, fmt.Errorf(recovery.ErrorCodeMessage[recovery.CodeErrorRequestNotPending]+" : "+requestId)))
+	}
+
+	proposal, err := _proposeIfRequired(ctx, ActionRecoverWallet, approver, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if proposal != nil {
+		return _proposalResponse(ctx, proposal)
+	}
+
+	err = _recoverWallet(ctx, approver, requestId)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+}
+
+func (s *SmartContract) RejectWalletRecovery(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{recovery.FieldRequestId}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{recovery.FieldRequestId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	rejector := ccutils.GetAddress([]byte(id))
+
+	request, err := recovery.GetRequest(ctx, args[recovery.FieldRequestId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request, err = recovery.CloseRequest(ctx, *request, recovery.StatusRejected, rejector)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RecoveryRejected", From: request.OldAddress, To: request.NewAddress, Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(request)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetWalletRecovery(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{recovery.FieldRequestId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{recovery.FieldRequestId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	request, err := recovery.GetRequest(ctx, args[recovery.FieldRequestId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(request)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetWalletRecoveryList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = recovery.GetRequestList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _recoverWallet 잔고, allowance, operator 권한, holder list 항목, 취득 이력, 의결권 위임을 old wallet 에서 new wallet 으로 이전.
+// snapshot 잔고는 old wallet 에 남고 new wallet 의 RecoveredFrom 으로 찾음
+func _recoverWallet(ctx contractapi.TransactionContextInterface, actor string, requestId string) error {
+
+	request, err := recovery.GetRequest(ctx, requestId)
+	if err != nil {
+		return err
+	}
+
+	err = _checkRecoverable(ctx, request.OldAddress, request.NewAddress)
+	if err != nil {
+		return err
+	}
+
+	request, err = recovery.CloseRequest(ctx, *request, recovery.StatusExecuted, actor)
+	if err != nil {
+		return err
+	}
+
+	partitions, err := wallet.MigrateWallet(ctx, request.OldAddress, request.NewAddress)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		err = token.MigrateHolder(ctx, partition, request.OldAddress, request.NewAddress)
+		if err != nil {
+			return err
+		}
+
+		err = vesting.MigrateSchedule(ctx, partition, request.OldAddress, request.NewAddress)
+		if err != nil {
+			return err
+		}
+
+		err = limits.MigrateAcquisition(ctx, partition, request.OldAddress, request.NewAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = token.MigrateAllowances(ctx, request.OldAddress, request.NewAddress)
+	if err != nil {
+		return err
+	}
+
+	err = operator.MigrateOperator(ctx, request.OldAddress, request.NewAddress)
+	if err != nil {
+		return err
+	}
+
+	err = kyc.MigrateInvestor(ctx, request.OldAddress, request.NewAddress, actor)
+	if err != nil {
+		return err
+	}
+
+	err = governance.MigrateVoting(ctx, request.OldAddress, request.NewAddress)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "WalletRecovered", From: request.OldAddress, To: request.NewAddress, Partition: "", Amount: 0}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+// _checkRecoverable 주소 기준으로 남는 제한이 old wallet 에 걸려 있으면 복구하지 않음 (먼저 해제해야 함)
+func _checkRecoverable(ctx contractapi.TransactionContextInterface, oldAddress string, newAddress string) error {
+
+	err := blocklist.CheckNotBlocked(ctx, oldAddress, newAddress)
+	if err != nil {
+		return err
+	}
+
+	oldWallet, err := wallet.GetWallet(ctx, oldAddress)
+	if err != nil {
+		return err
+	}
+
+	partitions := []string{}
+	for partition := range oldWallet.PartitionTokens {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+
+	// wallet 전체 동결
+	_, err = freeze.GetFrozenAmount(ctx, oldAddress, freeze.PartitionAll)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		frozenAmount, err := freeze.GetFrozenAmount(ctx, oldAddress, partition)
+		if err != nil {
+			return err
+		}
+
+		if frozenAmount > 0 {
+			return ccutils.CreateError(recovery.CodeErrorWalletEncumbered, fmt.Errorf(recovery.ErrorCodeMessage[recovery.CodeErrorWalletEncumbered]+" : partition %s, frozen %d", partition, frozenAmount))
+		}
+
+		reservedAmount, err := pendingtransfer.GetReservedAmount(ctx, oldAddress, partition, "")
+		if err != nil {
+			return err
+		}
+
+		if reservedAmount > 0 {
+			return ccutils.CreateError(recovery.CodeErrorWalletEncumbered, fmt.Errorf(recovery.ErrorCodeMessage[recovery.CodeErrorWalletEncumbered]+" : partition %s, reserved %d", partition, reservedAmount))
+		}
+
+		heldAmount, err := hold.GetHeldAmount(ctx, oldAddress, partition, "")
+		if err != nil {
+			return err
+		}
+
+		if heldAmount > 0 {
+			return ccutils.CreateError(recovery.CodeErrorWalletEncumbered, fmt.Errorf(recovery.ErrorCodeMessage[recovery.CodeErrorWalletEncumbered]+" : partition %s, held %d", partition, heldAmount))
+		}
+
+		pledgedAmount, err := pledge.GetPledgedAmount(ctx, oldAddress, partition, "")
+		if err != nil {
+			return err
+		}
+
+		if pledgedAmount > 0 {
+			return ccutils.CreateError(recovery.CodeErrorWalletEncumbered, fmt.Errorf(recovery.ErrorCodeMessage[recovery.CodeErrorWalletEncumbered]+" : partition %s, pledged %d", partition, pledgedAmount))
+		}
+	}
+
+	// 질권자 권리도 주소 기준
+	isPledgee, err := pledge.HasPledgeeRights(ctx, oldAddress)
+	if err != nil {
+		return err
+	}
+
+	if isPledgee {
+		return ccutils.CreateError(recovery.CodeErrorWalletEncumbered, fmt.Errorf(recovery.ErrorCodeMessage[recovery.CodeErrorWalletEncumbered]+" : %s is a pledgee of active pledges", oldAddress))
+	}
+
+	return nil
+}
+
+// _recoveryChain address 의 최종 대체 wallet 과 그 wallet 에 복구로 합쳐진 이전 wallet 목록. wallet 이 없으면 address 그대로
+func _recoveryChain(ctx contractapi.TransactionContextInterface, address string) (string, []string, error) {
+
+	exist, err := ledgermanager.CheckExistState(address, ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !exist {
+		return address, []string{}, nil
+	}
+
+	active, err := _activeWallet(ctx, address)
+	if err != nil {
+		return "", nil, err
+	}
+
+	activeWallet, err := wallet.GetWallet(ctx, active)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return active, activeWallet.RecoveredFrom, nil
+}
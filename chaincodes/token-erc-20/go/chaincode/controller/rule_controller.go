@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules/countries"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules/issuerapproval"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules/lockup"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules/maxholders"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules/maxholding"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules/mintransfer"
+)
+
+// 사용 가능한 rule module. 새 rule 은 package 를 추가하고 여기에 등록
+func init() {
+	rules.Register(
+		lockup.Module{},
+		maxholders.Module{},
+		maxholding.Module{},
+		mintransfer.Module{},
+		countries.Module{},
+		issuerapproval.Module{},
+	)
+}
+
+// partition 의 rule 목록 전체를 교체. rules 예시
+// [{"type":"lockup","params":{"releaseAt":1735689600}},{"type":"maxHolders","params":{"maxHolders":49}}]
+func (s *SmartContract) SetPartitionRules(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{rules.FieldPartition, rules.FieldRules}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{rules.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	arrayParameterFields := []string{rules.FieldRules}
+	err = ccutils.CheckRequireTypeArray(arrayParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partitionRules := rules.PartitionRulesStruct{}
+	partitionRules.Partition = args[rules.FieldPartition].(string)
+	partitionRules.UpdatedBy = ccutils.GetAddress([]byte(id))
+	partitionRules.Rules = []rules.RuleConfig{}
+
+	for _, value := range args[rules.FieldRules].([]interface{}) {
+		rule, ok := value.(map[string]interface{})
+		if !ok {
+			return ccutils.GenerateErrorResponse(fmt.Errorf("rule must be an object"))
+		}
+
+		err = ccutils.CheckRequireParameter([]string{rules.FieldType}, rule)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = ccutils.CheckRequireTypeString([]string{rules.FieldType}, rule)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		err = ccutils.CheckType(reflect.Map, []string{rules.FieldParams}, rule)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+
+		ruleConfig := rules.RuleConfig{Type: rule[rules.FieldType].(string), Params: map[string]interface{}{}}
+		if params, exist := rule[rules.FieldParams]; exist {
+			ruleConfig.Params = params.(map[string]interface{})
+		}
+
+		partitionRules.Rules = append(partitionRules.Rules, ruleConfig)
+	}
+
+	newRules, err := rules.SetRules(ctx, partitionRules)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newRules)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetPartitionRules(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{rules.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{rules.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partitionRules, err := rules.GetRules(ctx, args[rules.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(partitionRules)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetRuleTypes(ctx contractapi.TransactionContextInterface) (*ccutils.Response, error) {
+
+	retData := map[string]interface{}{rules.FieldRuleTypes: rules.ModuleTypes()}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// ERC-1400 canTransfer. 실제 이전 없이 partition rule 평가 결과(reason code)만 반환
+func (s *SmartContract) CanTransferByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{rules.FieldPartition, rules.FieldFrom, rules.FieldTo, rules.FieldAmount}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{rules.FieldPartition, rules.FieldFrom, rules.FieldTo}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{rules.FieldAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := args[rules.FieldPartition].(string)
+	from := args[rules.FieldFrom].(string)
+	to := args[rules.FieldTo].(string)
+	amount := int64(args[rules.FieldAmount].(float64))
+
+	err = _checkDebitByPartition(ctx, from, partition, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkCreditByPartition(ctx, to, partition, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkRules(ctx, rules.OperationTransfer, partition, from, to, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+}
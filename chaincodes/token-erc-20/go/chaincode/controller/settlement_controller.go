@@ -0,0 +1,361 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/dividend"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/offering"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/settlement"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// 발행사가 partition 의 cash leg 결제 방식 설정 (internal partition 또는 외부 token chaincode).
+// 진행 중인 offering / dividend / 상환 대금은 자금을 받은 방식으로 지급해야 하므로 모두 끝난 뒤에만 결제 경로를 바꿀 수 있음
+func (s *SmartContract) SetSettlementConfig(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{settlement.FieldPartition, settlement.FieldMode}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{settlement.FieldPartition, settlement.FieldMode}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	optionalStringFields := []string{settlement.FieldCashPartition, settlement.FieldChaincodeName, settlement.FieldChannel, settlement.FieldAccount, settlement.FieldDebitFunction, settlement.FieldCreditFunction}
+	err = ccutils.CheckTypeString(optionalStringFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{settlement.FieldRedemptionPrice}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	updatedBy, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	config := settlement.SettlementConfigStruct{}
+	config.Partition = args[settlement.FieldPartition].(string)
+	config.Mode = args[settlement.FieldMode].(string)
+	config.UpdatedBy = updatedBy
+
+	optionalStrings := map[string]*string{
+		settlement.FieldCashPartition:  &config.CashPartition,
+		settlement.FieldChaincodeName:  &config.ChaincodeName,
+		settlement.FieldChannel:        &config.Channel,
+		settlement.FieldAccount:        &config.Account,
+		settlement.FieldDebitFunction:  &config.DebitFunction,
+		settlement.FieldCreditFunction: &config.CreditFunction,
+	}
+	for field, target := range optionalStrings {
+		if value, exist := args[field]; exist {
+			*target = value.(string)
+		}
+	}
+
+	if value, exist := args[settlement.FieldRedemptionPrice]; exist {
+		config.RedemptionPrice = int64(value.(float64))
+	}
+
+	existing, err := settlement.GetConfig(ctx, config.Partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if settlement.RoutingChanged(existing, config) {
+		err = _checkNoOpenSettlementFlows(ctx, config.Partition)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	newConfig, err := settlement.SetConfig(ctx, config)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newConfig)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 설정이 없으면 internal mode 로 응답
+func (s *SmartContract) GetSettlementConfig(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{settlement.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{settlement.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := args[settlement.FieldPartition].(string)
+
+	config, err := settlement.GetConfig(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if config == nil {
+		config = &settlement.SettlementConfigStruct{DocType: settlement.DocType_SettlementConfig, Partition: partition, Mode: settlement.ModeInternal}
+	}
+
+	retData, err := ccutils.StructToMap(config)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 발행사가 상환 대금 재원을 결제 계정에 적립
+func (s *SmartContract) FundRedemption(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{settlement.FieldPartition, settlement.FieldAmount}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{settlement.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{settlement.FieldAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	issuer, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := args[settlement.FieldPartition].(string)
+	amount := int64(args[settlement.FieldAmount].(float64))
+
+	config, err := settlement.GetConfig(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if config == nil || config.RedemptionPrice == 0 {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("redemption price is not set for partition %s", partition))
+	}
+
+	config, err = settlement.AddRedemptionFunds(ctx, *config, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	adapter, err := _settlementAdapter(ctx, partition, config.CashPartition, settlement.EscrowName)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = adapter.Debit(ctx, issuer, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RedemptionFunded", From: issuer, To: "", Partition: config.CashPartition, Amount: amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(config)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 상환 대금 지급. chaincode mode 에서는 외부 chaincode 가 제출자를 결제 계정의 operator 로 인정해야 통과
+func (s *SmartContract) PayRedemptionPayout(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{settlement.FieldPayoutId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{settlement.FieldPayoutId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	payout, err := settlement.GetPayout(ctx, args[settlement.FieldPayoutId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 키 분실로 복구된 wallet 의 대금은 새 wallet 으로 지급
+	payee, err := _activeWallet(ctx, payout.Holder)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	payout, err = settlement.MarkPaid(ctx, *payout, payee)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	adapter, err := _settlementAdapter(ctx, payout.Partition, payout.CashPartition, settlement.EscrowName)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = adapter.Credit(ctx, []settlement.PaymentStruct{{To: payee, Amount: payout.Amount}})
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "RedemptionPaid", From: "", To: payee, Partition: payout.CashPartition, Amount: payout.Amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(payout)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetRedemptionPayout(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{settlement.FieldPayoutId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{settlement.FieldPayoutId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	payout, err := settlement.GetPayout(ctx, args[settlement.FieldPayoutId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(payout)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetRedemptionPayoutList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = settlement.GetPayoutList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _settlementAdapter partition 의 결제 설정에 맞는 cash leg adapter.
+// internal mode 는 flow 별 escrow 를 결제 계정으로 쓰고, 출금 제한은 _checkDebitByPartition 으로 확인.
+// chaincode mode 는 제출자 wallet 과 발행사/operator 여부를 넘겨 외부 chaincode 호출 권한을 확인
+func _settlementAdapter(ctx contractapi.TransactionContextInterface, partition string, cashPartition string, escrowName string) (settlement.Adapter, error) {
+
+	config, err := settlement.GetConfig(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	submitter := ""
+	pusher := false
+	if config != nil && config.Mode == settlement.ModeChaincode {
+		submitter, err = _callerWallet(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		pusher = access.CheckRole(ctx, access.RoleIssuer, access.RoleOperator) == nil
+	}
+
+	return settlement.NewAdapter(config, cashPartition, wallet.EscrowAddress(escrowName), _checkDebitByPartition, submitter, pusher), nil
+}
+
+// _checkNoOpenSettlementFlows partition 의 결제 설정으로 자금을 받았지만 아직 지급이 끝나지 않은 flow 가 있으면 에러
+func _checkNoOpenSettlementFlows(ctx contractapi.TransactionContextInterface, partition string) error {
+
+	checks := []func(ctx contractapi.TransactionContextInterface, partition string) (bool, error){
+		offering.HasOpenOffering,
+		dividend.HasActiveDistribution,
+		settlement.HasPendingPayout,
+	}
+
+	for _, check := range checks {
+		exist, err := check(ctx, partition)
+		if err != nil {
+			return err
+		}
+
+		if exist {
+			return ccutils.CreateError(settlement.CodeErrorOpenFlows, fmt.Errorf(settlement.ErrorCodeMessage[settlement.CodeErrorOpenFlows]+" : "+partition))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/snapshot"
+)
+
+// partition 의 기준일 snapshot 선언. 이후 BalanceOfAt / TotalSupplyAt 으로 이 시점 잔고 조회
+func (s *SmartContract) CreateSnapshot(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{snapshot.FieldPartition}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{snapshot.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{snapshot.FieldDescription}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var description string
+	if value, exist := args[snapshot.FieldDescription]; exist {
+		description = value.(string)
+	}
+
+	newSnapshot, err := snapshot.CreateSnapshot(ctx, args[snapshot.FieldPartition].(string), description, ccutils.GetAddress([]byte(id)))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "SnapshotCreated", From: "", To: "", Partition: newSnapshot.Partition, Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newSnapshot)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetSnapshot(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{snapshot.FieldPartition, snapshot.FieldSnapshotId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{snapshot.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{snapshot.FieldSnapshotId}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	snapshotStruct, err := snapshot.GetSnapshot(ctx, args[snapshot.FieldPartition].(string), int64(args[snapshot.FieldSnapshotId].(float64)))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(snapshotStruct)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetSnapshotList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = snapshot.GetSnapshotList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// snapshot 선언 시점의 holder 잔고. 복구로 합쳐진 이전 wallet 의 잔고를 포함
+func (s *SmartContract) BalanceOfAt(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{snapshot.FieldHolder, snapshot.FieldPartition, snapshot.FieldSnapshotId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{snapshot.FieldHolder, snapshot.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{snapshot.FieldSnapshotId}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	balance, err := _balanceOfAt(ctx, args[snapshot.FieldHolder].(string), args[snapshot.FieldPartition].(string), int64(args[snapshot.FieldSnapshotId].(float64)))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], balance)
+}
+
+// snapshot 선언 시점의 partition 총발행량
+func (s *SmartContract) TotalSupplyAt(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{snapshot.FieldPartition, snapshot.FieldSnapshotId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{snapshot.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{snapshot.FieldSnapshotId}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	totalSupply, err := snapshot.TotalSupplyAt(ctx, args[snapshot.FieldPartition].(string), int64(args[snapshot.FieldSnapshotId].(float64)))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], totalSupply)
+}
+
+// _balanceOfAt 복구로 대체된 wallet 의 snapshot 잔고는 최종 대체 wallet 몫으로 합산. 대체된 holder 는 0
+func _balanceOfAt(ctx contractapi.TransactionContextInterface, holder string, partition string, snapshotId int64) (int64, error) {
+
+	active, recoveredFrom, err := _recoveryChain(ctx, holder)
+	if err != nil {
+		return 0, err
+	}
+
+	if active != holder {
+		return 0, nil
+	}
+
+	var balance int64
+	for _, address := range append([]string{holder}, recoveredFrom...) {
+		addressBalance, err := snapshot.BalanceOfAt(ctx, address, partition, snapshotId)
+		if err != nil {
+			return 0, err
+		}
+		balance += addressBalance
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/dividend"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/dvp"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/freeze"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/hold"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/limits"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/offering"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/orderbook"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pendingtransfer"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pledge"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/settlement"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/split"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/vesting"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// partition 주식 분할(numerator > denominator) / 병합(numerator < denominator).
+// 잔고는 옮기지 않고 scaling factor 만 기록하므로 보유자 수와 무관하게 한 번에 처리됨
+func (s *SmartContract) ExecuteSplit(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{split.FieldPartition, split.FieldNumerator, split.FieldDenominator}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{split.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{split.FieldNumerator, split.FieldDenominator}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{split.FieldRoundingPolicy}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := args[split.FieldPartition].(string)
+
+	_, err = token.GetPartitionToken(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var roundingPolicy string
+	if value, exist := args[split.FieldRoundingPolicy]; exist {
+		roundingPolicy = value.(string)
+	}
+
+	caller, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkSplittable(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	supplyBefore, err := token.TotalSupplyByPartition(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	scale, err := split.ExecuteSplit(ctx, partition, int64(args[split.FieldNumerator].(float64)), int64(args[split.FieldDenominator].(float64)), roundingPolicy, caller)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 전체 총발행량은 읽을 때 환산되지 않으므로 partition 총발행량의 변화만큼 바로 반영
+	supplyAfter, _ := scale.Scale(supplyBefore.TotalSupply, supplyBefore.ScaleEpoch, scale.Epoch)
+	err = token.AdjustTotalSupply(ctx, supplyAfter-supplyBefore.TotalSupply)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Split", From: "", To: "", Partition: partition, Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(scale)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetPartitionScale(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{split.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{split.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	scale, err := split.GetScale(ctx, args[split.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(scale)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// holder 잔고를 현재 분할 기준으로 확정하고 단수주 권리를 기록. 누구나 호출 가능 (결과는 holder 와 무관하게 같음)
+func (s *SmartContract) SettleSplit(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{split.FieldHolder, split.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{split.FieldHolder, split.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	entry, err := wallet.SettleBalance(ctx, args[split.FieldHolder].(string), args[split.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(entry)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetCashInLieuList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = split.GetCashInLieuList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _checkSplittable 분할로 환산되지 않는 수량이 partition 에 남아 있으면 분할하지 않음
+func _checkSplittable(ctx contractapi.TransactionContextInterface, partition string) error {
+
+	checks := []struct {
+		name  string
+		check func(ctx contractapi.TransactionContextInterface, partition string) (bool, error)
+	}{
+		{"freeze", freeze.HasPartialFreeze},
+		{"vesting", vesting.HasUnvestedSchedule},
+		{"limits", limits.HasLimits},
+		{"pending transfer", pendingtransfer.HasPendingRequest},
+		{"hold", hold.HasActiveHold},
+		{"pledge", pledge.HasActivePledge},
+		{"order", orderbook.HasOpenOrder},
+		{"dvp", dvp.HasOpenDvP},
+		{"offering", offering.HasOpenOffering},
+		{"dividend", dividend.HasActiveDistribution},
+		{"redemption payout", settlement.HasPendingPayout},
+	}
+
+	for _, c := range checks {
+		exist, err := c.check(ctx, partition)
+		if err != nil {
+			return err
+		}
+
+		if exist {
+			return ccutils.CreateError(split.CodeErrorPartitionEncumbered, fmt.Errorf(split.ErrorCodeMessage[split.CodeErrorPartitionEncumbered]+" : %s, %s", partition, c.name))
+		}
+	}
+
+	return nil
+}
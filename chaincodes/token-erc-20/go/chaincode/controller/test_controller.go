@@ -38,7 +38,7 @@ func (s *SmartContract) GetHolderList(ctx contractapi.TransactionContextInterfac
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "tokenHolderList", "", "", partition, 0}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "tokenHolderList", From: "", To: "", Partition: partition, Amount: 0}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
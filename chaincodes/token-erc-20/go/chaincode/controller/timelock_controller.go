@@ -0,0 +1,460 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/ownership"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/timelock"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+)
+
+type timelockAction struct {
+	category string
+	// 예약 시점에 실행 파라메터를 미리 검사
+	validate func(args map[string]interface{}) error
+}
+
+// timelock 을 거쳐야 하는 action 과 category
+var timelockActions = map[string]timelockAction{
+	ActionSetSupplyCap: {
+		category: timelock.CategorySupplyCap,
+		validate: func(args map[string]interface{}) error {
+			err := ccutils.CheckRequireTypeString([]string{token.FieldPartition}, args)
+			if err != nil {
+				return err
+			}
+			return ccutils.CheckRequireTypeInt64([]string{token.FieldSupplyCap}, args)
+		},
+	},
+	ActionSetControllable: {
+		category: timelock.CategoryControllability,
+		validate: func(args map[string]interface{}) error {
+			err := ccutils.CheckRequireTypeString([]string{token.FieldPartition}, args)
+			if err != nil {
+				return err
+			}
+			return ccutils.CheckRequireTypeBool([]string{token.FieldIsControllable}, args)
+		},
+	},
+	ActionRotateAdminKey: {
+		category: timelock.CategoryAdminKey,
+		validate: func(args map[string]interface{}) error {
+			return ccutils.CheckRequireTypeString([]string{access.FieldOldAdmin, access.FieldNewAdmin}, args)
+		},
+	},
+	ActionGrantAdminRole: {
+		category: timelock.CategoryAdminKey,
+		validate: func(args map[string]interface{}) error {
+			return ccutils.CheckRequireTypeString([]string{access.FieldSubjectType, access.FieldSubject}, args)
+		},
+	},
+	ActionRevokeAdminRole: {
+		category: timelock.CategoryAdminKey,
+		validate: func(args map[string]interface{}) error {
+			return ccutils.CheckRequireTypeString([]string{access.FieldSubjectType, access.FieldSubject}, args)
+		},
+	},
+	ActionSetContractConfig: {
+		category: timelock.CategoryContractConfig,
+		validate: func(args map[string]interface{}) error {
+			config, err := ownership.ParseConfig(args)
+			if err != nil {
+				return err
+			}
+			_, err = ownership.NormalizeConfig(*config)
+			return err
+		},
+	},
+	ActionSetTimelockDelay: {
+		category: timelock.CategoryTimelockDelay,
+		validate: func(args map[string]interface{}) error {
+			err := ccutils.CheckRequireTypeString([]string{timelock.FieldCategory}, args)
+			if err != nil {
+				return err
+			}
+			return ccutils.CheckRequireTypeInt64([]string{timelock.FieldDelaySeconds}, args)
+		},
+	},
+}
+
+// category 별 지연 시간 설정. 즉시 적용은 늘리는 경우만 가능하고 단축은 ScheduleAction 으로 예약
+func (s *SmartContract) SetTimelockDelay(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{timelock.FieldCategory, timelock.FieldDelaySeconds}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{timelock.FieldCategory}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{timelock.FieldDelaySeconds}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	category := args[timelock.FieldCategory].(string)
+	delaySeconds := int64(args[timelock.FieldDelaySeconds].(float64))
+
+	err = timelock.CheckDelayIncrease(ctx, category, delaySeconds)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	delay, err := timelock.SetDelay(ctx, category, delaySeconds)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(delay)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetTimelockDelay(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{timelock.FieldCategory}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{timelock.FieldCategory}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	delay, err := timelock.GetDelay(ctx, args[timelock.FieldCategory].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(delay)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// action 을 예약. 실행 가능 시간은 트랜잭션 timestamp + category 지연 시간
+func (s *SmartContract) ScheduleAction(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{timelock.FieldActionType, timelock.FieldArgs}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{timelock.FieldActionType}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckRequireTypeObject([]string{timelock.FieldArgs}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	actionType := args[timelock.FieldActionType].(string)
+	actionArgs := args[timelock.FieldArgs].(map[string]interface{})
+
+	action, exist := timelockActions[actionType]
+	if !exist {
+		return ccutils.GenerateErrorResponse(fmt.Errorf("unknown timelock action type : %s", actionType))
+	}
+
+	err = action.validate(actionArgs)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	proposer := ccutils.GetAddress([]byte(id))
+
+	scheduled, err := timelock.Schedule(ctx, actionType, action.category, proposer, actionArgs)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 투자자 공지용 이벤트 (Amount 에 실행 가능 시간)
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ActionScheduled", From: proposer, To: actionType, Partition: "", Amount: scheduled.ExecutableAt}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := _timelockActionData(ctx, scheduled)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 지연 시간이 지난 action 을 예약자의 권한으로 실행
+func (s *SmartContract) ExecuteScheduledAction(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{timelock.FieldTimelockId}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{timelock.FieldTimelockId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	executor := ccutils.GetAddress([]byte(id))
+
+	action, err := timelock.GetAction(ctx, args[timelock.FieldTimelockId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// AcceptOwnership 으로 예약된 소유권 이전은 새 owner 본인도 실행 가능
+	if action.ActionType != ActionAcceptOwnership || action.Proposer != executor {
+		err = access.CheckRole(ctx, access.RoleAdmin)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	action, err = timelock.MarkExecuted(ctx, *action, executor)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _executeAction(ctx, action.ActionType, action.Proposer, action.Args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := _timelockActionData(ctx, action)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 지연 시간이 지나기 전에만 취소 가능
+func (s *SmartContract) CancelScheduledAction(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{timelock.FieldTimelockId}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{timelock.FieldTimelockId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	canceller := ccutils.GetAddress([]byte(id))
+
+	action, err := timelock.GetAction(ctx, args[timelock.FieldTimelockId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	action, err = timelock.Cancel(ctx, *action, canceller)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ActionCancelled", From: canceller, To: action.ActionType, Partition: "", Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := _timelockActionData(ctx, action)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetScheduledAction(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{timelock.FieldTimelockId}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{timelock.FieldTimelockId}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	action, err := timelock.GetAction(ctx, args[timelock.FieldTimelockId].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := _timelockActionData(ctx, action)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetScheduledActionList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = timelock.GetActionList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// _timelockActionData 실행 가능 시간을 config 의 timezone 날짜로 함께 표시
+func _timelockActionData(ctx contractapi.TransactionContextInterface, action *timelock.TimelockActionStruct) (map[string]interface{}, error) {
+
+	retData, err := ccutils.StructToMap(action)
+	if err != nil {
+		return nil, err
+	}
+
+	executableDate, err := ownership.FormatTimestamp(ctx, action.ExecutableAt)
+	if err != nil {
+		return nil, err
+	}
+	retData["executableDate"] = executableDate
+
+	return retData, nil
+}
+
+func _setSupplyCap(ctx contractapi.TransactionContextInterface, actor string, partition string, supplyCap int64) error {
+
+	_, err := token.SetSupplyCap(ctx, partition, supplyCap)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "SupplyCapChanged", From: actor, To: "", Partition: partition, Amount: supplyCap}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+func _setControllable(ctx contractapi.TransactionContextInterface, actor string, partition string, isControllable bool) error {
+
+	_, err := token.SetControllable(ctx, partition, isControllable)
+	if err != nil {
+		return err
+	}
+
+	var flag int64
+	if isControllable {
+		flag = 1
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ControllabilityChanged", From: actor, To: "", Partition: partition, Amount: flag}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+func _setTimelockDelay(ctx contractapi.TransactionContextInterface, actor string, category string, delaySeconds int64) error {
+
+	_, err := timelock.SetDelay(ctx, category, delaySeconds)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "TimelockDelayChanged", From: actor, To: category, Partition: "", Amount: delaySeconds}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+// _rotateAdminKey oldAdmin 주소의 admin role 을 newAdmin 주소로 옮김
+func _rotateAdminKey(ctx contractapi.TransactionContextInterface, actor string, oldAdmin string, newAdmin string) error {
+
+	if oldAdmin == newAdmin {
+		return fmt.Errorf("new admin is the same as old admin")
+	}
+
+	oldRole, err := access.GetRole(ctx, access.RoleAdmin, access.SubjectTypeAddress, oldAdmin)
+	if err != nil {
+		return err
+	}
+
+	if oldRole == nil {
+		return fmt.Errorf("%s does not have the admin role", oldAdmin)
+	}
+
+	adminRole := access.RoleStruct{Role: access.RoleAdmin, SubjectType: access.SubjectTypeAddress, Subject: newAdmin, GrantedBy: actor}
+	_, err = access.GrantRole(ctx, adminRole)
+	if err != nil {
+		return err
+	}
+
+	err = access.RevokeRole(ctx, access.RoleAdmin, access.SubjectTypeAddress, oldAdmin)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "AdminKeyRotated", From: oldAdmin, To: newAdmin, Partition: "", Amount: 0}
+	return transferEvent.EmitTransferEvent(ctx)
+}
@@ -4,12 +4,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"reflect"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/blocklist"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/kyc"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/operator"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/settlement"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
 )
@@ -25,7 +31,7 @@ type SmartContract struct {
  */
 func (s *SmartContract) TotalSupply(ctx contractapi.TransactionContextInterface) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return nil, err
@@ -41,7 +47,7 @@ func (s *SmartContract) TotalSupply(ctx contractapi.TransactionContextInterface)
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	log.Printf("TotalSupply: %d tokens", totalSupply)
+	log.Printf("TotalSupply: %d tokens", totalSupply.TotalSupply)
 
 	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
 }
@@ -52,7 +58,7 @@ func (s *SmartContract) TotalSupply(ctx contractapi.TransactionContextInterface)
  */
 func (s *SmartContract) TotalSupplyByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -85,9 +91,36 @@ func (s *SmartContract) TotalSupplyByPartition(ctx contractapi.TransactionContex
 	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
 }
 
+// ERC1644 isControllable
+func (s *SmartContract) IsControllable(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{token.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{token.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	partition := args[token.FieldPartition].(string)
+
+	isControllable, err := token.IsControllable(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData := map[string]interface{}{token.FieldPartition: partition, token.FieldIsControllable: isControllable}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
 func (s *SmartContract) BalanceOfByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return nil, err
@@ -118,7 +151,7 @@ func (s *SmartContract) BalanceOfByPartition(ctx contractapi.TransactionContextI
 
 func (s *SmartContract) AllowanceByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return nil, err
@@ -155,17 +188,12 @@ func (s *SmartContract) AllowanceByPartition(ctx contractapi.TransactionContextI
 
 func (s *SmartContract) ApproveByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	id, err := ccutils.GetID(ctx)
-	if err != nil {
-		return ccutils.GenerateErrorResponse(err)
-	}
-
 	requireParameterFields := []string{token.FieldSpender, token.FieldPartition, token.FieldAmount}
 	err = ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
@@ -184,17 +212,26 @@ func (s *SmartContract) ApproveByPartition(ctx contractapi.TransactionContextInt
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	owner := ccutils.GetAddress([]byte(id))
+	owner, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	spender := args[token.FieldSpender].(string)
 	partition := args[token.FieldPartition].(string)
 	amount := int64(args[token.FieldAmount].(float64))
 
+	err = blocklist.CheckNotBlocked(ctx, owner, spender)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	err = _approveByPartition(ctx, owner, spender, partition, amount)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Approval", owner, spender, partition, amount}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Approval", From: owner, To: spender, Partition: partition, Amount: amount}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -205,17 +242,12 @@ func (s *SmartContract) ApproveByPartition(ctx contractapi.TransactionContextInt
 
 func (s *SmartContract) IncreaseAllowanceByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	id, err := ccutils.GetID(ctx)
-	if err != nil {
-		return ccutils.GenerateErrorResponse(err)
-	}
-
 	requireParameterFields := []string{token.FieldSpender, token.FieldPartition, token.FieldAmount}
 	err = ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
@@ -235,7 +267,11 @@ func (s *SmartContract) IncreaseAllowanceByPartition(ctx contractapi.Transaction
 	}
 
 	// args Data
-	owner := ccutils.GetAddress([]byte(id))
+	owner, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	spender := args[token.FieldSpender].(string)
 	partition := args[token.FieldPartition].(string)
 	addedValue := int64(args[token.FieldAmount].(float64))
@@ -244,6 +280,12 @@ func (s *SmartContract) IncreaseAllowanceByPartition(ctx contractapi.Transaction
 		return nil, fmt.Errorf("addValue cannot be negative")
 	}
 
+	// allowance 감소는 차단된 상대방에 대한 노출을 줄이므로 허용
+	err = blocklist.CheckNotBlocked(ctx, owner, spender)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	allowanceByPartition, err := token.AllowanceByPartition(ctx, owner, spender, partition)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -256,7 +298,7 @@ func (s *SmartContract) IncreaseAllowanceByPartition(ctx contractapi.Transaction
 		return nil, err
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Approval", owner, spender, partition, addedValue}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Approval", From: owner, To: spender, Partition: partition, Amount: addedValue}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -267,17 +309,12 @@ func (s *SmartContract) IncreaseAllowanceByPartition(ctx contractapi.Transaction
 
 func (s *SmartContract) DecreaseAllowanceByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := ccutils.GetID(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	requireParameterFields := []string{token.FieldSpender, token.FieldPartition, token.FieldAmount}
 	err = ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
@@ -297,7 +334,11 @@ func (s *SmartContract) DecreaseAllowanceByPartition(ctx contractapi.Transaction
 	}
 
 	// args Data
-	owner := ccutils.GetAddress([]byte(id))
+	owner, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	spender := args[token.FieldSpender].(string)
 	partition := args[token.FieldPartition].(string)
 	subtractedValue := int64(args[token.FieldAmount].(float64))
@@ -322,7 +363,7 @@ func (s *SmartContract) DecreaseAllowanceByPartition(ctx contractapi.Transaction
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Approval", owner, spender, partition, subtractedValue}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Approval", From: owner, To: spender, Partition: partition, Amount: subtractedValue}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -345,7 +386,7 @@ func _approveByPartition(ctx contractapi.TransactionContextInterface, owner stri
 
 func (s *SmartContract) IssueToken(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return nil, err
@@ -374,18 +415,55 @@ func (s *SmartContract) IssueToken(ctx contractapi.TransactionContextInterface,
 		return ccutils.GenerateErrorResponse(err)
 	}
 
+	// 0 이면 무제한. 발행 후 변경은 timelock (SetSupplyCap) 으로만 가능
+	err = ccutils.CheckTypeInt64([]string{token.FieldSupplyCap}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// true 면 KYC 검증된 투자자만 수령 가능. 이후 SetPartitionAllowlist 로 변경
+	err = ccutils.CheckType(reflect.Bool, []string{kyc.FieldAllowlistRequired}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	partition := args[token.FieldPartition].(string)
 
+	err = pause.CheckNotPaused(ctx, partition, pause.OperationIssuance)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = blocklist.CheckNotBlocked(ctx, address)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	newToken := token.PartitionToken{}
 	newToken.Publisher = address
 	newToken.TokenID = partition
 	newToken.IsLocked = false
+	newToken.IsControllable = true
 
 	asset, err := token.IssueToken(ctx, newToken)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
 
+	if value, exist := args[token.FieldSupplyCap]; exist {
+		_, err = token.SetSupplyCap(ctx, partition, int64(value.(float64)))
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	if value, exist := args[kyc.FieldAllowlistRequired]; exist {
+		_, err = kyc.SetPartitionKyc(ctx, kyc.PartitionKycStruct{Partition: partition, AllowlistRequired: value.(bool)})
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
 	// 임시 admin wallet
 	adminBytes, err := ledgermanager.GetState(wallet.DocType_AdminWallet, "AdminWallet", ctx)
 	if err != nil {
@@ -432,7 +510,7 @@ func (s *SmartContract) IssueToken(ctx contractapi.TransactionContextInterface,
 		return nil, err
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Issue", address, "", partition, 0}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Issue", From: address, To: "", Partition: partition, Amount: 0}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -448,7 +526,7 @@ func (s *SmartContract) IssueToken(ctx contractapi.TransactionContextInterface,
 
 func (s *SmartContract) UndoIssueToken(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return nil, err
@@ -479,15 +557,16 @@ func (s *SmartContract) UndoIssueToken(ctx contractapi.TransactionContextInterfa
 
 	partition := args[token.FieldPartition].(string)
 
-	tokenStruct := token.PartitionToken{Publisher: address, TokenID: partition}
-
-	asset, err := token.UndoIssueToken(ctx, tokenStruct)
+	proposal, err := _proposeIfRequired(ctx, ActionUndoIssueToken, address, args)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "UndoIssue", address, "", partition, 0}
-	err = transferEvent.EmitTransferEvent(ctx)
+	if proposal != nil {
+		return _proposalResponse(ctx, proposal)
+	}
+
+	asset, err := _undoIssueToken(ctx, address, partition)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
@@ -500,15 +579,28 @@ func (s *SmartContract) UndoIssueToken(ctx contractapi.TransactionContextInterfa
 	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
 }
 
-func (s *SmartContract) RedeemToken(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+func _undoIssueToken(ctx contractapi.TransactionContextInterface, publisher string, partition string) (*token.PartitionToken, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
-	err := ccutils.GetMSPID(ctx)
+	tokenStruct := token.PartitionToken{Publisher: publisher, TokenID: partition}
+
+	asset, err := token.UndoIssueToken(ctx, tokenStruct)
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := ccutils.GetID(ctx)
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "UndoIssue", From: publisher, To: "", Partition: partition, Amount: 0}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+func (s *SmartContract) RedeemToken(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
+	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -526,9 +618,34 @@ func (s *SmartContract) RedeemToken(ctx contractapi.TransactionContextInterface,
 	}
 
 	// args Data
-	holder := ccutils.GetAddress([]byte(id))
+	holder, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	partition := args[token.FieldPartition].(string)
 
+	err = pause.CheckNotPaused(ctx, partition, pause.OperationRedemption)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// 상환은 partition 잔고 전체를 출금
+	balance, err := token.BalanceOfByPartition(ctx, holder, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkDebitByPartition(ctx, holder, partition, balance)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkRules(ctx, rules.OperationRedemption, partition, holder, "", balance)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	redeemStruct := token.RedeemTokenStruct{Holder: holder, Partition: partition}
 
 	asset, err := wallet.RedeemToken(ctx, redeemStruct)
@@ -536,7 +653,21 @@ func (s *SmartContract) RedeemToken(ctx contractapi.TransactionContextInterface,
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Redeem", holder, "", partition, 0}
+	// 상환 대금이 설정된 partition 은 대금 지급 건을 만들고 PayRedemptionPayout 으로 별도 지급
+	config, err := settlement.GetConfig(ctx, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	var payout *settlement.RedemptionPayoutStruct
+	if config != nil && config.RedemptionPrice > 0 && balance > 0 {
+		payout, err = settlement.CreatePayout(ctx, *config, holder, balance)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Redeem", From: holder, To: "", Partition: partition, Amount: 0}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -547,13 +678,17 @@ func (s *SmartContract) RedeemToken(ctx contractapi.TransactionContextInterface,
 		return ccutils.GenerateErrorResponse(err)
 	}
 
+	if payout != nil {
+		retData[settlement.FieldPayoutId] = payout.PayoutId
+	}
+
 	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
 
 }
 
 func (s *SmartContract) IsIssuable(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
 		return nil, err
@@ -584,7 +719,7 @@ func (s *SmartContract) IsIssuable(ctx contractapi.TransactionContextInterface,
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "IsIssuable", "", "", partition, 0}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "IsIssuable", From: "", To: "", Partition: partition, Amount: 0}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -610,7 +745,7 @@ func (s *SmartContract) GetTokenList(ctx contractapi.TransactionContextInterface
 		return nil, err
 	}
 
-	requireParameterFields := []string{ledgermanager.PageSize, ledgermanager.Bookmark}
+	requireParameterFields := []string{ledgermanager.Bookmark}
 	err = ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -634,7 +769,11 @@ func (s *SmartContract) GetTokenList(ctx contractapi.TransactionContextInterface
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	pageSize := int32(args[ledgermanager.PageSize].(float64))
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	bookmark := args[ledgermanager.Bookmark].(string)
 
 	var bytes []byte
@@ -648,7 +787,7 @@ func (s *SmartContract) GetTokenList(ctx contractapi.TransactionContextInterface
 
 func (s *SmartContract) GetTokenHolderList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	requireParameterFields := []string{ledgermanager.PageSize, ledgermanager.Bookmark, ledgermanager.Partition}
+	requireParameterFields := []string{ledgermanager.Bookmark, ledgermanager.Partition}
 	err := ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -672,7 +811,11 @@ func (s *SmartContract) GetTokenHolderList(ctx contractapi.TransactionContextInt
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	pageSize := int32(args[ledgermanager.PageSize].(float64))
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	bookmark := args[ledgermanager.Bookmark].(string)
 	partition := args[ledgermanager.Partition].(string)
 
@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/blocklist"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/freeze"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/hold"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/kyc"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/limits"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pendingtransfer"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pledge"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/vesting"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// _checkDebitByPartition holder 의 partition 잔고에서 amount 를 빼도 되는지 확인.
+// transfer, transferFrom, operator transfer, burn, redeem 등 모든 출금 경로에서 호출
+func _checkDebitByPartition(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64) error {
+
+	return _checkDebitExceptRequest(ctx, holder, partition, amount, "")
+}
+
+// _checkDebitExceptRequest 승인 중인 pending transfer 는 자기 예약분을 빼고 확인
+func _checkDebitExceptRequest(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64, requestId string) error {
+
+	return _checkDebit(ctx, holder, partition, amount, requestId, "", "")
+}
+
+// _checkDebitExceptHold 실행 중인 hold 는 자기 hold 분을 빼고 확인
+func _checkDebitExceptHold(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64, holdId string) error {
+
+	return _checkDebit(ctx, holder, partition, amount, "", holdId, "")
+}
+
+// _checkDebitExceptPledge 집행 중인 질권은 자기 질권분을 빼고 확인
+func _checkDebitExceptPledge(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64, pledgeId string) error {
+
+	return _checkDebit(ctx, holder, partition, amount, "", "", pledgeId)
+}
+
+func _checkDebit(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64, requestId string, holdId string, pledgeId string) error {
+
+	err := blocklist.CheckNotBlocked(ctx, holder)
+	if err != nil {
+		return err
+	}
+
+	frozenAmount, err := freeze.GetFrozenAmount(ctx, holder, partition)
+	if err != nil {
+		return err
+	}
+
+	unvestedAmount, err := vesting.GetUnvestedAmount(ctx, holder, partition)
+	if err != nil {
+		return err
+	}
+
+	reservedAmount, err := pendingtransfer.GetReservedAmount(ctx, holder, partition, requestId)
+	if err != nil {
+		return err
+	}
+
+	heldAmount, err := hold.GetHeldAmount(ctx, holder, partition, holdId)
+	if err != nil {
+		return err
+	}
+
+	pledgedAmount, err := pledge.GetPledgedAmount(ctx, holder, partition, pledgeId)
+	if err != nil {
+		return err
+	}
+
+	if frozenAmount == 0 && unvestedAmount == 0 && reservedAmount == 0 && heldAmount == 0 && pledgedAmount == 0 {
+		return nil
+	}
+
+	balance, err := token.BalanceOfByPartition(ctx, holder, partition)
+	if err != nil {
+		return err
+	}
+
+	if balance-amount < frozenAmount {
+		return ccutils.CreateError(freeze.CodeErrorInsufficientUnfrozen, fmt.Errorf(freeze.ErrorCodeMessage[freeze.CodeErrorInsufficientUnfrozen]+" : balance %d, frozen %d, requested %d", balance, frozenAmount, amount))
+	}
+
+	if balance-amount < frozenAmount+unvestedAmount {
+		return ccutils.CreateError(vesting.CodeErrorInsufficientVested, fmt.Errorf(vesting.ErrorCodeMessage[vesting.CodeErrorInsufficientVested]+" : balance %d, unvested %d, requested %d", balance, unvestedAmount, amount))
+	}
+
+	if balance-amount < frozenAmount+unvestedAmount+reservedAmount {
+		return ccutils.CreateError(pendingtransfer.CodeErrorInsufficientUnreserved, fmt.Errorf(pendingtransfer.ErrorCodeMessage[pendingtransfer.CodeErrorInsufficientUnreserved]+" : balance %d, reserved %d, requested %d", balance, reservedAmount, amount))
+	}
+
+	if balance-amount < frozenAmount+unvestedAmount+reservedAmount+heldAmount {
+		return ccutils.CreateError(hold.CodeErrorInsufficientUnheld, fmt.Errorf(hold.ErrorCodeMessage[hold.CodeErrorInsufficientUnheld]+" : balance %d, held %d, requested %d", balance, heldAmount, amount))
+	}
+
+	if balance-amount < frozenAmount+unvestedAmount+reservedAmount+heldAmount+pledgedAmount {
+		return ccutils.CreateError(pledge.CodeErrorInsufficientUnpledged, fmt.Errorf(pledge.ErrorCodeMessage[pledge.CodeErrorInsufficientUnpledged]+" : balance %d, pledged %d, requested %d", balance, pledgedAmount, amount))
+	}
+
+	return nil
+}
+
+// _transferableByPartition 출금 가능한 수량. _checkDebitByPartition 과 같은 기준으로 계산
+func _transferableByPartition(ctx contractapi.TransactionContextInterface, holder string, partition string) (map[string]interface{}, error) {
+
+	balance, err := token.BalanceOfByPartition(ctx, holder, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	frozenAmount, err := freeze.GetFrozenAmount(ctx, holder, partition)
+	if err != nil {
+		// wallet 또는 partition 전체 동결
+		var frozenErr *ccutils.ErrorWithStack
+		if !errors.As(err, &frozenErr) || (frozenErr.Code != freeze.CodeErrorWalletFrozen && frozenErr.Code != freeze.CodeErrorPartitionFrozen) {
+			return nil, err
+		}
+		frozenAmount = balance
+	}
+
+	unvestedAmount, err := vesting.GetUnvestedAmount(ctx, holder, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	reservedAmount, err := pendingtransfer.GetReservedAmount(ctx, holder, partition, "")
+	if err != nil {
+		return nil, err
+	}
+
+	heldAmount, err := hold.GetHeldAmount(ctx, holder, partition, "")
+	if err != nil {
+		return nil, err
+	}
+
+	pledgedAmount, err := pledge.GetPledgedAmount(ctx, holder, partition, "")
+	if err != nil {
+		return nil, err
+	}
+
+	transferable := balance - frozenAmount - unvestedAmount - reservedAmount - heldAmount - pledgedAmount
+	if transferable < 0 {
+		transferable = 0
+	}
+
+	retData := map[string]interface{}{
+		vesting.FieldHolder:           holder,
+		vesting.FieldPartition:        partition,
+		vesting.FieldBalance:          balance,
+		vesting.FieldFrozen:           frozenAmount,
+		vesting.FieldUnvested:         unvestedAmount,
+		pendingtransfer.FieldReserved: reservedAmount,
+		hold.FieldHeld:                heldAmount,
+		pledge.FieldPledged:           pledgedAmount,
+		vesting.FieldTransferable:     transferable,
+	}
+
+	return retData, nil
+}
+
+// _checkCreditByPartition recipient 가 partition 토큰을 amount 만큼 받아도 되는지 확인.
+// transfer, mint, distribute, airdrop 등 모든 입금 경로에서 호출
+func _checkCreditByPartition(ctx contractapi.TransactionContextInterface, recipient string, partition string, amount int64) error {
+
+	err := blocklist.CheckNotBlocked(ctx, recipient)
+	if err != nil {
+		return err
+	}
+
+	err = kyc.CheckRecipient(ctx, recipient, partition)
+	if err != nil {
+		return err
+	}
+
+	return limits.CheckAcquisition(ctx, recipient, partition, amount)
+}
+
+// _recordCreditByPartition 입금이 확정된 뒤 연간 한도 계산용 취득 이력 기록
+func _recordCreditByPartition(ctx contractapi.TransactionContextInterface, recipient string, partition string, amount int64) error {
+
+	return limits.RecordAcquisition(ctx, recipient, partition, amount)
+}
+
+// _checkRules partition 에 설정된 rule module 을 순서대로 평가.
+// issuance 는 from 을, redemption 은 to 를 비워서 호출
+func _checkRules(ctx contractapi.TransactionContextInterface, operation string, partition string, from string, to string, amount int64) error {
+
+	check := rules.CheckStruct{Operation: operation, Partition: partition, From: from, To: to, Amount: amount}
+	return rules.Evaluate(ctx, check)
+}
+
+// ruleBatch 한 트랜잭션에서 여러 변동을 차례로 평가할 때, 앞서 통과했지만 아직 잔고에 반영되지 않은 변동을 누적해 rule 에 넘김.
+// escrow 가 있으면 From 대신 escrow 에서 출금되므로 From 의 잔고와 보유 여부는 바뀌지 않음
+type ruleBatch struct {
+	partition  string
+	escrow     string
+	credits    map[string]int64
+	debits     map[string]int64
+	newHolders int64
+}
+
+func _newRuleBatch(partition string, escrow string) *ruleBatch {
+	return &ruleBatch{partition: partition, escrow: escrow, credits: make(map[string]int64), debits: make(map[string]int64)}
+}
+
+// check rule 을 평가하고 통과한 변동을 누적
+func (b *ruleBatch) check(ctx contractapi.TransactionContextInterface, operation string, from string, to string, amount int64) error {
+
+	err := b.evaluate(ctx, operation, from, to, amount)
+	if err != nil {
+		return err
+	}
+
+	return b.add(ctx, from, to, amount)
+}
+
+// evaluate 누적된 변동 위에서 rule 만 평가
+func (b *ruleBatch) evaluate(ctx contractapi.TransactionContextInterface, operation string, from string, to string, amount int64) error {
+
+	check := rules.CheckStruct{Operation: operation, Partition: b.partition, From: from, To: to, Amount: amount, PendingCredit: b.credits[to], PendingDebit: b.debits[from], PendingHolders: b.newHolders, Escrowed: b.escrow != ""}
+	return rules.Evaluate(ctx, check)
+}
+
+// add 평가를 통과한 변동을 누적
+func (b *ruleBatch) add(ctx contractapi.TransactionContextInterface, from string, to string, amount int64) error {
+
+	debited := from
+	if b.escrow != "" {
+		debited = b.escrow
+	}
+
+	if to != "" {
+		delta, err := b.holderDelta(ctx, to, amount)
+		if err != nil {
+			return err
+		}
+		b.newHolders += delta
+		b.credits[to] += amount
+	}
+
+	if debited != "" {
+		delta, err := b.holderDelta(ctx, debited, -amount)
+		if err != nil {
+			return err
+		}
+		b.newHolders += delta
+		b.debits[debited] += amount
+	}
+
+	return nil
+}
+
+// holderDelta 누적된 변동 위에 change 를 더했을 때 address 의 보유자 수 증감
+func (b *ruleBatch) holderDelta(ctx contractapi.TransactionContextInterface, address string, change int64) (int64, error) {
+
+	balance, err := token.BalanceOfByPartitionOrZero(ctx, address, b.partition)
+	if err != nil {
+		return 0, err
+	}
+
+	before := balance + b.credits[address] - b.debits[address]
+	return wallet.HolderDelta(address, before, before+change), nil
+}
+
+// _checkApprovedRules 발행사가 승인한 transfer 에 대한 rule 평가. issuerApproval 은 통과
+func _checkApprovedRules(ctx contractapi.TransactionContextInterface, partition string, from string, to string, amount int64) error {
+
+	check := rules.CheckStruct{Operation: rules.OperationTransfer, Partition: partition, From: from, To: to, Amount: amount, Approved: true}
+	return rules.Evaluate(ctx, check)
+}
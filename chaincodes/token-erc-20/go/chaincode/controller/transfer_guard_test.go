@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/test"
+)
+
+const guardPartition = "BOND"
+
+func maxHoldersLedger(t *testing.T, maxHolders int64) *test.MockLedger {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(guardPartition)
+
+	ledger.Tx()
+	_, err := rules.SetRules(ledger.Ctx, rules.PartitionRulesStruct{
+		Partition: guardPartition,
+		Rules:     []rules.RuleConfig{{Type: "maxHolders", Params: map[string]interface{}{"maxHolders": float64(maxHolders)}}},
+	})
+	ledger.Check(err)
+
+	return ledger
+}
+
+func TestRuleBatchCountsNewHoldersOnIssuance(t *testing.T) {
+
+	ledger := maxHoldersLedger(t, 2)
+	ledger.Wallet("alice", "bob", "carol")
+	ledger.Tx()
+
+	batch := _newRuleBatch(guardPartition, "")
+
+	ledger.Check(batch.check(ledger.Ctx, rules.OperationIssuance, "", "alice", 10))
+	// 같은 수령인은 이미 보유자로 봄
+	ledger.Check(batch.check(ledger.Ctx, rules.OperationIssuance, "", "alice", 10))
+	ledger.Check(batch.check(ledger.Ctx, rules.OperationIssuance, "", "bob", 10))
+
+	if err := batch.check(ledger.Ctx, rules.OperationIssuance, "", "carol", 10); err == nil {
+		t.Error("expected max holders error for the third holder")
+	}
+}
+
+func TestRuleBatchDrainingTransfer(t *testing.T) {
+
+	ledger := maxHoldersLedger(t, 2)
+	ledger.Wallet("alice", "bob", "carol")
+	ledger.Mint("alice", guardPartition, 100)
+	ledger.Tx()
+
+	// alice 가 남아 있는 동안에는 새 보유자 한 명만 가능
+	batch := _newRuleBatch(guardPartition, "")
+	ledger.Check(batch.check(ledger.Ctx, rules.OperationTransfer, "alice", "bob", 60))
+	if err := batch.evaluate(ledger.Ctx, rules.OperationTransfer, "alice", "carol", 10); err == nil {
+		t.Error("expected max holders error while alice still holds")
+	}
+
+	// 마지막 이전으로 alice 가 전량을 보내면 통과
+	ledger.Check(batch.check(ledger.Ctx, rules.OperationTransfer, "alice", "carol", 40))
+	if batch.newHolders != 1 {
+		t.Errorf("new holders = %d, expected 1", batch.newHolders)
+	}
+}
+
+func TestRuleBatchEscrowKeepsSender(t *testing.T) {
+
+	ledger := maxHoldersLedger(t, 2)
+	escrow := wallet.EscrowAddress("ORDERBOOK")
+	ledger.Wallet("seller", "buyer1", "buyer2", escrow)
+	ledger.Mint("seller", guardPartition, 5)
+	ledger.Mint(escrow, guardPartition, 20)
+	ledger.Tx()
+
+	// escrow 에서 나가므로 seller 는 보유자로 남고 escrow 는 세지 않음
+	batch := _newRuleBatch(guardPartition, escrow)
+	ledger.Check(batch.check(ledger.Ctx, rules.OperationTransfer, "seller", "buyer1", 5))
+	if err := batch.check(ledger.Ctx, rules.OperationTransfer, "seller", "buyer2", 5); err == nil {
+		t.Error("expected max holders error for the second buyer")
+	}
+	if batch.debits["seller"] != 0 || batch.debits[escrow] != 5 {
+		t.Errorf("debits = %v, expected only escrow", batch.debits)
+	}
+}
@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/vesting"
+)
+
+// (holder, partition) 에 vesting schedule 설정. totalAmount 만큼이 schedule 에 따라 해제될 때까지 출금 불가
+func (s *SmartContract) CreateVestingSchedule(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	id, err := ccutils.GetID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requireParameterFields := []string{vesting.FieldHolder, vesting.FieldPartition, vesting.FieldKind, vesting.FieldTotalAmount, vesting.FieldStartAt}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{vesting.FieldHolder, vesting.FieldPartition, vesting.FieldKind}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{vesting.FieldTotalAmount, vesting.FieldStartAt}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeInt64([]string{vesting.FieldCliffAt, vesting.FieldEndAt, vesting.FieldStepSeconds}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	schedule := vesting.VestingScheduleStruct{}
+	schedule.Holder = args[vesting.FieldHolder].(string)
+	schedule.Partition = args[vesting.FieldPartition].(string)
+	schedule.Kind = args[vesting.FieldKind].(string)
+	schedule.TotalAmount = int64(args[vesting.FieldTotalAmount].(float64))
+	schedule.StartAt = int64(args[vesting.FieldStartAt].(float64))
+	schedule.CreatedBy = ccutils.GetAddress([]byte(id))
+
+	if value, exist := args[vesting.FieldCliffAt]; exist {
+		schedule.CliffAt = int64(value.(float64))
+	}
+
+	if value, exist := args[vesting.FieldEndAt]; exist {
+		schedule.EndAt = int64(value.(float64))
+	}
+
+	if value, exist := args[vesting.FieldStepSeconds]; exist {
+		schedule.StepSeconds = int64(value.(float64))
+	}
+
+	newSchedule, err := vesting.CreateSchedule(ctx, schedule)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(newSchedule)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// 미해제 수량을 treasury(생략 시 partition 발행자)로 회수
+func (s *SmartContract) RevokeVesting(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{vesting.FieldHolder, vesting.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{vesting.FieldHolder, vesting.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = ccutils.CheckTypeString([]string{vesting.FieldTreasury}, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	holder := args[vesting.FieldHolder].(string)
+	partition := args[vesting.FieldPartition].(string)
+
+	var treasury string
+	if value, exist := args[vesting.FieldTreasury]; exist {
+		treasury = value.(string)
+	} else {
+		partitionToken, err := token.GetPartitionToken(ctx, partition)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+		treasury = partitionToken.Publisher
+	}
+
+	schedule, err := vesting.Revoke(ctx, holder, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	balance, err := token.BalanceOfByPartition(ctx, holder, partition)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// controller 강제 이전 등으로 잔고가 미해제 수량보다 적을 수 있음
+	amount := schedule.RevokedAmount
+	if balance < amount {
+		amount = balance
+	}
+
+	if amount > 0 {
+		err = _moveByPartition(ctx, holder, treasury, partition, amount)
+		if err != nil {
+			return ccutils.GenerateErrorResponse(err)
+		}
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "VestingRevoked", From: holder, To: treasury, Partition: partition, Amount: amount}
+	err = transferEvent.EmitTransferEvent(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(schedule)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+// schedule 과 현재 해제/미해제 수량
+func (s *SmartContract) GetVestingSchedule(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{vesting.FieldHolder, vesting.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{vesting.FieldHolder, vesting.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	schedule, err := vesting.GetSchedule(ctx, args[vesting.FieldHolder].(string), args[vesting.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if schedule == nil {
+		return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := ccutils.StructToMap(schedule)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+	retData[vesting.FieldVested] = schedule.VestedAmount(now)
+	retData[vesting.FieldUnvested] = schedule.UnvestedAmount(now)
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
+func (s *SmartContract) GetVestingScheduleList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{ledgermanager.Bookmark}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{ledgermanager.Bookmark}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{ledgermanager.PageSize}
+	err = ccutils.CheckTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	bookmark := args[ledgermanager.Bookmark].(string)
+
+	var bytes []byte
+	bytes, err = vesting.GetScheduleList(args, pageSize, bookmark, ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponseByteArray(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], bytes)
+}
+
+// 잔고에서 동결 수량과 미해제 수량을 뺀 출금 가능 수량
+func (s *SmartContract) GetTransferableBalance(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	requireParameterFields := []string{vesting.FieldHolder, vesting.FieldPartition}
+	err := ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{vesting.FieldHolder, vesting.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData, err := _transferableByPartition(ctx, args[vesting.FieldHolder].(string), args[vesting.FieldPartition].(string))
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
@@ -7,6 +7,10 @@ import (
 
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/binding"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/blocklist"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
 )
@@ -37,6 +41,18 @@ func (s *SmartContract) CreateWallet(ctx contractapi.TransactionContextInterface
 		return ccutils.GenerateErrorResponse(err)
 	}
 
+	// 생성한 identity 와 wallet 을 바인딩
+	mspId, subject, issuer, err := ccutils.GetX509Identity(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	walletBinding := binding.WalletBindingStruct{Address: tokenWalletId, MSPID: mspId, Subject: subject, Issuer: issuer, BoundBy: tokenWalletId}
+	_, err = binding.Bind(ctx, walletBinding)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	retData, err := ccutils.StructToMap(newWallet)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -52,11 +68,6 @@ func (s *SmartContract) TransferByPartition(ctx contractapi.TransactionContextIn
 		return nil, err
 	}
 
-	id, err := ccutils.GetID(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	requireParameterFields := []string{token.FieldRecipient, token.FieldPartition, token.FieldAmount}
 	err = ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
@@ -76,7 +87,11 @@ func (s *SmartContract) TransferByPartition(ctx contractapi.TransactionContextIn
 	}
 
 	// args Data
-	owner := ccutils.GetAddress([]byte(id))
+	owner, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	recipient := args[token.FieldRecipient].(string)
 	partition := args[token.FieldPartition].(string)
 	amount := int64(args[token.FieldAmount].(float64))
@@ -90,7 +105,7 @@ func (s *SmartContract) TransferByPartition(ctx contractapi.TransactionContextIn
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Transfer", owner, recipient, partition, amount}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Transfer", From: owner, To: recipient, Partition: partition, Amount: amount}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -106,11 +121,6 @@ func (s *SmartContract) TransferFromByPartition(ctx contractapi.TransactionConte
 		return nil, err
 	}
 
-	id, err := ccutils.GetID(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	requireParameterFields := []string{token.FieldFrom, token.FieldTo, token.FieldPartition, token.FieldAmount}
 	err = ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
@@ -130,7 +140,11 @@ func (s *SmartContract) TransferFromByPartition(ctx contractapi.TransactionConte
 	}
 
 	// args Data
-	spender := ccutils.GetAddress([]byte(id))
+	spender, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	from := args[token.FieldFrom].(string)
 	to := args[token.FieldTo].(string)
 	partition := args[token.FieldPartition].(string)
@@ -140,6 +154,11 @@ func (s *SmartContract) TransferFromByPartition(ctx contractapi.TransactionConte
 		return nil, fmt.Errorf("mint amount must be a positive integer")
 	}
 
+	err = blocklist.CheckNotBlocked(ctx, spender)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	allowanceByPartition, err := token.AllowanceByPartition(ctx, from, spender, partition)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -162,7 +181,7 @@ func (s *SmartContract) TransferFromByPartition(ctx contractapi.TransactionConte
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Transfer", from, to, partition, amount}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Transfer", From: from, To: to, Partition: partition, Amount: amount}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -173,18 +192,55 @@ func (s *SmartContract) TransferFromByPartition(ctx contractapi.TransactionConte
 
 func _transferByPartition(ctx contractapi.TransactionContextInterface, from string, to string, partition string, value int64) error {
 
+	err := _checkDebitByPartition(ctx, from, partition, value)
+	if err != nil {
+		return err
+	}
+
+	err = _checkRules(ctx, rules.OperationTransfer, partition, from, to, value)
+	if err != nil {
+		return err
+	}
+
+	return _moveByPartition(ctx, from, to, partition, value)
+}
+
+// _moveByPartition 출금 제한(_checkDebitByPartition)을 확인하지 않는 이전. controller 강제 이전에서만 직접 사용
+func _moveByPartition(ctx contractapi.TransactionContextInterface, from string, to string, partition string, value int64) error {
+
+	// wallet 을 두번 읽고 써서 잔고가 늘어나는 것을 방지
+	if from == to {
+		return fmt.Errorf("cannot transfer to the same wallet")
+	}
+
+	err := pause.CheckNotPaused(ctx, partition, pause.OperationTransfer)
+	if err != nil {
+		return err
+	}
+
+	// 복구로 대체된 wallet 으로 보내면 토큰이 묶이게 됨
+	err = wallet.CheckNotSuperseded(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	err = _checkCreditByPartition(ctx, to, partition, value)
+	if err != nil {
+		return err
+	}
+
 	transferByPartition := token.TransferByPartitionStruct{}
 	transferByPartition.From = from
 	transferByPartition.To = to
 	transferByPartition.Partition = partition
 	transferByPartition.Amount = value
 
-	err := wallet.TransferByPartition(ctx, transferByPartition)
+	err = wallet.TransferByPartition(ctx, transferByPartition)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return _recordCreditByPartition(ctx, to, partition, value)
 }
 
 func (s *SmartContract) MintByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
@@ -226,16 +282,16 @@ func (s *SmartContract) MintByPartition(ctx contractapi.TransactionContextInterf
 		return nil, fmt.Errorf("mint amount must be a positive integer")
 	}
 
-	mintByPartition := token.MintByPartitionStruct{Minter: minter, Partition: partition, Amount: amount}
-
-	err = wallet.MintByPartition(ctx, mintByPartition)
+	proposal, err := _proposeIfRequired(ctx, ActionMintByPartition, minter, args)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
+	}
 
+	if proposal != nil {
+		return _proposalResponse(ctx, proposal)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Transfer", minter, "", partition, amount}
-	err = transferEvent.EmitTransferEvent(ctx)
+	err = _mintByPartition(ctx, minter, partition, amount)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
 	}
@@ -243,7 +299,46 @@ func (s *SmartContract) MintByPartition(ctx contractapi.TransactionContextInterf
 	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
 }
 
-func (s *SmartContract) BurnByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+func _mintByPartition(ctx contractapi.TransactionContextInterface, minter string, partition string, amount int64) error {
+
+	err := pause.CheckNotPaused(ctx, partition, pause.OperationIssuance)
+	if err != nil {
+		return err
+	}
+
+	err = token.CheckSupplyCap(ctx, partition, amount)
+	if err != nil {
+		return err
+	}
+
+	err = _checkCreditByPartition(ctx, minter, partition, amount)
+	if err != nil {
+		return err
+	}
+
+	err = _checkRules(ctx, rules.OperationIssuance, partition, "", minter, amount)
+	if err != nil {
+		return err
+	}
+
+	mintByPartition := token.MintByPartitionStruct{Minter: minter, Partition: partition, Amount: amount}
+
+	err = wallet.MintByPartition(ctx, mintByPartition)
+	if err != nil {
+		return err
+	}
+
+	err = _recordCreditByPartition(ctx, minter, partition, amount)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Transfer", From: minter, To: "", Partition: partition, Amount: amount}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+// ERC1644 controllerTransfer. 승인 없이 controller 가 강제로 이전
+func (s *SmartContract) ControllerTransferByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
 	err := ccutils.GetMSPID(ctx)
 	if err != nil {
@@ -255,6 +350,80 @@ func (s *SmartContract) BurnByPartition(ctx contractapi.TransactionContextInterf
 		return nil, err
 	}
 
+	requireParameterFields := []string{token.FieldFrom, token.FieldTo, token.FieldPartition, token.FieldAmount}
+	err = ccutils.CheckRequireParameter(requireParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	stringParameterFields := []string{token.FieldFrom, token.FieldTo, token.FieldPartition}
+	err = ccutils.CheckRequireTypeString(stringParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	int64ParameterFields := []string{token.FieldAmount}
+	err = ccutils.CheckRequireTypeInt64(int64ParameterFields, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	// args Data
+	controller := ccutils.GetAddress([]byte(id))
+	from := args[token.FieldFrom].(string)
+	to := args[token.FieldTo].(string)
+	partition := args[token.FieldPartition].(string)
+	amount := int64(args[token.FieldAmount].(float64))
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("transfer amount must be a positive integer")
+	}
+
+	proposal, err := _proposeIfRequired(ctx, ActionControllerTransferByPartition, controller, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	if proposal != nil {
+		return _proposalResponse(ctx, proposal)
+	}
+
+	err = _controllerTransferByPartition(ctx, controller, from, to, partition, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], nil)
+}
+
+func _controllerTransferByPartition(ctx contractapi.TransactionContextInterface, controller string, from string, to string, partition string, amount int64) error {
+
+	isControllable, err := token.IsControllable(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	if !isControllable {
+		return fmt.Errorf("partition %s is not controllable", partition)
+	}
+
+	// 법원 명령 등 강제 이전은 동결된 잔고도 이전 가능
+	err = _moveByPartition(ctx, from, to, partition, amount)
+	if err != nil {
+		return err
+	}
+
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "ControllerTransfer", From: from, To: to, Partition: partition, Amount: amount}
+	return transferEvent.EmitTransferEvent(ctx)
+}
+
+func (s *SmartContract) BurnByPartition(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
+
+	err := ccutils.GetMSPID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	requireParameterFields := []string{token.FieldPartition, token.FieldAmount}
 	err = ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
@@ -274,7 +443,11 @@ func (s *SmartContract) BurnByPartition(ctx contractapi.TransactionContextInterf
 	}
 
 	// args Data
-	minter := ccutils.GetAddress([]byte(id))
+	minter, err := _callerWallet(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	partition := args[token.FieldPartition].(string)
 	amount := int64(args[token.FieldAmount].(float64))
 
@@ -282,6 +455,21 @@ func (s *SmartContract) BurnByPartition(ctx contractapi.TransactionContextInterf
 		return nil, fmt.Errorf("mint amount must be a positive integer")
 	}
 
+	err = pause.CheckNotPaused(ctx, partition, pause.OperationRedemption)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkDebitByPartition(ctx, minter, partition, amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	err = _checkRules(ctx, rules.OperationRedemption, partition, minter, "", amount)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	burnByPartition := token.MintByPartitionStruct{Minter: minter, Partition: partition, Amount: amount}
 
 	err = wallet.BurnByPartition(ctx, burnByPartition)
@@ -289,7 +477,7 @@ func (s *SmartContract) BurnByPartition(ctx contractapi.TransactionContextInterf
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	transferEvent := ccutils.Event{ctx.GetStub().GetTxID(), "Transfer", minter, "", partition, amount}
+	transferEvent := ccutils.Event{TxId: ctx.GetStub().GetTxID(), Type: "Transfer", From: minter, To: "", Partition: partition, Amount: amount}
 	err = transferEvent.EmitTransferEvent(ctx)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -300,7 +488,7 @@ func (s *SmartContract) BurnByPartition(ctx contractapi.TransactionContextInterf
 
 func (s *SmartContract) GetTokenWalletList(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	requireParameterFields := []string{ledgermanager.PageSize, ledgermanager.Bookmark}
+	requireParameterFields := []string{ledgermanager.Bookmark}
 	err := ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -324,7 +512,11 @@ func (s *SmartContract) GetTokenWalletList(ctx contractapi.TransactionContextInt
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	pageSize := int32(args[ledgermanager.PageSize].(float64))
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	bookmark := args[ledgermanager.Bookmark].(string)
 
 	var bytes []byte
@@ -338,7 +530,7 @@ func (s *SmartContract) GetTokenWalletList(ctx contractapi.TransactionContextInt
 
 func (s *SmartContract) GetAdminWallet(ctx contractapi.TransactionContextInterface, args map[string]interface{}) (*ccutils.Response, error) {
 
-	requireParameterFields := []string{ledgermanager.PageSize, ledgermanager.Bookmark}
+	requireParameterFields := []string{ledgermanager.Bookmark}
 	err := ccutils.CheckRequireParameter(requireParameterFields, args)
 	if err != nil {
 		return ccutils.GenerateErrorResponse(err)
@@ -362,7 +554,11 @@ func (s *SmartContract) GetAdminWallet(ctx contractapi.TransactionContextInterfa
 		return ccutils.GenerateErrorResponse(err)
 	}
 
-	pageSize := int32(args[ledgermanager.PageSize].(float64))
+	pageSize, err := _pageSize(ctx, args)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
 	bookmark := args[ledgermanager.Bookmark].(string)
 
 	var bytes []byte
@@ -155,6 +155,18 @@ func UpdateState(docType string, key string, data map[string]interface{}, ctx co
 	return nil
 }
 
+func DeleteState(docType string, key string, ctx contractapi.TransactionContextInterface) error {
+	// docType 확인
+	if _, err := GetState(docType, key, ctx); err != nil {
+		return err
+	}
+
+	if err := ctx.GetStub().DelState(key); err != nil {
+		return ccutils.CreateError(CodeErrorDeleteState, fmt.Errorf(ErrorCodeMessage[CodeErrorDeleteState]+" : "+err.Error()))
+	}
+	return nil
+}
+
 func GetExistState(key string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
 	var resultBytes []byte
 	var err error
@@ -216,6 +228,18 @@ func GetQueryResultWithPagination(queryString string, pageSize int32, bookmark s
 	return []byte(retData), nil
 }
 
+// ExistQueryResult rich query 결과가 하나라도 있는지
+func ExistQueryResult(queryString string, ctx contractapi.TransactionContextInterface) (bool, error) {
+
+	resultsIterator, err := ctx.GetStub().GetQueryResult(queryString)
+	if err != nil {
+		return false, ccutils.CreateError(ccutils.ChaincodeError, err)
+	}
+	defer resultsIterator.Close()
+
+	return resultsIterator.HasNext(), nil
+}
+
 // ===========================================================================================
 // constructQueryResponseFromIterator constructs a JSON array containing query results from
 // a given result iterator
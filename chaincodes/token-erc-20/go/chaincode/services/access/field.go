@@ -0,0 +1,11 @@
+package access
+
+const (
+	FieldRole        string = "role"
+	FieldSubjectType string = "subjectType"
+	FieldSubject     string = "subject"
+	FieldAttributes  string = "attributes"
+
+	FieldOldAdmin string = "oldAdmin"
+	FieldNewAdmin string = "newAdmin"
+)
@@ -0,0 +1,36 @@
+package access
+
+const (
+	DocType_Role = "DOCTYPE_ROLE"
+)
+
+// Roles
+const (
+	RoleAdmin      = "admin"
+	RoleIssuer     = "issuer"
+	RoleOperator   = "operator"
+	RoleController = "controller"
+	RoleCompliance = "compliance"
+	RoleAuditor    = "auditor"
+)
+
+// 역할을 부여받는 대상의 종류
+const (
+	SubjectTypeAddress = "address"
+	SubjectTypeMSP     = "msp"
+)
+
+var Roles = []string{RoleAdmin, RoleIssuer, RoleOperator, RoleController, RoleCompliance, RoleAuditor}
+
+type RoleStruct struct {
+	DocType string `json:"docType"`
+
+	Role        string `json:"role"`
+	SubjectType string `json:"subjectType"`
+	Subject     string `json:"subject"`
+
+	// 부여 대상이 가지고 있어야 하는 X.509 attribute (attrName : attrValue)
+	Attributes map[string]string `json:"attributes"`
+
+	GrantedBy string `json:"grantedBy"`
+}
@@ -0,0 +1,213 @@
+package access
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func GrantRole(ctx contractapi.TransactionContextInterface, role RoleStruct) (*RoleStruct, error) {
+
+	if !IsValidRole(role.Role) {
+		return nil, fmt.Errorf("unknown role : %s", role.Role)
+	}
+
+	if role.SubjectType != SubjectTypeAddress && role.SubjectType != SubjectTypeMSP {
+		return nil, fmt.Errorf("unknown subject type : %s", role.SubjectType)
+	}
+
+	if role.Attributes == nil {
+		role.Attributes = make(map[string]string)
+	}
+
+	roleKey, err := ctx.GetStub().CreateCompositeKey(DocType_Role, []string{role.Role, role.SubjectType, role.Subject})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Role, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(roleKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		roleToMap, err := ccutils.StructToMap(role)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_Role, roleKey, roleToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_Role, roleKey, role, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &role, nil
+}
+
+func RevokeRole(ctx contractapi.TransactionContextInterface, role string, subjectType string, subject string) error {
+
+	roleKey, err := ctx.GetStub().CreateCompositeKey(DocType_Role, []string{role, subjectType, subject})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Role, err)
+	}
+
+	return ledgermanager.DeleteState(DocType_Role, roleKey, ctx)
+}
+
+func GetRole(ctx contractapi.TransactionContextInterface, role string, subjectType string, subject string) (*RoleStruct, error) {
+
+	roleKey, err := ctx.GetStub().CreateCompositeKey(DocType_Role, []string{role, subjectType, subject})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Role, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(roleKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 부여된 역할이 없는 경우
+	if !exist {
+		return nil, nil
+	}
+
+	roleBytes, err := ledgermanager.GetState(DocType_Role, roleKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roleStruct := RoleStruct{}
+	if err := json.Unmarshal(roleBytes, &roleStruct); err != nil {
+		return nil, err
+	}
+
+	return &roleStruct, nil
+}
+
+// HasRole 호출자가 role(혹은 admin)을 address 나 MSP 단위로 부여받았는지 확인
+func HasRole(ctx contractapi.TransactionContextInterface, role string) (bool, error) {
+
+	identity := ctx.GetClientIdentity()
+
+	id, err := identity.GetID()
+	if err != nil {
+		return false, fmt.Errorf("failed to get client id: %v", err)
+	}
+
+	mspId, err := identity.GetMSPID()
+	if err != nil {
+		return false, fmt.Errorf("failed to get client msp id: %v", err)
+	}
+
+	address := ccutils.GetAddress([]byte(id))
+
+	candidates := []string{role}
+	if role != RoleAdmin {
+		candidates = append(candidates, RoleAdmin)
+	}
+
+	for _, candidate := range candidates {
+		for subjectType, subject := range map[string]string{SubjectTypeAddress: address, SubjectTypeMSP: mspId} {
+			roleStruct, err := GetRole(ctx, candidate, subjectType, subject)
+			if err != nil {
+				return false, err
+			}
+
+			if roleStruct == nil {
+				continue
+			}
+
+			matched, err := matchAttributes(identity, roleStruct.Attributes)
+			if err != nil {
+				return false, err
+			}
+
+			if matched {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// CheckRole 호출자가 roles 중 하나라도 가지고 있지 않으면 에러
+func CheckRole(ctx contractapi.TransactionContextInterface, roles ...string) error {
+
+	for _, role := range roles {
+		ok, err := HasRole(ctx, role)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+	}
+
+	return ccutils.CreateError(ccutils.ChaincodeErrorForbidden, fmt.Errorf("caller does not have any of the required roles : %v", roles))
+}
+
+// X.509 인증서 attribute 조건 확인
+func matchAttributes(identity cid.ClientIdentity, attributes map[string]string) (bool, error) {
+
+	for attrName, attrValue := range attributes {
+		value, found, err := identity.GetAttributeValue(attrName)
+		if err != nil {
+			return false, fmt.Errorf("failed to get client attribute %s: %v", attrName, err)
+		}
+
+		if !found || value != attrValue {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func GetRoleList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Role)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldRole, FieldSubjectType, FieldSubject}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
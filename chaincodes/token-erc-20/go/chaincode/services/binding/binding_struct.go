@@ -0,0 +1,26 @@
+package binding
+
+const (
+	DocType_WalletBinding   = "DOCTYPE_WALLETBINDING"
+	DocType_IdentityBinding = "DOCTYPE_IDENTITYBINDING"
+)
+
+// wallet 주소 ↔ 인증서(X.509 subject/issuer) ↔ MSP
+type WalletBindingStruct struct {
+	DocType string `json:"docType"`
+
+	Address string `json:"address"`
+	MSPID   string `json:"mspId"`
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer"`
+
+	BoundBy string `json:"boundBy"`
+	BoundAt int64  `json:"boundAt"`
+}
+
+// 인증서 → wallet 주소 역방향 인덱스
+type IdentityBindingStruct struct {
+	DocType string `json:"docType"`
+
+	Address string `json:"address"`
+}
@@ -0,0 +1,11 @@
+package binding
+
+const CodeErrorIdentityMismatch int = 640
+const CodeErrorIdentityAlreadyBound int = 641
+const CodeErrorEscrowAddress int = 642
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorIdentityMismatch:     "Binding error : wallet is bound to a different identity",
+	CodeErrorIdentityAlreadyBound: "Binding error : identity is already bound to another wallet",
+	CodeErrorEscrowAddress:        "Binding error : escrow wallet cannot be bound to an identity",
+}
@@ -0,0 +1,8 @@
+package binding
+
+const (
+	FieldAddress string = "address"
+	FieldMSPID   string = "mspId"
+	FieldSubject string = "subject"
+	FieldIssuer  string = "issuer"
+)
@@ -0,0 +1,218 @@
+package binding
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// Bind 주소에 identity 를 묶음. 이미 묶인 주소면 기존 identity 인덱스를 지우고 새 identity 로 교체
+func Bind(ctx contractapi.TransactionContextInterface, binding WalletBindingStruct) (*WalletBindingStruct, error) {
+
+	if binding.Address == "" || binding.MSPID == "" || binding.Subject == "" || binding.Issuer == "" {
+		return nil, fmt.Errorf("address, mspId, subject and issuer must not be empty")
+	}
+
+	boundAddress, err := getBoundAddress(ctx, binding.MSPID, binding.Subject, binding.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	if boundAddress != "" && boundAddress != binding.Address {
+		return nil, ccutils.CreateError(CodeErrorIdentityAlreadyBound, fmt.Errorf(ErrorCodeMessage[CodeErrorIdentityAlreadyBound]+" : "+boundAddress))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+	binding.BoundAt = now
+
+	walletKey, err := ctx.GetStub().CreateCompositeKey(DocType_WalletBinding, []string{binding.Address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_WalletBinding, err)
+	}
+
+	previous, err := GetBindingByAddress(ctx, binding.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous != nil {
+		if boundAddress == "" {
+			err = deleteIdentityIndex(ctx, previous.MSPID, previous.Subject, previous.Issuer)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		bindingToMap, err := ccutils.StructToMap(binding)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_WalletBinding, walletKey, bindingToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_WalletBinding, walletKey, binding, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if boundAddress == "" {
+		identityKey, err := ctx.GetStub().CreateCompositeKey(DocType_IdentityBinding, []string{binding.MSPID, binding.Subject, binding.Issuer})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_IdentityBinding, err)
+		}
+
+		_, err = ledgermanager.PutState(DocType_IdentityBinding, identityKey, IdentityBindingStruct{Address: binding.Address}, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &binding, nil
+}
+
+// GetBindingByAddress 바인딩이 없으면 nil
+func GetBindingByAddress(ctx contractapi.TransactionContextInterface, address string) (*WalletBindingStruct, error) {
+
+	walletKey, err := ctx.GetStub().CreateCompositeKey(DocType_WalletBinding, []string{address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_WalletBinding, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(walletKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	bindingBytes, err := ledgermanager.GetState(DocType_WalletBinding, walletKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	binding := WalletBindingStruct{}
+	if err := json.Unmarshal(bindingBytes, &binding); err != nil {
+		return nil, err
+	}
+
+	return &binding, nil
+}
+
+// GetBindingByIdentity 바인딩이 없으면 nil
+func GetBindingByIdentity(ctx contractapi.TransactionContextInterface, mspId string, subject string, issuer string) (*WalletBindingStruct, error) {
+
+	address, err := getBoundAddress(ctx, mspId, subject, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	if address == "" {
+		return nil, nil
+	}
+
+	return GetBindingByAddress(ctx, address)
+}
+
+// ResolveWallet 호출자 identity 가 사용할 수 있는 wallet 주소.
+// 바인딩된 identity 면 바인딩된 주소, 아니면 derivedAddress (다른 identity 에 바인딩된 주소면 거부)
+func ResolveWallet(ctx contractapi.TransactionContextInterface, mspId string, subject string, issuer string, derivedAddress string) (string, error) {
+
+	boundAddress, err := getBoundAddress(ctx, mspId, subject, issuer)
+	if err != nil {
+		return "", err
+	}
+
+	if boundAddress != "" {
+		return boundAddress, nil
+	}
+
+	binding, err := GetBindingByAddress(ctx, derivedAddress)
+	if err != nil {
+		return "", err
+	}
+
+	if binding != nil {
+		return "", ccutils.CreateError(CodeErrorIdentityMismatch, fmt.Errorf(ErrorCodeMessage[CodeErrorIdentityMismatch]+" : "+derivedAddress))
+	}
+
+	return derivedAddress, nil
+}
+
+func GetBindingList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_WalletBinding)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldMSPID, FieldSubject, FieldIssuer}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func getBoundAddress(ctx contractapi.TransactionContextInterface, mspId string, subject string, issuer string) (string, error) {
+
+	identityKey, err := ctx.GetStub().CreateCompositeKey(DocType_IdentityBinding, []string{mspId, subject, issuer})
+	if err != nil {
+		return "", fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_IdentityBinding, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(identityKey, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if !exist {
+		return "", nil
+	}
+
+	indexBytes, err := ledgermanager.GetState(DocType_IdentityBinding, identityKey, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	index := IdentityBindingStruct{}
+	if err := json.Unmarshal(indexBytes, &index); err != nil {
+		return "", err
+	}
+
+	return index.Address, nil
+}
+
+func deleteIdentityIndex(ctx contractapi.TransactionContextInterface, mspId string, subject string, issuer string) error {
+
+	identityKey, err := ctx.GetStub().CreateCompositeKey(DocType_IdentityBinding, []string{mspId, subject, issuer})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_IdentityBinding, err)
+	}
+
+	return ledgermanager.DeleteState(DocType_IdentityBinding, identityKey, ctx)
+}
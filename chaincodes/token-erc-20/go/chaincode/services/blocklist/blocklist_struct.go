@@ -0,0 +1,44 @@
+package blocklist
+
+const (
+	DocType_BlockedAddress  = "DOCTYPE_BLOCKEDADDRESS"
+	DocType_BlocklistUpdate = "DOCTYPE_BLOCKLISTUPDATE"
+)
+
+// Bulk update action
+const (
+	ActionAdd    = "add"
+	ActionRemove = "remove"
+)
+
+type BlockedAddressStruct struct {
+	DocType string `json:"docType"`
+
+	Address string `json:"address"`
+	Reason  string `json:"reason"`
+	// unix seconds. 0 이면 만료 없음
+	ExpiresAt int64 `json:"expiresAt"`
+	// 등록한 원본 목록의 hash
+	ContentHash string `json:"contentHash"`
+
+	AddedBy string `json:"addedBy"`
+	AddedAt int64  `json:"addedAt"`
+}
+
+// IsActive now 시점에 차단 중인지
+func (b BlockedAddressStruct) IsActive(now int64) bool {
+	return b.ExpiresAt == 0 || now < b.ExpiresAt
+}
+
+// 일괄 추가/삭제 이력. 업로드한 원본 목록의 hash 로 감사 추적
+type BlocklistUpdateStruct struct {
+	DocType string `json:"docType"`
+
+	UpdateId    string `json:"updateId"`
+	Action      string `json:"action"`
+	ContentHash string `json:"contentHash"`
+	Count       int64  `json:"count"`
+
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
@@ -0,0 +1,7 @@
+package blocklist
+
+const CodeErrorAddressBlocked int = 750
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorAddressBlocked: "Blocklist error : address is blocked",
+}
@@ -0,0 +1,13 @@
+package blocklist
+
+const (
+	FieldAddress     string = "address"
+	FieldAddresses   string = "addresses"
+	FieldEntries     string = "entries"
+	FieldReason      string = "reason"
+	FieldExpiresAt   string = "expiresAt"
+	FieldContentHash string = "contentHash"
+	FieldAction      string = "action"
+
+	FieldBlocked string = "blocked"
+)
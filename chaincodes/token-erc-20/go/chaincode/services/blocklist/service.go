@@ -0,0 +1,253 @@
+package blocklist
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// AddEntries 주소 일괄 차단. 이미 있는 주소는 사유/만료를 갱신
+func AddEntries(ctx contractapi.TransactionContextInterface, entries []BlockedAddressStruct, contentHash string, addedBy string) (*BlocklistUpdateStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		// 같은 키를 한 tx 에서 두 번 쓰지 않도록 중복 주소 거부
+		if seen[entry.Address] {
+			return nil, fmt.Errorf("duplicate address in blocklist : %s", entry.Address)
+		}
+		seen[entry.Address] = true
+
+		entry.ContentHash = contentHash
+		entry.AddedBy = addedBy
+		entry.AddedAt = now
+
+		entryKey, err := ctx.GetStub().CreateCompositeKey(DocType_BlockedAddress, []string{entry.Address})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_BlockedAddress, err)
+		}
+
+		exist, err := ledgermanager.CheckExistState(entryKey, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if exist {
+			entryToMap, err := ccutils.StructToMap(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			err = ledgermanager.UpdateState(DocType_BlockedAddress, entryKey, entryToMap, ctx)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			_, err = ledgermanager.PutState(DocType_BlockedAddress, entryKey, entry, ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return putUpdate(ctx, ActionAdd, contentHash, int64(len(entries)), addedBy, now)
+}
+
+// RemoveEntries 주소 일괄 해제. 목록에 없는 주소는 무시
+func RemoveEntries(ctx contractapi.TransactionContextInterface, addresses []string, contentHash string, removedBy string) (*BlocklistUpdateStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	seen := make(map[string]bool)
+	for _, address := range addresses {
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		entryKey, err := ctx.GetStub().CreateCompositeKey(DocType_BlockedAddress, []string{address})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_BlockedAddress, err)
+		}
+
+		exist, err := ledgermanager.CheckExistState(entryKey, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exist {
+			continue
+		}
+
+		err = ledgermanager.DeleteState(DocType_BlockedAddress, entryKey, ctx)
+		if err != nil {
+			return nil, err
+		}
+		count++
+	}
+
+	return putUpdate(ctx, ActionRemove, contentHash, count, removedBy, now)
+}
+
+// GetEntry 차단 기록이 없으면 nil. 만료된 기록도 그대로 반환
+func GetEntry(ctx contractapi.TransactionContextInterface, address string) (*BlockedAddressStruct, error) {
+
+	entryKey, err := ctx.GetStub().CreateCompositeKey(DocType_BlockedAddress, []string{address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_BlockedAddress, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(entryKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	entryBytes, err := ledgermanager.GetState(DocType_BlockedAddress, entryKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := BlockedAddressStruct{}
+	if err := json.Unmarshal(entryBytes, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Screen 주어진 주소 중 현재 차단 중인 기록만 반환
+func Screen(ctx contractapi.TransactionContextInterface, addresses []string) ([]BlockedAddressStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked := []BlockedAddressStruct{}
+	seen := make(map[string]bool)
+	for _, address := range addresses {
+		if address == "" || seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		entry, err := GetEntry(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil && entry.IsActive(now) {
+			blocked = append(blocked, *entry)
+		}
+	}
+
+	return blocked, nil
+}
+
+// CheckNotBlocked 빈 주소(발행/소각의 상대방)는 무시
+func CheckNotBlocked(ctx contractapi.TransactionContextInterface, addresses ...string) error {
+
+	blocked, err := Screen(ctx, addresses)
+	if err != nil {
+		return err
+	}
+
+	if len(blocked) > 0 {
+		return ccutils.CreateError(CodeErrorAddressBlocked, fmt.Errorf(ErrorCodeMessage[CodeErrorAddressBlocked]+" : "+blocked[0].Address))
+	}
+
+	return nil
+}
+
+func GetEntryList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_BlockedAddress)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldContentHash}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func GetUpdateList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_BlocklistUpdate)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldAction, FieldContentHash}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func putUpdate(ctx contractapi.TransactionContextInterface, action string, contentHash string, count int64, updatedBy string, now int64) (*BlocklistUpdateStruct, error) {
+
+	update := BlocklistUpdateStruct{}
+	update.UpdateId = ctx.GetStub().GetTxID()
+	update.Action = action
+	update.ContentHash = contentHash
+	update.Count = count
+	update.UpdatedBy = updatedBy
+	update.UpdatedAt = now
+
+	updateKey, err := ctx.GetStub().CreateCompositeKey(DocType_BlocklistUpdate, []string{update.UpdateId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_BlocklistUpdate, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_BlocklistUpdate, updateKey, update, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &update, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/split"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
 )
@@ -32,8 +33,15 @@ func DistributeToken(ctx contractapi.TransactionContextInterface, airDrop AirDro
 	tokenMap := make(map[string][]token.PartitionToken)
 	tokenMap[airDrop.PartitionToken.TokenID] = append(tokenMap[airDrop.PartitionToken.TokenID], airDrop.PartitionToken)
 
+	// 배분 수량은 현재 분할 epoch 기준
+	scale, err := split.GetScale(ctx, airDrop.PartitionToken.TokenID)
+	if err != nil {
+		errChan <- err
+		return
+	}
+
 	walletData.PartitionTokens[airDrop.PartitionToken.TokenID] = tokenMap[airDrop.PartitionToken.TokenID]
-	walletData.PartitionTokens[airDrop.PartitionToken.TokenID][0] = token.PartitionToken{Amount: airDrop.PartitionToken.Amount}
+	walletData.PartitionTokens[airDrop.PartitionToken.TokenID][0] = token.PartitionToken{Amount: airDrop.PartitionToken.Amount, ScaleEpoch: scale.Epoch}
 
 	walletToMap, err := ccutils.StructToMap(walletData)
 	if err != nil {
@@ -57,6 +65,7 @@ func DistributeToken(ctx contractapi.TransactionContextInterface, airDrop AirDro
 	partitionToken := token.PartitionToken{}
 	partitionToken.DocType = token.DocType_Token
 	partitionToken.Amount = airDrop.PartitionToken.Amount
+	partitionToken.ScaleEpoch = scale.Epoch
 
 	// _, err = ledgermanager.PutState(token.BalanceOfByPartitionPrefix, balanceKey, partitionToken, ctx)
 	// if err != nil {
@@ -121,6 +130,12 @@ func DistributeToken(ctx contractapi.TransactionContextInterface, airDrop AirDro
 		return
 	}
 
+	err = token.ScaleTotalSupply(ctx, airDrop.PartitionToken.TokenID, &totalSupplyByPartition)
+	if err != nil {
+		errChan <- err
+		return
+	}
+
 	totalSupplyByPartition.TotalSupply += airDrop.PartitionToken.Amount
 
 	totalSupplyByPartitionMap, err := ccutils.StructToMap(totalSupplyByPartition)
@@ -157,7 +172,15 @@ func AirDrop(ctx contractapi.TransactionContextInterface, airDrop AirDropStruct,
 		return
 	}
 
-	walletData.PartitionTokens[airDrop.PartitionToken.TokenID][0].Amount += airDrop.PartitionToken.Amount
+	// 분할 이후 처음 받는 경우 기존 잔고부터 환산
+	entry := &walletData.PartitionTokens[airDrop.PartitionToken.TokenID][0]
+	err = wallet.NormalizeBalance(ctx, airDrop.Recipient, airDrop.PartitionToken.TokenID, entry)
+	if err != nil {
+		errChan <- err
+		return
+	}
+
+	entry.Amount += airDrop.PartitionToken.Amount
 
 	walletToMap, err := ccutils.StructToMap(walletData)
 	if err != nil {
@@ -181,6 +204,7 @@ func AirDrop(ctx contractapi.TransactionContextInterface, airDrop AirDropStruct,
 	partitionToken := token.PartitionToken{}
 	partitionToken.DocType = token.DocType_Token
 	partitionToken.Amount = airDrop.PartitionToken.Amount
+	partitionToken.ScaleEpoch = entry.ScaleEpoch
 
 	// _, err = ledgermanager.PutState(token.BalanceOfByPartitionPrefix, balanceKey, partitionToken, ctx)
 	// if err != nil {
@@ -245,6 +269,12 @@ func AirDrop(ctx contractapi.TransactionContextInterface, airDrop AirDropStruct,
 		return
 	}
 
+	err = token.ScaleTotalSupply(ctx, airDrop.PartitionToken.TokenID, &totalSupplyByPartition)
+	if err != nil {
+		errChan <- err
+		return
+	}
+
 	totalSupplyByPartition.TotalSupply += airDrop.PartitionToken.Amount
 
 	totalSupplyByPartitionMap, err := ccutils.StructToMap(totalSupplyByPartition)
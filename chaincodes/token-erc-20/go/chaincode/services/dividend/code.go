@@ -0,0 +1,15 @@
+package dividend
+
+const CodeErrorDistributionNotActive int = 780
+const CodeErrorAlreadyClaimed int = 781
+const CodeErrorNothingToClaim int = 782
+const CodeErrorClaimPeriodNotEnded int = 783
+const CodeErrorClaimPeriodEnded int = 784
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorDistributionNotActive: "Dividend error : distribution is not active",
+	CodeErrorAlreadyClaimed:        "Dividend error : already claimed",
+	CodeErrorNothingToClaim:        "Dividend error : no entitlement at the snapshot",
+	CodeErrorClaimPeriodNotEnded:   "Dividend error : claim period has not ended",
+	CodeErrorClaimPeriodEnded:      "Dividend error : claim period has ended",
+}
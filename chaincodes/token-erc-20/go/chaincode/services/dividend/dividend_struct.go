@@ -0,0 +1,151 @@
+package dividend
+
+import "math/big"
+
+const (
+	DocType_Distribution = "DOCTYPE_DIVIDEND"
+	DocType_Claim        = "DOCTYPE_DIVIDENDCLAIM"
+)
+
+const (
+	// 배당금을 보관하는 escrow wallet 이름 (wallet.EscrowAddress)
+	EscrowName = "DIVIDEND"
+
+	// withholding rate 단위. 10000 = 100%
+	BasisPoints int64 = 10000
+)
+
+// Distribution status
+const (
+	StatusActive = "active"
+	StatusClosed = "closed"
+)
+
+// Rounding policy. 어느 쪽이든 남은 잔액은 close 시 발행사로 반환
+const (
+	// 보유자별 지급액 내림
+	RoundingDown = "down"
+	// 보유자별 지급액 반올림. 아직 지급하지 않은 보유자들의 내림 지급액을 남겨 둘 수 있을 때만 올림
+	RoundingHalfUp = "halfUp"
+)
+
+// cash partition 으로 지급하는 security partition 보유자 배당/이자
+type DistributionStruct struct {
+	DocType string `json:"docType"`
+
+	DistributionId string `json:"distributionId"`
+	Partition      string `json:"partition"`
+	CashPartition  string `json:"cashPartition"`
+	SnapshotId     int64  `json:"snapshotId"`
+
+	// 재원을 넣고 잔액을 돌려받는 발행사 wallet
+	Issuer      string `json:"issuer"`
+	TotalAmount int64  `json:"totalAmount"`
+	// snapshot 시점 partition 총발행량
+	EligibleSupply int64  `json:"eligibleSupply"`
+	RoundingPolicy string `json:"roundingPolicy"`
+
+	// 투자자 category 별 원천징수율 (basis points). 원천징수액은 WithholdingAccount 로 지급
+	WithholdingRates   map[string]int64 `json:"withholdingRates"`
+	WithholdingAccount string           `json:"withholdingAccount"`
+
+	// unix seconds. 0 이면 기한 없음. 기한 후에만 close 가능
+	ClaimDeadline int64 `json:"claimDeadline"`
+
+	// 지급을 마친 snapshot 잔고 합계
+	ClaimedBalance int64 `json:"claimedBalance"`
+	PaidGross      int64 `json:"paidGross"`
+	PaidNet        int64 `json:"paidNet"`
+	Withheld       int64 `json:"withheld"`
+	ClaimCount     int64 `json:"claimCount"`
+	ReturnedAmount int64 `json:"returnedAmount"`
+
+	Status    string `json:"status"`
+	CreatedBy string `json:"createdBy"`
+	CreatedAt int64  `json:"createdAt"`
+	ClosedBy  string `json:"closedBy"`
+	ClosedAt  int64  `json:"closedAt"`
+}
+
+// GrossAmount snapshot 잔고 balance 에 대한 세전 지급액.
+// 내림 지급액은 항상 지급 가능 (PaidGross + 미지급 잔고의 내림 지급액 <= TotalAmount 를 유지).
+// 반올림으로 더하는 1 은 이 조건을 깨지 않을 때만 더하므로 마지막 보유자가 덜 받지 않음
+func (d DistributionStruct) GrossAmount(balance int64) int64 {
+
+	if d.EligibleSupply <= 0 || balance <= 0 {
+		return 0
+	}
+
+	gross, remainder := d.share(balance)
+
+	if d.RoundingPolicy == RoundingHalfUp && remainder*2 >= d.EligibleSupply {
+		unclaimed, _ := d.share(d.EligibleSupply - d.ClaimedBalance - balance)
+		if d.PaidGross+gross+1+unclaimed <= d.TotalAmount {
+			gross++
+		}
+	}
+
+	// 위 조건에서는 넘지 않음. escrow 를 함께 쓰는 다른 배당 재원을 건드리지 않도록 한 번 더 제한
+	if remaining := d.TotalAmount - d.PaidGross; gross > remaining {
+		gross = remaining
+	}
+
+	return gross
+}
+
+// share balance 비례 지급액 내림값과 나머지
+func (d DistributionStruct) share(balance int64) (int64, int64) {
+
+	if balance <= 0 {
+		return 0, 0
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(balance), big.NewInt(d.TotalAmount)), big.NewInt(d.EligibleSupply), new(big.Int))
+	return quotient.Int64(), remainder.Int64()
+}
+
+// WithholdingAmount category 원천징수액 (내림)
+func (d DistributionStruct) WithholdingAmount(gross int64, category string) int64 {
+
+	rate := d.WithholdingRates[category]
+	if rate <= 0 {
+		return 0
+	}
+
+	return new(big.Int).Quo(new(big.Int).Mul(big.NewInt(gross), big.NewInt(rate)), big.NewInt(BasisPoints)).Int64()
+}
+
+// 보유자별 지급 기록. 기록이 있으면 지급 완료
+type ClaimStruct struct {
+	DocType string `json:"docType"`
+
+	DistributionId string `json:"distributionId"`
+	Holder         string `json:"holder"`
+	// snapshot 시점 잔고
+	Balance  int64  `json:"balance"`
+	Category string `json:"category"`
+
+	Gross    int64 `json:"gross"`
+	Withheld int64 `json:"withheld"`
+	Net      int64 `json:"net"`
+
+	// 발행사/operator 일괄 지급이면 true
+	Pushed bool   `json:"pushed"`
+	PaidBy string `json:"paidBy"`
+	PaidAt int64  `json:"paidAt"`
+}
+
+// Allocate holder 의 지급액을 계산하고 지급 합계에 반영. 일괄 지급 중 남은 escrow 를 넘지 않도록 d 를 갱신
+func (d *DistributionStruct) Allocate(holder string, balance int64, category string) ClaimStruct {
+
+	gross := d.GrossAmount(balance)
+	withheld := d.WithholdingAmount(gross, category)
+
+	d.ClaimedBalance += balance
+	d.PaidGross += gross
+	d.PaidNet += gross - withheld
+	d.Withheld += withheld
+	d.ClaimCount++
+
+	return ClaimStruct{DistributionId: d.DistributionId, Holder: holder, Balance: balance, Category: category, Gross: gross, Withheld: withheld, Net: gross - withheld}
+}
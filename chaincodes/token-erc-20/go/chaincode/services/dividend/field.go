@@ -0,0 +1,18 @@
+package dividend
+
+const (
+	FieldDistributionId     string = "distributionId"
+	FieldPartition          string = "partition"
+	FieldCashPartition      string = "cashPartition"
+	FieldSnapshotId         string = "snapshotId"
+	FieldTotalAmount        string = "totalAmount"
+	FieldRoundingPolicy     string = "roundingPolicy"
+	FieldWithholdingRates   string = "withholdingRates"
+	FieldWithholdingAccount string = "withholdingAccount"
+	FieldClaimDeadline      string = "claimDeadline"
+	FieldHolder             string = "holder"
+	FieldHolders            string = "holders"
+	FieldStatus             string = "status"
+
+	FieldClaimed string = "claimed"
+)
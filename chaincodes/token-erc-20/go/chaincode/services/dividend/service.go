@@ -0,0 +1,288 @@
+package dividend
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/kyc"
+)
+
+// CreateDistribution 배당 기록 생성. 재원 입금(escrow 이전)은 controller 에서 처리
+func CreateDistribution(ctx contractapi.TransactionContextInterface, distribution DistributionStruct) (*DistributionStruct, error) {
+
+	if distribution.TotalAmount <= 0 {
+		return nil, fmt.Errorf("invalid amount : %d", distribution.TotalAmount)
+	}
+
+	if distribution.EligibleSupply <= 0 {
+		return nil, fmt.Errorf("no supply at snapshot %d of partition %s", distribution.SnapshotId, distribution.Partition)
+	}
+
+	if distribution.Partition == distribution.CashPartition {
+		return nil, fmt.Errorf("cash partition must be different from the partition : %s", distribution.Partition)
+	}
+
+	if distribution.RoundingPolicy == "" {
+		distribution.RoundingPolicy = RoundingDown
+	}
+
+	if distribution.RoundingPolicy != RoundingDown && distribution.RoundingPolicy != RoundingHalfUp {
+		return nil, fmt.Errorf("unknown rounding policy : %s", distribution.RoundingPolicy)
+	}
+
+	if distribution.WithholdingRates == nil {
+		distribution.WithholdingRates = map[string]int64{}
+	}
+
+	for category, rate := range distribution.WithholdingRates {
+		if !kyc.IsValidCategory(category) {
+			return nil, fmt.Errorf("unknown investor category : %s", category)
+		}
+
+		if rate < 0 || rate > BasisPoints {
+			return nil, fmt.Errorf("withholding rate must be between 0 and %d : %s %d", BasisPoints, category, rate)
+		}
+	}
+
+	if distribution.WithholdingAccount == "" {
+		distribution.WithholdingAccount = distribution.Issuer
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if distribution.ClaimDeadline != 0 && distribution.ClaimDeadline <= now {
+		return nil, fmt.Errorf("claim deadline must be in the future : %d", distribution.ClaimDeadline)
+	}
+
+	distribution.DistributionId = ctx.GetStub().GetTxID()
+	distribution.Status = StatusActive
+	distribution.CreatedAt = now
+
+	distributionKey, err := ctx.GetStub().CreateCompositeKey(DocType_Distribution, []string{distribution.DistributionId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Distribution, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Distribution, distributionKey, distribution, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &distribution, nil
+}
+
+func GetDistribution(ctx contractapi.TransactionContextInterface, distributionId string) (*DistributionStruct, error) {
+
+	distributionKey, err := ctx.GetStub().CreateCompositeKey(DocType_Distribution, []string{distributionId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Distribution, err)
+	}
+
+	distributionBytes, err := ledgermanager.GetState(DocType_Distribution, distributionKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := DistributionStruct{}
+	if err := json.Unmarshal(distributionBytes, &distribution); err != nil {
+		return nil, err
+	}
+
+	if distribution.WithholdingRates == nil {
+		distribution.WithholdingRates = map[string]int64{}
+	}
+
+	return &distribution, nil
+}
+
+// CheckClaimable 지급 가능한 상태인지 (active 이고 청구 기한 전)
+func CheckClaimable(ctx contractapi.TransactionContextInterface, distribution DistributionStruct) error {
+
+	if distribution.Status != StatusActive {
+		return ccutils.CreateError(CodeErrorDistributionNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorDistributionNotActive]+" : "+distribution.DistributionId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if distribution.ClaimDeadline != 0 && now >= distribution.ClaimDeadline {
+		return ccutils.CreateError(CodeErrorClaimPeriodEnded, fmt.Errorf(ErrorCodeMessage[CodeErrorClaimPeriodEnded]+" : "+distribution.DistributionId))
+	}
+
+	return nil
+}
+
+// GetClaim 지급 기록이 없으면 nil
+func GetClaim(ctx contractapi.TransactionContextInterface, distributionId string, holder string) (*ClaimStruct, error) {
+
+	claimKey, err := ctx.GetStub().CreateCompositeKey(DocType_Claim, []string{distributionId, holder})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Claim, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(claimKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	claimBytes, err := ledgermanager.GetState(DocType_Claim, claimKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := ClaimStruct{}
+	if err := json.Unmarshal(claimBytes, &claim); err != nil {
+		return nil, err
+	}
+
+	return &claim, nil
+}
+
+// RecordClaims 지급 기록 저장 후 Allocate 로 갱신된 distribution 합계 저장
+func RecordClaims(ctx contractapi.TransactionContextInterface, distribution DistributionStruct, claims []ClaimStruct, paidBy string, pushed bool) error {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, claim := range claims {
+		claim.PaidBy = paidBy
+		claim.PaidAt = now
+		claim.Pushed = pushed
+
+		claimKey, err := ctx.GetStub().CreateCompositeKey(DocType_Claim, []string{claim.DistributionId, claim.Holder})
+		if err != nil {
+			return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Claim, err)
+		}
+
+		_, err = ledgermanager.PutState(DocType_Claim, claimKey, claim, ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return putDistribution(ctx, distribution)
+}
+
+// CloseDistribution 청구 기한 후 종료. escrow 에 남은 수량(미청구분, 반올림 잔액)을 돌려줌
+func CloseDistribution(ctx contractapi.TransactionContextInterface, distribution DistributionStruct, closedBy string) (*DistributionStruct, error) {
+
+	if distribution.Status != StatusActive {
+		return nil, ccutils.CreateError(CodeErrorDistributionNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorDistributionNotActive]+" : "+distribution.DistributionId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if distribution.ClaimDeadline != 0 && now < distribution.ClaimDeadline {
+		return nil, ccutils.CreateError(CodeErrorClaimPeriodNotEnded, fmt.Errorf(ErrorCodeMessage[CodeErrorClaimPeriodNotEnded]+" : "+distribution.DistributionId))
+	}
+
+	distribution.ReturnedAmount = distribution.TotalAmount - distribution.PaidGross
+	distribution.Status = StatusClosed
+	distribution.ClosedBy = closedBy
+	distribution.ClosedAt = now
+
+	err = putDistribution(ctx, distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	return &distribution, nil
+}
+
+// HasActiveDistribution partition 을 대상 또는 지급 cash 로 쓰는 종료되지 않은 배당이 있는지
+func HasActiveDistribution(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Distribution)
+	queryBuilder.AddSelectorGroup(FieldStatus, StatusActive)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldPartition, partition)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldCashPartition, partition)
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetDistributionList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Distribution)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldPartition, FieldCashPartition, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func GetClaimList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Claim)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldDistributionId, FieldHolder}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func putDistribution(ctx contractapi.TransactionContextInterface, distribution DistributionStruct) error {
+
+	distributionKey, err := ctx.GetStub().CreateCompositeKey(DocType_Distribution, []string{distribution.DistributionId})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Distribution, err)
+	}
+
+	distributionToMap, err := ccutils.StructToMap(distribution)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_Distribution, distributionKey, distributionToMap, ctx)
+}
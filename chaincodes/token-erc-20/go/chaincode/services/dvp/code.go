@@ -0,0 +1,19 @@
+package dvp
+
+const CodeErrorNotParty int = 830
+const CodeErrorDvPNotActive int = 831
+const CodeErrorLegAlreadyFunded int = 832
+const CodeErrorNotFullyFunded int = 833
+const CodeErrorDvPExpired int = 834
+const CodeErrorInvalidSecret int = 835
+const CodeErrorNotCancellable int = 836
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorNotParty:         "DvP error : caller is not a party of the settlement",
+	CodeErrorDvPNotActive:     "DvP error : settlement is already closed",
+	CodeErrorLegAlreadyFunded: "DvP error : leg is already funded",
+	CodeErrorNotFullyFunded:   "DvP error : both legs must be funded",
+	CodeErrorDvPExpired:       "DvP error : settlement is expired",
+	CodeErrorInvalidSecret:    "DvP error : secret does not match the hash lock",
+	CodeErrorNotCancellable:   "DvP error : both legs are funded, settlement can only be executed or refunded after expiration",
+}
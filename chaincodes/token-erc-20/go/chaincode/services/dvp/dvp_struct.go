@@ -0,0 +1,80 @@
+package dvp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	DocType_DvP = "DOCTYPE_DVP"
+)
+
+const (
+	// 매도인의 증권 leg 를 보관하는 escrow wallet 이름 (wallet.EscrowAddress)
+	SecurityEscrowName = "DVP_SECURITY"
+	// 매수인의 대금 leg 를 보관하는 escrow wallet 이름.
+	// 실행 시 escrow wallet 을 partition 별로 한 번씩만 쓰도록 증권과 나눔
+	CashEscrowName = "DVP_CASH"
+)
+
+// DvP status
+const (
+	StatusCreated   = "created"
+	StatusExecuted  = "executed"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+// Leg
+const (
+	LegSecurity = "security"
+	LegCash     = "cash"
+)
+
+// 증권 partition 과 cash partition 의 동시 결제.
+// 양쪽 leg 가 모두 escrow 된 뒤 한 번의 실행으로 교환하고, 만료되면 각자 환불
+type DvPStruct struct {
+	DocType string `json:"docType"`
+
+	DvPId  string `json:"dvpId"`
+	Seller string `json:"seller"`
+	Buyer  string `json:"buyer"`
+
+	Partition     string `json:"partition"`
+	Amount        int64  `json:"amount"`
+	CashPartition string `json:"cashPartition"`
+	CashAmount    int64  `json:"cashAmount"`
+
+	// unix seconds. 만료 후에는 실행 불가, 누구나 환불 가능
+	Expiration int64 `json:"expiration"`
+
+	// HTLC 모드. sha256(secret) hex 가 있으면 실행 시 secret 필요 (다른 channel 결제와 연동)
+	HashLock string `json:"hashLock"`
+	Secret   string `json:"secret"`
+
+	SellerFunded bool `json:"sellerFunded"`
+	BuyerFunded  bool `json:"buyerFunded"`
+
+	Status    string `json:"status"`
+	CreatedBy string `json:"createdBy"`
+	CreatedAt int64  `json:"createdAt"`
+	ClosedBy  string `json:"closedBy"`
+	ClosedAt  int64  `json:"closedAt"`
+}
+
+func (d DvPStruct) IsExpired(now int64) bool {
+
+	return now >= d.Expiration
+}
+
+// MatchSecret hash lock 이 없으면 항상 통과
+func (d DvPStruct) MatchSecret(secret string) bool {
+
+	if d.HashLock == "" {
+		return true
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+	return strings.EqualFold(hex.EncodeToString(hash[:]), d.HashLock)
+}
@@ -0,0 +1,15 @@
+package dvp
+
+const (
+	FieldDvPId         string = "dvpId"
+	FieldSeller        string = "seller"
+	FieldBuyer         string = "buyer"
+	FieldPartition     string = "partition"
+	FieldAmount        string = "amount"
+	FieldCashPartition string = "cashPartition"
+	FieldCashAmount    string = "cashAmount"
+	FieldExpiration    string = "expiration"
+	FieldHashLock      string = "hashLock"
+	FieldSecret        string = "secret"
+	FieldStatus        string = "status"
+)
@@ -0,0 +1,248 @@
+package dvp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// CreateDvP 결제 기록 생성. 각 leg 의 escrow 이전은 controller 에서 처리
+func CreateDvP(ctx contractapi.TransactionContextInterface, settlement DvPStruct) (*DvPStruct, error) {
+
+	if settlement.Amount <= 0 || settlement.CashAmount <= 0 {
+		return nil, fmt.Errorf("invalid amount : amount %d, cash amount %d", settlement.Amount, settlement.CashAmount)
+	}
+
+	if settlement.Seller == settlement.Buyer {
+		return nil, fmt.Errorf("seller and buyer must be different : %s", settlement.Seller)
+	}
+
+	if settlement.Partition == settlement.CashPartition {
+		return nil, fmt.Errorf("cash partition must be different from the partition : %s", settlement.Partition)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if settlement.Expiration <= now {
+		return nil, fmt.Errorf("expiration must be in the future : %d", settlement.Expiration)
+	}
+
+	settlement.DvPId = ctx.GetStub().GetTxID()
+	settlement.Status = StatusCreated
+	settlement.CreatedAt = now
+
+	dvpKey, err := ctx.GetStub().CreateCompositeKey(DocType_DvP, []string{settlement.DvPId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_DvP, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_DvP, dvpKey, settlement, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+func GetDvP(ctx contractapi.TransactionContextInterface, dvpId string) (*DvPStruct, error) {
+
+	dvpKey, err := ctx.GetStub().CreateCompositeKey(DocType_DvP, []string{dvpId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_DvP, err)
+	}
+
+	dvpBytes, err := ledgermanager.GetState(DocType_DvP, dvpKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := DvPStruct{}
+	if err := json.Unmarshal(dvpBytes, &settlement); err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+// FundLeg party 의 leg 를 funded 로 기록. 매도인은 증권, 매수인은 대금 leg
+func FundLeg(ctx contractapi.TransactionContextInterface, settlement DvPStruct, party string) (*DvPStruct, string, error) {
+
+	err := checkActive(ctx, settlement)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var leg string
+	switch party {
+	case settlement.Seller:
+		if settlement.SellerFunded {
+			return nil, "", ccutils.CreateError(CodeErrorLegAlreadyFunded, fmt.Errorf(ErrorCodeMessage[CodeErrorLegAlreadyFunded]+" : "+LegSecurity))
+		}
+		settlement.SellerFunded = true
+		leg = LegSecurity
+	case settlement.Buyer:
+		if settlement.BuyerFunded {
+			return nil, "", ccutils.CreateError(CodeErrorLegAlreadyFunded, fmt.Errorf(ErrorCodeMessage[CodeErrorLegAlreadyFunded]+" : "+LegCash))
+		}
+		settlement.BuyerFunded = true
+		leg = LegCash
+	default:
+		return nil, "", ccutils.CreateError(CodeErrorNotParty, fmt.Errorf(ErrorCodeMessage[CodeErrorNotParty]+" : "+party))
+	}
+
+	err = putDvP(ctx, settlement)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &settlement, leg, nil
+}
+
+// Execute 양쪽 leg 가 모두 funded 이고 만료 전이면 실행 기록. 실제 교환은 controller 에서 처리
+func Execute(ctx contractapi.TransactionContextInterface, settlement DvPStruct, secret string, executedBy string) (*DvPStruct, error) {
+
+	err := checkActive(ctx, settlement)
+	if err != nil {
+		return nil, err
+	}
+
+	if !settlement.SellerFunded || !settlement.BuyerFunded {
+		return nil, ccutils.CreateError(CodeErrorNotFullyFunded, fmt.Errorf(ErrorCodeMessage[CodeErrorNotFullyFunded]+" : "+settlement.DvPId))
+	}
+
+	if !settlement.MatchSecret(secret) {
+		return nil, ccutils.CreateError(CodeErrorInvalidSecret, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidSecret]+" : "+settlement.DvPId))
+	}
+
+	if settlement.HashLock != "" {
+		settlement.Secret = secret
+	}
+
+	return closeDvP(ctx, settlement, StatusExecuted, executedBy)
+}
+
+// Cancel 만료 후에는 누구나 (expired), 만료 전에는 당사자가 양쪽 leg 가 모두 funded 되기 전까지만 (cancelled).
+// funded leg 의 환불은 controller 에서 처리
+func Cancel(ctx contractapi.TransactionContextInterface, settlement DvPStruct, cancelledBy string) (*DvPStruct, error) {
+
+	if settlement.Status != StatusCreated {
+		return nil, ccutils.CreateError(CodeErrorDvPNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorDvPNotActive]+" : "+settlement.DvPId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if settlement.IsExpired(now) {
+		return closeDvP(ctx, settlement, StatusExpired, cancelledBy)
+	}
+
+	if cancelledBy != settlement.Seller && cancelledBy != settlement.Buyer {
+		return nil, ccutils.CreateError(CodeErrorNotParty, fmt.Errorf(ErrorCodeMessage[CodeErrorNotParty]+" : "+cancelledBy))
+	}
+
+	if settlement.SellerFunded && settlement.BuyerFunded {
+		return nil, ccutils.CreateError(CodeErrorNotCancellable, fmt.Errorf(ErrorCodeMessage[CodeErrorNotCancellable]+" : "+settlement.DvPId))
+	}
+
+	return closeDvP(ctx, settlement, StatusCancelled, cancelledBy)
+}
+
+// HasOpenDvP partition 을 증권 또는 cash leg 로 쓰는 미결제 DvP 가 있는지
+func HasOpenDvP(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_DvP)
+	queryBuilder.AddSelectorGroup(FieldStatus, StatusCreated)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldPartition, partition)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldCashPartition, partition)
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetDvPList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_DvP)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldSeller, FieldBuyer, FieldPartition, FieldCashPartition, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+// checkActive 진행 중이고 만료 전인지
+func checkActive(ctx contractapi.TransactionContextInterface, settlement DvPStruct) error {
+
+	if settlement.Status != StatusCreated {
+		return ccutils.CreateError(CodeErrorDvPNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorDvPNotActive]+" : "+settlement.DvPId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if settlement.IsExpired(now) {
+		return ccutils.CreateError(CodeErrorDvPExpired, fmt.Errorf(ErrorCodeMessage[CodeErrorDvPExpired]+" : "+settlement.DvPId))
+	}
+
+	return nil
+}
+
+func closeDvP(ctx contractapi.TransactionContextInterface, settlement DvPStruct, status string, closedBy string) (*DvPStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settlement.Status = status
+	settlement.ClosedBy = closedBy
+	settlement.ClosedAt = now
+
+	err = putDvP(ctx, settlement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+func putDvP(ctx contractapi.TransactionContextInterface, settlement DvPStruct) error {
+
+	dvpKey, err := ctx.GetStub().CreateCompositeKey(DocType_DvP, []string{settlement.DvPId})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_DvP, err)
+	}
+
+	dvpToMap, err := ccutils.StructToMap(settlement)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_DvP, dvpKey, dvpToMap, ctx)
+}
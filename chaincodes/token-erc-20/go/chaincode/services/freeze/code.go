@@ -0,0 +1,13 @@
+package freeze
+
+const CodeErrorWalletFrozen int = 660
+const CodeErrorPartitionFrozen int = 661
+const CodeErrorInsufficientUnfrozen int = 662
+const CodeErrorNotFrozen int = 663
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorWalletFrozen:         "Freeze error : wallet is frozen",
+	CodeErrorPartitionFrozen:      "Freeze error : partition of the wallet is frozen",
+	CodeErrorInsufficientUnfrozen: "Freeze error : insufficient unfrozen balance",
+	CodeErrorNotFrozen:            "Freeze error : no active freeze",
+}
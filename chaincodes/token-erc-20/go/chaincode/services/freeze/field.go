@@ -0,0 +1,9 @@
+package freeze
+
+const (
+	FieldAddress      string = "address"
+	FieldPartition    string = "partition"
+	FieldAmount       string = "amount"
+	FieldReason       string = "reason"
+	FieldReferenceDoc string = "referenceDoc"
+)
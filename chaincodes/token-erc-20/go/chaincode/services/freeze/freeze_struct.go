@@ -0,0 +1,23 @@
+package freeze
+
+const (
+	DocType_Freeze = "DOCTYPE_FREEZE"
+
+	// partition 이 비어 있으면 wallet 전체 동결
+	PartitionAll = ""
+)
+
+type FreezeStruct struct {
+	DocType string `json:"docType"`
+
+	Address   string `json:"address"`
+	Partition string `json:"partition"`
+	// 0 이면 전액 동결, 0 보다 크면 해당 수량만 묶어둠 (partition 단위만 가능)
+	Amount int64 `json:"amount"`
+
+	Reason       string `json:"reason"`
+	ReferenceDoc string `json:"referenceDoc"`
+
+	FrozenBy string `json:"frozenBy"`
+	FrozenAt int64  `json:"frozenAt"`
+}
@@ -0,0 +1,176 @@
+package freeze
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// Freeze 같은 범위에 동결이 있으면 새 내용으로 교체
+func Freeze(ctx contractapi.TransactionContextInterface, freeze FreezeStruct) (*FreezeStruct, error) {
+
+	if freeze.Amount < 0 {
+		return nil, fmt.Errorf("frozen amount cannot be negative")
+	}
+
+	if freeze.Partition == PartitionAll && freeze.Amount != 0 {
+		return nil, fmt.Errorf("partial freeze requires a partition")
+	}
+
+	if freeze.Reason == "" {
+		return nil, fmt.Errorf("reason must not be empty")
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+	freeze.FrozenAt = now
+
+	freezeKey, err := ctx.GetStub().CreateCompositeKey(DocType_Freeze, []string{freeze.Address, freeze.Partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Freeze, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(freezeKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		freezeToMap, err := ccutils.StructToMap(freeze)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_Freeze, freezeKey, freezeToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_Freeze, freezeKey, freeze, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &freeze, nil
+}
+
+func Unfreeze(ctx contractapi.TransactionContextInterface, address string, partition string) error {
+
+	freezeKey, err := ctx.GetStub().CreateCompositeKey(DocType_Freeze, []string{address, partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Freeze, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(freezeKey, ctx)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		return ccutils.CreateError(CodeErrorNotFrozen, fmt.Errorf(ErrorCodeMessage[CodeErrorNotFrozen]+" : "+address))
+	}
+
+	return ledgermanager.DeleteState(DocType_Freeze, freezeKey, ctx)
+}
+
+// GetFreeze 동결이 없으면 nil
+func GetFreeze(ctx contractapi.TransactionContextInterface, address string, partition string) (*FreezeStruct, error) {
+
+	freezeKey, err := ctx.GetStub().CreateCompositeKey(DocType_Freeze, []string{address, partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Freeze, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(freezeKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	freezeBytes, err := ledgermanager.GetState(DocType_Freeze, freezeKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	freeze := FreezeStruct{}
+	if err := json.Unmarshal(freezeBytes, &freeze); err != nil {
+		return nil, err
+	}
+
+	return &freeze, nil
+}
+
+// GetFrozenAmount partition 에서 움직일 수 없는 수량. wallet/partition 전체 동결이면 에러
+func GetFrozenAmount(ctx contractapi.TransactionContextInterface, address string, partition string) (int64, error) {
+
+	walletFreeze, err := GetFreeze(ctx, address, PartitionAll)
+	if err != nil {
+		return 0, err
+	}
+
+	if walletFreeze != nil {
+		return 0, ccutils.CreateError(CodeErrorWalletFrozen, fmt.Errorf(ErrorCodeMessage[CodeErrorWalletFrozen]+" : "+address))
+	}
+
+	partitionFreeze, err := GetFreeze(ctx, address, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	if partitionFreeze == nil {
+		return 0, nil
+	}
+
+	if partitionFreeze.Amount == 0 {
+		return 0, ccutils.CreateError(CodeErrorPartitionFrozen, fmt.Errorf(ErrorCodeMessage[CodeErrorPartitionFrozen]+" : "+address+", "+partition))
+	}
+
+	return partitionFreeze.Amount, nil
+}
+
+// HasPartialFreeze partition 에 수량 지정 동결이 있는지 (전체 동결은 수량이 없어 분할과 무관)
+func HasPartialFreeze(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Freeze)
+	queryBuilder.AddSelectorGroup(FieldPartition, partition)
+	queryBuilder.AddSelectorGroupCondition(FieldAmount, "$gt", 0)
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetFreezeList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Freeze)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldAddress, FieldPartition}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
@@ -0,0 +1,21 @@
+package governance
+
+const CodeErrorBallotNotFound int = 800
+const CodeErrorVotingNotOpen int = 801
+const CodeErrorVotingNotEnded int = 802
+const CodeErrorAlreadyVoted int = 803
+const CodeErrorNoVotingPower int = 804
+const CodeErrorInvalidOption int = 805
+const CodeErrorNotAuthorizedToVote int = 806
+const CodeErrorBallotNotActive int = 807
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorBallotNotFound:      "Governance error : ballot does not exist",
+	CodeErrorVotingNotOpen:       "Governance error : voting is not open",
+	CodeErrorVotingNotEnded:      "Governance error : voting period has not ended",
+	CodeErrorAlreadyVoted:        "Governance error : already voted",
+	CodeErrorNoVotingPower:       "Governance error : no balance at the snapshot",
+	CodeErrorInvalidOption:       "Governance error : unknown option",
+	CodeErrorNotAuthorizedToVote: "Governance error : not authorized to vote for the holder",
+	CodeErrorBallotNotActive:     "Governance error : ballot is already finalized",
+}
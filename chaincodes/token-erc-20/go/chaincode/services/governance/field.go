@@ -0,0 +1,21 @@
+package governance
+
+const (
+	FieldBallotId    string = "ballotId"
+	FieldPartition   string = "partition"
+	FieldSnapshotId  string = "snapshotId"
+	FieldTitle       string = "title"
+	FieldDescription string = "description"
+	FieldOptions     string = "options"
+	FieldStartAt     string = "startAt"
+	FieldEndAt       string = "endAt"
+	FieldQuorum      string = "quorum"
+	FieldThreshold   string = "threshold"
+	FieldOption      string = "option"
+	FieldHolder      string = "holder"
+	FieldHolders     string = "holders"
+	FieldDelegator   string = "delegator"
+	FieldDelegatee   string = "delegatee"
+	FieldOperator    string = "operator"
+	FieldStatus      string = "status"
+)
@@ -0,0 +1,132 @@
+package governance
+
+import "math/big"
+
+const (
+	DocType_Ballot         = "DOCTYPE_BALLOT"
+	DocType_Vote           = "DOCTYPE_VOTE"
+	DocType_VoteDelegation = "DOCTYPE_VOTEDELEGATION"
+	DocType_VotingOperator = "DOCTYPE_VOTINGOPERATOR"
+)
+
+const (
+	// quorum / threshold 단위. 10000 = 100%
+	BasisPoints int64 = 10000
+
+	// 최다 득표 option 이 투표 수의 과반이어야 통과
+	DefaultThreshold int64 = 5000
+)
+
+// Ballot status
+const (
+	StatusActive       = "active"
+	StatusPassed       = "passed"
+	StatusRejected     = "rejected"
+	StatusQuorumNotMet = "quorumNotMet"
+)
+
+// partition 보유자 투표. 의결권은 SnapshotId 시점 잔고
+type BallotStruct struct {
+	DocType string `json:"docType"`
+
+	BallotId    string   `json:"ballotId"`
+	Partition   string   `json:"partition"`
+	SnapshotId  int64    `json:"snapshotId"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Options     []string `json:"options"`
+
+	// unix seconds. StartAt <= now < EndAt 동안 투표 가능
+	StartAt int64 `json:"startAt"`
+	EndAt   int64 `json:"endAt"`
+
+	// basis points. Quorum 은 EligibleSupply 대비 투표 수, Threshold 는 투표 수 대비 최다 득표 (초과)
+	Quorum    int64 `json:"quorum"`
+	Threshold int64 `json:"threshold"`
+	// snapshot 시점 partition 총발행량
+	EligibleSupply int64 `json:"eligibleSupply"`
+
+	Tally      map[string]int64 `json:"tally"`
+	TotalVotes int64            `json:"totalVotes"`
+	VoterCount int64            `json:"voterCount"`
+
+	Status        string `json:"status"`
+	WinningOption string `json:"winningOption"`
+
+	CreatedBy   string `json:"createdBy"`
+	CreatedAt   int64  `json:"createdAt"`
+	FinalizedBy string `json:"finalizedBy"`
+	FinalizedAt int64  `json:"finalizedAt"`
+}
+
+// HasOption option 이 선택지에 있는지
+func (b BallotStruct) HasOption(option string) bool {
+	for _, candidate := range b.Options {
+		if candidate == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Result quorum 과 threshold 를 적용한 결과. 최다 득표가 동률이면 통과 option 없음
+func (b BallotStruct) Result() (string, string) {
+
+	quorum := new(big.Int).Mul(big.NewInt(b.TotalVotes), big.NewInt(BasisPoints))
+	if b.TotalVotes == 0 || quorum.Cmp(new(big.Int).Mul(big.NewInt(b.Quorum), big.NewInt(b.EligibleSupply))) < 0 {
+		return StatusQuorumNotMet, ""
+	}
+
+	var winningOption string
+	var winningVotes int64
+	tie := false
+	for _, option := range b.Options {
+		votes := b.Tally[option]
+		if votes > winningVotes {
+			winningOption, winningVotes, tie = option, votes, false
+		} else if votes == winningVotes && votes > 0 {
+			tie = true
+		}
+	}
+
+	threshold := new(big.Int).Mul(big.NewInt(winningVotes), big.NewInt(BasisPoints))
+	if tie || threshold.Cmp(new(big.Int).Mul(big.NewInt(b.Threshold), big.NewInt(b.TotalVotes))) <= 0 {
+		return StatusRejected, ""
+	}
+
+	return StatusPassed, winningOption
+}
+
+// holder 별 투표. 한 번 행사하면 변경 불가
+type VoteStruct struct {
+	DocType string `json:"docType"`
+
+	BallotId string `json:"ballotId"`
+	Holder   string `json:"holder"`
+	Option   string `json:"option"`
+	Weight   int64  `json:"weight"`
+
+	// 직접 투표면 Holder, 아니면 대리인 또는 partition operator
+	CastBy string `json:"castBy"`
+	CastAt int64  `json:"castAt"`
+}
+
+// partition 의결권 위임. 위임 중에는 Delegatee 만 Delegator 몫을 행사
+type DelegationStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string `json:"partition"`
+	Delegator string `json:"delegator"`
+	Delegatee string `json:"delegatee"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// holder 가 자기 몫을 대신 행사하도록 지정한 partition operator. 위임과 달리 holder 본인도 계속 투표 가능
+type VotingOperatorStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string `json:"partition"`
+	Holder    string `json:"holder"`
+	Operator  string `json:"operator"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
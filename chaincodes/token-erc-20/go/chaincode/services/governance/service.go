@@ -0,0 +1,604 @@
+package governance
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// CreateBallot 투표 생성. snapshot 선언과 EligibleSupply 계산은 controller 에서 처리
+func CreateBallot(ctx contractapi.TransactionContextInterface, ballot BallotStruct) (*BallotStruct, error) {
+
+	if ballot.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+
+	if len(ballot.Options) < 2 {
+		return nil, fmt.Errorf("at least two options are required")
+	}
+
+	seen := make(map[string]bool)
+	for _, option := range ballot.Options {
+		if option == "" || seen[option] {
+			return nil, fmt.Errorf("options must be unique and non-empty : %s", option)
+		}
+		seen[option] = true
+	}
+
+	if ballot.Threshold == 0 {
+		ballot.Threshold = DefaultThreshold
+	}
+
+	if ballot.Quorum < 0 || ballot.Quorum > BasisPoints || ballot.Threshold < 0 || ballot.Threshold >= BasisPoints {
+		return nil, fmt.Errorf("quorum must be between 0 and %d and threshold below %d : %d, %d", BasisPoints, BasisPoints, ballot.Quorum, ballot.Threshold)
+	}
+
+	if ballot.EligibleSupply <= 0 {
+		return nil, fmt.Errorf("no supply at snapshot %d of partition %s", ballot.SnapshotId, ballot.Partition)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if ballot.StartAt == 0 {
+		ballot.StartAt = now
+	}
+
+	if ballot.EndAt <= ballot.StartAt || ballot.EndAt <= now {
+		return nil, fmt.Errorf("voting period must end after it starts and in the future : %d ~ %d", ballot.StartAt, ballot.EndAt)
+	}
+
+	ballot.BallotId = ctx.GetStub().GetTxID()
+	ballot.Tally = make(map[string]int64)
+	for _, option := range ballot.Options {
+		ballot.Tally[option] = 0
+	}
+	ballot.Status = StatusActive
+	ballot.CreatedAt = now
+
+	ballotKey, err := ctx.GetStub().CreateCompositeKey(DocType_Ballot, []string{ballot.BallotId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Ballot, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Ballot, ballotKey, ballot, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ballot, nil
+}
+
+func GetBallot(ctx contractapi.TransactionContextInterface, ballotId string) (*BallotStruct, error) {
+
+	ballotKey, err := ctx.GetStub().CreateCompositeKey(DocType_Ballot, []string{ballotId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Ballot, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(ballotKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, ccutils.CreateError(CodeErrorBallotNotFound, fmt.Errorf(ErrorCodeMessage[CodeErrorBallotNotFound]+" : "+ballotId))
+	}
+
+	ballotBytes, err := ledgermanager.GetState(DocType_Ballot, ballotKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ballot := BallotStruct{}
+	if err := json.Unmarshal(ballotBytes, &ballot); err != nil {
+		return nil, err
+	}
+
+	if ballot.Tally == nil {
+		ballot.Tally = make(map[string]int64)
+	}
+
+	return &ballot, nil
+}
+
+// CheckVotingOpen active 이고 투표 기간 중인지
+func CheckVotingOpen(ctx contractapi.TransactionContextInterface, ballot BallotStruct) error {
+
+	if ballot.Status != StatusActive {
+		return ccutils.CreateError(CodeErrorBallotNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorBallotNotActive]+" : "+ballot.BallotId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if now < ballot.StartAt || now >= ballot.EndAt {
+		return ccutils.CreateError(CodeErrorVotingNotOpen, fmt.Errorf(ErrorCodeMessage[CodeErrorVotingNotOpen]+" : %s (%d ~ %d)", ballot.BallotId, ballot.StartAt, ballot.EndAt))
+	}
+
+	return nil
+}
+
+// GetVote 투표 기록이 없으면 nil
+func GetVote(ctx contractapi.TransactionContextInterface, ballotId string, holder string) (*VoteStruct, error) {
+
+	voteKey, err := ctx.GetStub().CreateCompositeKey(DocType_Vote, []string{ballotId, holder})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Vote, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(voteKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	voteBytes, err := ledgermanager.GetState(DocType_Vote, voteKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vote := VoteStruct{}
+	if err := json.Unmarshal(voteBytes, &vote); err != nil {
+		return nil, err
+	}
+
+	return &vote, nil
+}
+
+// RecordVotes 투표 기록 후 집계 반영. 같은 tx 에서 ballot 을 한 번만 쓰도록 여러 holder 를 한 번에 처리
+func RecordVotes(ctx contractapi.TransactionContextInterface, ballot BallotStruct, votes []VoteStruct) (*BallotStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, vote := range votes {
+		if !ballot.HasOption(vote.Option) {
+			return nil, ccutils.CreateError(CodeErrorInvalidOption, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidOption]+" : "+vote.Option))
+		}
+
+		if vote.Weight <= 0 {
+			return nil, ccutils.CreateError(CodeErrorNoVotingPower, fmt.Errorf(ErrorCodeMessage[CodeErrorNoVotingPower]+" : "+vote.Holder))
+		}
+
+		existing, err := GetVote(ctx, ballot.BallotId, vote.Holder)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil || seen[vote.Holder] {
+			return nil, ccutils.CreateError(CodeErrorAlreadyVoted, fmt.Errorf(ErrorCodeMessage[CodeErrorAlreadyVoted]+" : "+vote.Holder))
+		}
+		seen[vote.Holder] = true
+
+		vote.BallotId = ballot.BallotId
+		vote.CastAt = now
+
+		voteKey, err := ctx.GetStub().CreateCompositeKey(DocType_Vote, []string{ballot.BallotId, vote.Holder})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Vote, err)
+		}
+
+		_, err = ledgermanager.PutState(DocType_Vote, voteKey, vote, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ballot.Tally[vote.Option] += vote.Weight
+		ballot.TotalVotes += vote.Weight
+		ballot.VoterCount++
+	}
+
+	err = putBallot(ctx, ballot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ballot, nil
+}
+
+// FinalizeBallot 투표 기간 후 quorum / threshold 를 적용해 결과 확정
+func FinalizeBallot(ctx contractapi.TransactionContextInterface, ballot BallotStruct, finalizedBy string) (*BallotStruct, error) {
+
+	if ballot.Status != StatusActive {
+		return nil, ccutils.CreateError(CodeErrorBallotNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorBallotNotActive]+" : "+ballot.BallotId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if now < ballot.EndAt {
+		return nil, ccutils.CreateError(CodeErrorVotingNotEnded, fmt.Errorf(ErrorCodeMessage[CodeErrorVotingNotEnded]+" : "+ballot.BallotId))
+	}
+
+	ballot.Status, ballot.WinningOption = ballot.Result()
+	ballot.FinalizedBy = finalizedBy
+	ballot.FinalizedAt = now
+
+	err = putBallot(ctx, ballot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ballot, nil
+}
+
+// SetDelegation delegator 의 partition 의결권을 delegatee 에게 위임. delegatee 가 비어 있으면 위임 해제
+func SetDelegation(ctx contractapi.TransactionContextInterface, partition string, delegator string, delegatee string) (*DelegationStruct, error) {
+
+	if delegator == delegatee {
+		return nil, fmt.Errorf("cannot delegate to self")
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delegationKey, err := ctx.GetStub().CreateCompositeKey(DocType_VoteDelegation, []string{partition, delegator})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_VoteDelegation, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(delegationKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delegation := DelegationStruct{Partition: partition, Delegator: delegator, Delegatee: delegatee, UpdatedAt: now}
+
+	if delegatee == "" {
+		if !exist {
+			return nil, fmt.Errorf("no delegation of %s on partition %s", delegator, partition)
+		}
+
+		return &delegation, ledgermanager.DeleteState(DocType_VoteDelegation, delegationKey, ctx)
+	}
+
+	if exist {
+		delegationToMap, err := ccutils.StructToMap(delegation)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_VoteDelegation, delegationKey, delegationToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_VoteDelegation, delegationKey, delegation, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &delegation, nil
+}
+
+// GetDelegation 위임하지 않았으면 nil
+func GetDelegation(ctx contractapi.TransactionContextInterface, partition string, delegator string) (*DelegationStruct, error) {
+
+	delegationKey, err := ctx.GetStub().CreateCompositeKey(DocType_VoteDelegation, []string{partition, delegator})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_VoteDelegation, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(delegationKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	delegationBytes, err := ledgermanager.GetState(DocType_VoteDelegation, delegationKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delegation := DelegationStruct{}
+	if err := json.Unmarshal(delegationBytes, &delegation); err != nil {
+		return nil, err
+	}
+
+	return &delegation, nil
+}
+
+// SetVotingOperator operator 를 빈 문자열로 보내면 지정 해제
+func SetVotingOperator(ctx contractapi.TransactionContextInterface, partition string, holder string, operator string) (*VotingOperatorStruct, error) {
+
+	if holder == operator {
+		return nil, fmt.Errorf("cannot authorize self as voting operator")
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	operatorKey, err := ctx.GetStub().CreateCompositeKey(DocType_VotingOperator, []string{partition, holder})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_VotingOperator, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(operatorKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	votingOperator := VotingOperatorStruct{Partition: partition, Holder: holder, Operator: operator, UpdatedAt: now}
+
+	if operator == "" {
+		if !exist {
+			return nil, fmt.Errorf("no voting operator of %s on partition %s", holder, partition)
+		}
+
+		return &votingOperator, ledgermanager.DeleteState(DocType_VotingOperator, operatorKey, ctx)
+	}
+
+	if exist {
+		operatorToMap, err := ccutils.StructToMap(votingOperator)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_VotingOperator, operatorKey, operatorToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_VotingOperator, operatorKey, votingOperator, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &votingOperator, nil
+}
+
+// GetVotingOperator 지정하지 않았으면 nil
+func GetVotingOperator(ctx contractapi.TransactionContextInterface, partition string, holder string) (*VotingOperatorStruct, error) {
+
+	operatorKey, err := ctx.GetStub().CreateCompositeKey(DocType_VotingOperator, []string{partition, holder})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_VotingOperator, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(operatorKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	operatorBytes, err := ledgermanager.GetState(DocType_VotingOperator, operatorKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	votingOperator := VotingOperatorStruct{}
+	if err := json.Unmarshal(operatorBytes, &votingOperator); err != nil {
+		return nil, err
+	}
+
+	return &votingOperator, nil
+}
+
+func GetBallotList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	return getList(DocType_Ballot, []string{FieldPartition, FieldStatus}, args, pageSize, bookmark, ctx)
+}
+
+func GetVoteList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	return getList(DocType_Vote, []string{FieldBallotId, FieldHolder, FieldOption}, args, pageSize, bookmark, ctx)
+}
+
+func GetDelegationList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	return getList(DocType_VoteDelegation, []string{FieldPartition, FieldDelegator, FieldDelegatee}, args, pageSize, bookmark, ctx)
+}
+
+// MigrateVoting 키 분실 복구 시 old wallet 이 위임자/수임자, holder/operator 인 의결권 설정을 new wallet 으로 옮김.
+// new wallet 에 이미 같은 partition 설정이 있으면 new wallet 설정을 유지
+func MigrateVoting(ctx contractapi.TransactionContextInterface, oldAddress string, newAddress string) error {
+
+	delegations := []DelegationStruct{}
+	for _, field := range []string{FieldDelegator, FieldDelegatee} {
+		err := queryRecords(ctx, DocType_VoteDelegation, field, oldAddress, func(value []byte) error {
+			delegation := DelegationStruct{}
+			if err := json.Unmarshal(value, &delegation); err != nil {
+				return err
+			}
+			delegations = append(delegations, delegation)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, delegation := range delegations {
+		if delegation.Delegator == oldAddress {
+			_, err := SetDelegation(ctx, delegation.Partition, oldAddress, "")
+			if err != nil {
+				return err
+			}
+
+			if delegation.Delegatee == newAddress {
+				continue
+			}
+
+			existing, err := GetDelegation(ctx, delegation.Partition, newAddress)
+			if err != nil {
+				return err
+			}
+
+			if existing == nil {
+				_, err = SetDelegation(ctx, delegation.Partition, newAddress, delegation.Delegatee)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// old wallet 이 수임자
+		if delegation.Delegator == newAddress {
+			_, err := SetDelegation(ctx, delegation.Partition, newAddress, "")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := SetDelegation(ctx, delegation.Partition, delegation.Delegator, newAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	votingOperators := []VotingOperatorStruct{}
+	for _, field := range []string{FieldHolder, FieldOperator} {
+		err := queryRecords(ctx, DocType_VotingOperator, field, oldAddress, func(value []byte) error {
+			votingOperator := VotingOperatorStruct{}
+			if err := json.Unmarshal(value, &votingOperator); err != nil {
+				return err
+			}
+			votingOperators = append(votingOperators, votingOperator)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, votingOperator := range votingOperators {
+		if votingOperator.Holder == oldAddress {
+			_, err := SetVotingOperator(ctx, votingOperator.Partition, oldAddress, "")
+			if err != nil {
+				return err
+			}
+
+			if votingOperator.Operator == newAddress {
+				continue
+			}
+
+			existing, err := GetVotingOperator(ctx, votingOperator.Partition, newAddress)
+			if err != nil {
+				return err
+			}
+
+			if existing == nil {
+				_, err = SetVotingOperator(ctx, votingOperator.Partition, newAddress, votingOperator.Operator)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// old wallet 이 operator (operator 권한은 operator.MigrateOperator 가 옮김)
+		if votingOperator.Holder == newAddress {
+			_, err := SetVotingOperator(ctx, votingOperator.Partition, newAddress, "")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := SetVotingOperator(ctx, votingOperator.Partition, votingOperator.Holder, newAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func queryRecords(ctx contractapi.TransactionContextInterface, docType string, field string, value string, handle func([]byte) error) error {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, docType)
+	queryBuilder.AddSelectorGroup(field, value)
+
+	resultsIterator, err := ctx.GetStub().GetQueryResult(queryBuilder.MakeQueryString())
+	if err != nil {
+		return err
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return err
+		}
+
+		err = handle(queryResponse.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getList(docType string, stringParameterFields []string, args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, docType)
+
+	// 고유 필드
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func putBallot(ctx contractapi.TransactionContextInterface, ballot BallotStruct) error {
+
+	ballotKey, err := ctx.GetStub().CreateCompositeKey(DocType_Ballot, []string{ballot.BallotId})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Ballot, err)
+	}
+
+	ballotToMap, err := ccutils.StructToMap(ballot)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_Ballot, ballotKey, ballotToMap, ctx)
+}
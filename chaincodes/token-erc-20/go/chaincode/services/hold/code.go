@@ -0,0 +1,19 @@
+package hold
+
+const CodeErrorInsufficientUnheld int = 820
+const CodeErrorHoldNotActive int = 821
+const CodeErrorHoldExpired int = 822
+const CodeErrorHoldNotExpired int = 823
+const CodeErrorNotNotary int = 824
+const CodeErrorInvalidSecret int = 825
+const CodeErrorExceedsHeldAmount int = 826
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorInsufficientUnheld: "Hold error : insufficient balance not on hold",
+	CodeErrorHoldNotActive:      "Hold error : hold is not active",
+	CodeErrorHoldExpired:        "Hold error : hold is expired",
+	CodeErrorHoldNotExpired:     "Hold error : hold is not expired",
+	CodeErrorNotNotary:          "Hold error : caller is not the notary of the hold",
+	CodeErrorInvalidSecret:      "Hold error : secret does not match the secret hash",
+	CodeErrorExceedsHeldAmount:  "Hold error : amount exceeds the remaining held amount",
+}
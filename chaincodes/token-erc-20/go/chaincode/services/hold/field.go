@@ -0,0 +1,15 @@
+package hold
+
+const (
+	FieldHoldId     string = "holdId"
+	FieldPartition  string = "partition"
+	FieldHolder     string = "holder"
+	FieldRecipient  string = "recipient"
+	FieldNotary     string = "notary"
+	FieldAmount     string = "amount"
+	FieldExpiration string = "expiration"
+	FieldSecretHash string = "secretHash"
+	FieldSecret     string = "secret"
+	FieldStatus     string = "status"
+	FieldHeld       string = "held"
+)
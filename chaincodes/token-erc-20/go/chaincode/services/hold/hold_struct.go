@@ -0,0 +1,103 @@
+package hold
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	DocType_Hold        = "DOCTYPE_HOLD"
+	DocType_HeldBalance = "DOCTYPE_HELDBALANCE"
+)
+
+// Hold status (ERC-1996)
+const (
+	StatusOrdered              = "ordered"
+	StatusExecutedAndKeptOpen  = "executedAndKeptOpen"
+	StatusExecuted             = "executed"
+	StatusReleasedByNotary     = "releasedByNotary"
+	StatusReleasedOnExpiration = "releasedOnExpiration"
+)
+
+// Holder 의 partition 잔고 중 Recipient 앞으로 묶어 둔 수량.
+// Notary 가 실행(부분 실행 가능)하거나 해제하고, Expiration 이 지나면 자동 해제
+type HoldStruct struct {
+	DocType string `json:"docType"`
+
+	HoldId    string `json:"holdId"`
+	Partition string `json:"partition"`
+	Holder    string `json:"holder"`
+	Recipient string `json:"recipient"`
+	Notary    string `json:"notary"`
+
+	Amount         int64 `json:"amount"`
+	ExecutedAmount int64 `json:"executedAmount"`
+	// unix seconds. 0 이면 만료 없음
+	Expiration int64 `json:"expiration"`
+
+	// sha256(secret) hex. 있으면 실행 시 secret 필요
+	SecretHash string `json:"secretHash"`
+	Secret     string `json:"secret"`
+
+	Status    string `json:"status"`
+	CreatedBy string `json:"createdBy"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// Remaining 아직 실행되지 않은 수량
+func (h HoldStruct) Remaining() int64 {
+
+	return h.Amount - h.ExecutedAmount
+}
+
+func (h HoldStruct) IsActive() bool {
+
+	return h.Status == StatusOrdered || h.Status == StatusExecutedAndKeptOpen
+}
+
+func (h HoldStruct) IsExpired(now int64) bool {
+
+	return h.Expiration != 0 && now >= h.Expiration
+}
+
+// MatchSecret secret hash 가 없으면 항상 통과
+func (h HoldStruct) MatchSecret(secret string) bool {
+
+	if h.SecretHash == "" {
+		return true
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+	return strings.EqualFold(hex.EncodeToString(hash[:]), h.SecretHash)
+}
+
+type HeldStruct struct {
+	Amount     int64 `json:"amount"`
+	Expiration int64 `json:"expiration"`
+}
+
+// (holder, partition) 의 활성 hold 별 남은 수량. key 는 holdId
+type HeldBalanceStruct struct {
+	DocType string `json:"docType"`
+
+	Holder    string                `json:"holder"`
+	Partition string                `json:"partition"`
+	Holds     map[string]HeldStruct `json:"holds"`
+}
+
+// HeldAmount now 시점에 만료되지 않은 hold 합계. except hold 는 제외
+func (b HeldBalanceStruct) HeldAmount(now int64, except string) int64 {
+
+	var total int64
+	for holdId, held := range b.Holds {
+		if holdId == except || (held.Expiration != 0 && now >= held.Expiration) {
+			continue
+		}
+		total += held.Amount
+	}
+
+	return total
+}
@@ -0,0 +1,314 @@
+package hold
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// CreateHold hold 저장 후 Holder 의 잔고에서 Amount 만큼 묶음. 출금 가능 여부는 controller 에서 확인
+func CreateHold(ctx contractapi.TransactionContextInterface, hold HoldStruct) (*HoldStruct, error) {
+
+	if hold.Amount <= 0 {
+		return nil, fmt.Errorf("invalid amount : %d", hold.Amount)
+	}
+
+	if hold.Notary == "" {
+		return nil, fmt.Errorf("notary is required")
+	}
+
+	if hold.Holder == hold.Recipient {
+		return nil, fmt.Errorf("cannot hold toward the same wallet")
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.Expiration != 0 && hold.Expiration <= now {
+		return nil, fmt.Errorf("expiration must be in the future : %d", hold.Expiration)
+	}
+
+	hold.HoldId = ctx.GetStub().GetTxID()
+	hold.Status = StatusOrdered
+	hold.CreatedAt = now
+	hold.UpdatedBy = hold.CreatedBy
+	hold.UpdatedAt = now
+
+	holdKey, err := ctx.GetStub().CreateCompositeKey(DocType_Hold, []string{hold.HoldId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Hold, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Hold, holdKey, hold, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	heldBalance, exist, err := getHeldBalance(ctx, hold.Holder, hold.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	// 만료된 hold 는 여기서 정리
+	for holdId, held := range heldBalance.Holds {
+		if held.Expiration != 0 && now >= held.Expiration {
+			delete(heldBalance.Holds, holdId)
+		}
+	}
+	heldBalance.Holds[hold.HoldId] = HeldStruct{Amount: hold.Amount, Expiration: hold.Expiration}
+
+	err = putHeldBalance(ctx, *heldBalance, exist)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+func GetHold(ctx contractapi.TransactionContextInterface, holdId string) (*HoldStruct, error) {
+
+	holdKey, err := ctx.GetStub().CreateCompositeKey(DocType_Hold, []string{holdId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Hold, err)
+	}
+
+	holdBytes, err := ledgermanager.GetState(DocType_Hold, holdKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hold := HoldStruct{}
+	if err := json.Unmarshal(holdBytes, &hold); err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// ExecuteHold notary 가 만료 전에 amount 만큼 실행. 남은 수량이 있으면 계속 묶여 있음.
+// 실제 이전은 controller 에서 처리
+func ExecuteHold(ctx contractapi.TransactionContextInterface, hold HoldStruct, amount int64, secret string, executedBy string) (*HoldStruct, error) {
+
+	if !hold.IsActive() {
+		return nil, ccutils.CreateError(CodeErrorHoldNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorHoldNotActive]+" : "+hold.HoldId))
+	}
+
+	if executedBy != hold.Notary {
+		return nil, ccutils.CreateError(CodeErrorNotNotary, fmt.Errorf(ErrorCodeMessage[CodeErrorNotNotary]+" : "+executedBy))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.IsExpired(now) {
+		return nil, ccutils.CreateError(CodeErrorHoldExpired, fmt.Errorf(ErrorCodeMessage[CodeErrorHoldExpired]+" : "+hold.HoldId))
+	}
+
+	if !hold.MatchSecret(secret) {
+		return nil, ccutils.CreateError(CodeErrorInvalidSecret, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidSecret]+" : "+hold.HoldId))
+	}
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("invalid amount : %d", amount)
+	}
+
+	if amount > hold.Remaining() {
+		return nil, ccutils.CreateError(CodeErrorExceedsHeldAmount, fmt.Errorf(ErrorCodeMessage[CodeErrorExceedsHeldAmount]+" : remaining %d, requested %d", hold.Remaining(), amount))
+	}
+
+	hold.ExecutedAmount += amount
+	if hold.SecretHash != "" {
+		hold.Secret = secret
+	}
+
+	hold.Status = StatusExecutedAndKeptOpen
+	if hold.Remaining() == 0 {
+		hold.Status = StatusExecuted
+	}
+
+	return updateHold(ctx, hold, executedBy, now)
+}
+
+// ReleaseHold 남은 수량을 풀어줌. notary 는 언제든, 그 외에는 만료 후에만 가능
+func ReleaseHold(ctx contractapi.TransactionContextInterface, hold HoldStruct, releasedBy string) (*HoldStruct, error) {
+
+	if !hold.IsActive() {
+		return nil, ccutils.CreateError(CodeErrorHoldNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorHoldNotActive]+" : "+hold.HoldId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case hold.IsExpired(now):
+		hold.Status = StatusReleasedOnExpiration
+	case releasedBy == hold.Notary:
+		hold.Status = StatusReleasedByNotary
+	default:
+		return nil, ccutils.CreateError(CodeErrorHoldNotExpired, fmt.Errorf(ErrorCodeMessage[CodeErrorHoldNotExpired]+" : "+hold.HoldId))
+	}
+
+	return updateHold(ctx, hold, releasedBy, now)
+}
+
+// GetHeldAmount holder 의 partition 잔고 중 만료되지 않은 hold 에 묶인 수량. except hold 는 제외
+func GetHeldAmount(ctx contractapi.TransactionContextInterface, holder string, partition string, except string) (int64, error) {
+
+	heldBalance, exist, err := getHeldBalance(ctx, holder, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exist {
+		return 0, nil
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return heldBalance.HeldAmount(now, except), nil
+}
+
+// HasActiveHold partition 에 실행/해제되지 않은 hold 가 있는지
+func HasActiveHold(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Hold)
+	queryBuilder.AddSelectorGroup(FieldPartition, partition)
+	queryBuilder.AddSelectorGroupCondition(FieldStatus, "$in", []string{StatusOrdered, StatusExecutedAndKeptOpen})
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetHoldList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Hold)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldHolder, FieldNotary, FieldRecipient, FieldPartition, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+// updateHold hold 저장 후 묶인 수량을 남은 수량으로 갱신 (종료된 hold 는 제거)
+func updateHold(ctx contractapi.TransactionContextInterface, hold HoldStruct, updatedBy string, now int64) (*HoldStruct, error) {
+
+	hold.UpdatedBy = updatedBy
+	hold.UpdatedAt = now
+
+	holdKey, err := ctx.GetStub().CreateCompositeKey(DocType_Hold, []string{hold.HoldId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Hold, err)
+	}
+
+	holdToMap, err := ccutils.StructToMap(hold)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_Hold, holdKey, holdToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	heldBalance, exist, err := getHeldBalance(ctx, hold.Holder, hold.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		if hold.IsActive() {
+			heldBalance.Holds[hold.HoldId] = HeldStruct{Amount: hold.Remaining(), Expiration: hold.Expiration}
+		} else {
+			delete(heldBalance.Holds, hold.HoldId)
+		}
+
+		err = putHeldBalance(ctx, *heldBalance, exist)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &hold, nil
+}
+
+func getHeldBalance(ctx contractapi.TransactionContextInterface, holder string, partition string) (*HeldBalanceStruct, bool, error) {
+
+	heldKey, err := ctx.GetStub().CreateCompositeKey(DocType_HeldBalance, []string{holder, partition})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_HeldBalance, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(heldKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !exist {
+		return &HeldBalanceStruct{DocType: DocType_HeldBalance, Holder: holder, Partition: partition, Holds: map[string]HeldStruct{}}, false, nil
+	}
+
+	heldBytes, err := ledgermanager.GetState(DocType_HeldBalance, heldKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	heldBalance := HeldBalanceStruct{}
+	if err := json.Unmarshal(heldBytes, &heldBalance); err != nil {
+		return nil, false, err
+	}
+
+	if heldBalance.Holds == nil {
+		heldBalance.Holds = map[string]HeldStruct{}
+	}
+
+	return &heldBalance, true, nil
+}
+
+func putHeldBalance(ctx contractapi.TransactionContextInterface, heldBalance HeldBalanceStruct, exist bool) error {
+
+	heldKey, err := ctx.GetStub().CreateCompositeKey(DocType_HeldBalance, []string{heldBalance.Holder, heldBalance.Partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_HeldBalance, err)
+	}
+
+	if exist {
+		heldToMap, err := ccutils.StructToMap(heldBalance)
+		if err != nil {
+			return err
+		}
+
+		return ledgermanager.UpdateState(DocType_HeldBalance, heldKey, heldToMap, ctx)
+	}
+
+	_, err = ledgermanager.PutState(DocType_HeldBalance, heldKey, heldBalance, ctx)
+	return err
+}
@@ -0,0 +1,7 @@
+package holders
+
+const CodeErrorMaxHoldersExceeded int = 730
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorMaxHoldersExceeded: "Holder error : maximum number of holders exceeded",
+}
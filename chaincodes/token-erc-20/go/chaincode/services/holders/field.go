@@ -0,0 +1,6 @@
+package holders
+
+const (
+	FieldPartition  string = "partition"
+	FieldMaxHolders string = "maxHolders"
+)
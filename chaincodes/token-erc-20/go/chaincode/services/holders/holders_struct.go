@@ -0,0 +1,26 @@
+package holders
+
+const (
+	DocType_HolderCount = "DOCTYPE_HOLDERCOUNT"
+)
+
+// partition 별 잔고가 0 보다 큰 보유자 수
+type HolderCountStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string `json:"partition"`
+	Count     int64  `json:"count"`
+	// 0 이면 무제한
+	MaxHolders int64 `json:"maxHolders"`
+}
+
+// Delta 잔고 변동에 따른 보유자 수 변화. 0→양수 +1, 양수→0 -1
+func Delta(before int64, after int64) int64 {
+	if before <= 0 && after > 0 {
+		return 1
+	}
+	if before > 0 && after <= 0 {
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package holders
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// GetHolderCount 기록이 없으면 0
+func GetHolderCount(ctx contractapi.TransactionContextInterface, partition string) (*HolderCountStruct, error) {
+
+	countKey, err := ctx.GetStub().CreateCompositeKey(DocType_HolderCount, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_HolderCount, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(countKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &HolderCountStruct{DocType: DocType_HolderCount, Partition: partition}, nil
+	}
+
+	countBytes, err := ledgermanager.GetState(DocType_HolderCount, countKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	holderCount := HolderCountStruct{}
+	if err := json.Unmarshal(countBytes, &holderCount); err != nil {
+		return nil, err
+	}
+
+	return &holderCount, nil
+}
+
+// Apply 한 트랜잭션에서 partition 당 한 번만 호출. 같은 tx 의 이전 기록은 GetState 로 보이지 않음
+func Apply(ctx contractapi.TransactionContextInterface, partition string, delta int64) error {
+
+	if delta == 0 {
+		return nil
+	}
+
+	holderCount, err := GetHolderCount(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	// 카운터 도입 전 보유자가 있던 partition 은 SetHolderCount 로 먼저 초기화해야 정확함
+	holderCount.Count += delta
+
+	if delta > 0 && holderCount.MaxHolders > 0 && holderCount.Count > holderCount.MaxHolders {
+		return ccutils.CreateError(CodeErrorMaxHoldersExceeded, fmt.Errorf(ErrorCodeMessage[CodeErrorMaxHoldersExceeded]+" : %s, max %d", partition, holderCount.MaxHolders))
+	}
+
+	return putHolderCount(ctx, *holderCount)
+}
+
+// SetHolderCount 카운터 도입 전부터 보유자가 있던 partition 의 보유자 수 초기화
+func SetHolderCount(ctx contractapi.TransactionContextInterface, partition string, count int64) (*HolderCountStruct, error) {
+
+	if count < 0 {
+		return nil, fmt.Errorf("holder count cannot be negative")
+	}
+
+	holderCount, err := GetHolderCount(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	holderCount.Count = count
+
+	err = putHolderCount(ctx, *holderCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return holderCount, nil
+}
+
+// SetMaxHolders 0 이면 무제한. 현재 보유자 수보다 작게 설정하면 새 보유자만 막음
+func SetMaxHolders(ctx contractapi.TransactionContextInterface, partition string, maxHolders int64) (*HolderCountStruct, error) {
+
+	if maxHolders < 0 {
+		return nil, fmt.Errorf("max holders cannot be negative")
+	}
+
+	holderCount, err := GetHolderCount(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	holderCount.MaxHolders = maxHolders
+
+	err = putHolderCount(ctx, *holderCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return holderCount, nil
+}
+
+func putHolderCount(ctx contractapi.TransactionContextInterface, holderCount HolderCountStruct) error {
+
+	countKey, err := ctx.GetStub().CreateCompositeKey(DocType_HolderCount, []string{holderCount.Partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_HolderCount, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(countKey, ctx)
+	if err != nil {
+		return err
+	}
+
+	if exist {
+		countToMap, err := ccutils.StructToMap(holderCount)
+		if err != nil {
+			return err
+		}
+
+		return ledgermanager.UpdateState(DocType_HolderCount, countKey, countToMap, ctx)
+	}
+
+	_, err = ledgermanager.PutState(DocType_HolderCount, countKey, holderCount, ctx)
+	return err
+}
@@ -0,0 +1,11 @@
+package kyc
+
+const CodeErrorNotVerified int = 680
+const CodeErrorExpired int = 681
+const CodeErrorLevelTooLow int = 682
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorNotVerified: "KYC error : investor is not verified",
+	CodeErrorExpired:     "KYC error : investor verification is expired",
+	CodeErrorLevelTooLow: "KYC error : investor verification level is too low",
+}
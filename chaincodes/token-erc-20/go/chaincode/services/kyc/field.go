@@ -0,0 +1,14 @@
+package kyc
+
+const (
+	FieldAddress     string = "address"
+	FieldStatus      string = "status"
+	FieldLevel       string = "level"
+	FieldCountryCode string = "countryCode"
+	FieldCategory    string = "category"
+	FieldExpiresAt   string = "expiresAt"
+
+	FieldPartition         string = "partition"
+	FieldAllowlistRequired string = "allowlistRequired"
+	FieldMinLevel          string = "minLevel"
+)
@@ -0,0 +1,62 @@
+package kyc
+
+const (
+	DocType_Investor     = "DOCTYPE_INVESTOR"
+	DocType_PartitionKyc = "DOCTYPE_PARTITIONKYC"
+)
+
+// KYC status
+const (
+	StatusPending  = "pending"
+	StatusVerified = "verified"
+	StatusRejected = "rejected"
+	StatusRevoked  = "revoked"
+)
+
+// Investor category
+const (
+	CategoryRetail       = "retail"
+	CategoryQualified    = "qualified"
+	CategoryProfessional = "professional"
+	CategoryInstitution  = "institution"
+)
+
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusVerified, StatusRejected, StatusRevoked:
+		return true
+	}
+	return false
+}
+
+func IsValidCategory(category string) bool {
+	switch category {
+	case CategoryRetail, CategoryQualified, CategoryProfessional, CategoryInstitution:
+		return true
+	}
+	return false
+}
+
+type InvestorStruct struct {
+	DocType string `json:"docType"`
+
+	Address     string `json:"address"`
+	Status      string `json:"status"`
+	Level       int64  `json:"level"`
+	CountryCode string `json:"countryCode"`
+	Category    string `json:"category"`
+	// unix seconds. 0 이면 만료 없음
+	ExpiresAt int64 `json:"expiresAt"`
+
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// partition 별 allowlist 설정. 설정이 없는 partition 은 누구나 받을 수 있음
+type PartitionKycStruct struct {
+	DocType string `json:"docType"`
+
+	Partition         string `json:"partition"`
+	AllowlistRequired bool   `json:"allowlistRequired"`
+	MinLevel          int64  `json:"minLevel"`
+}
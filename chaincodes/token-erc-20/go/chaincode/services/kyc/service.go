@@ -0,0 +1,245 @@
+package kyc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+func SetInvestor(ctx contractapi.TransactionContextInterface, investor InvestorStruct) (*InvestorStruct, error) {
+
+	if !IsValidStatus(investor.Status) {
+		return nil, fmt.Errorf("unknown kyc status : %s", investor.Status)
+	}
+
+	if !IsValidCategory(investor.Category) {
+		return nil, fmt.Errorf("unknown investor category : %s", investor.Category)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+	investor.UpdatedAt = now
+
+	investorKey, err := ctx.GetStub().CreateCompositeKey(DocType_Investor, []string{investor.Address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Investor, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(investorKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		investorToMap, err := ccutils.StructToMap(investor)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_Investor, investorKey, investorToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_Investor, investorKey, investor, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &investor, nil
+}
+
+// GetInvestor 등록되지 않았으면 nil
+func GetInvestor(ctx contractapi.TransactionContextInterface, address string) (*InvestorStruct, error) {
+
+	investorKey, err := ctx.GetStub().CreateCompositeKey(DocType_Investor, []string{address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Investor, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(investorKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	investorBytes, err := ledgermanager.GetState(DocType_Investor, investorKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	investor := InvestorStruct{}
+	if err := json.Unmarshal(investorBytes, &investor); err != nil {
+		return nil, err
+	}
+
+	return &investor, nil
+}
+
+// MigrateInvestor 복구된 wallet 이 같은 투자자의 KYC 정보를 이어받도록 복사. 새 주소에 이미 정보가 있으면 유지
+func MigrateInvestor(ctx contractapi.TransactionContextInterface, oldAddress string, newAddress string, updatedBy string) error {
+
+	investor, err := GetInvestor(ctx, oldAddress)
+	if err != nil {
+		return err
+	}
+
+	if investor == nil {
+		return nil
+	}
+
+	existing, err := GetInvestor(ctx, newAddress)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return nil
+	}
+
+	investor.Address = newAddress
+	investor.UpdatedBy = updatedBy
+
+	_, err = SetInvestor(ctx, *investor)
+	return err
+}
+
+func SetPartitionKyc(ctx contractapi.TransactionContextInterface, partitionKyc PartitionKycStruct) (*PartitionKycStruct, error) {
+
+	if partitionKyc.MinLevel < 0 {
+		return nil, fmt.Errorf("min level cannot be negative")
+	}
+
+	configKey, err := ctx.GetStub().CreateCompositeKey(DocType_PartitionKyc, []string{partitionKyc.Partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PartitionKyc, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(configKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		configToMap, err := ccutils.StructToMap(partitionKyc)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_PartitionKyc, configKey, configToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_PartitionKyc, configKey, partitionKyc, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &partitionKyc, nil
+}
+
+// GetPartitionKyc 설정이 없으면 allowlist 를 요구하지 않음
+func GetPartitionKyc(ctx contractapi.TransactionContextInterface, partition string) (*PartitionKycStruct, error) {
+
+	configKey, err := ctx.GetStub().CreateCompositeKey(DocType_PartitionKyc, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PartitionKyc, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(configKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &PartitionKycStruct{DocType: DocType_PartitionKyc, Partition: partition}, nil
+	}
+
+	configBytes, err := ledgermanager.GetState(DocType_PartitionKyc, configKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	partitionKyc := PartitionKycStruct{}
+	if err := json.Unmarshal(configBytes, &partitionKyc); err != nil {
+		return nil, err
+	}
+
+	return &partitionKyc, nil
+}
+
+// CheckRecipient partition 이 allowlist 를 요구하면 수령인이 현재 유효한 KYC 를 가지고 있는지 확인
+func CheckRecipient(ctx contractapi.TransactionContextInterface, address string, partition string) error {
+
+	partitionKyc, err := GetPartitionKyc(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	if !partitionKyc.AllowlistRequired {
+		return nil
+	}
+
+	investor, err := GetInvestor(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if investor == nil || investor.Status != StatusVerified {
+		return ccutils.CreateError(CodeErrorNotVerified, fmt.Errorf(ErrorCodeMessage[CodeErrorNotVerified]+" : "+address))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if investor.ExpiresAt != 0 && now >= investor.ExpiresAt {
+		return ccutils.CreateError(CodeErrorExpired, fmt.Errorf(ErrorCodeMessage[CodeErrorExpired]+" : "+address))
+	}
+
+	if investor.Level < partitionKyc.MinLevel {
+		return ccutils.CreateError(CodeErrorLevelTooLow, fmt.Errorf(ErrorCodeMessage[CodeErrorLevelTooLow]+" : %s, level %d, required %d", address, investor.Level, partitionKyc.MinLevel))
+	}
+
+	return nil
+}
+
+func GetInvestorList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Investor)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldStatus, FieldCountryCode, FieldCategory}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
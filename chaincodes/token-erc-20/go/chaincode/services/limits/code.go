@@ -0,0 +1,9 @@
+package limits
+
+const CodeErrorHoldingLimitExceeded int = 740
+const CodeErrorAnnualLimitExceeded int = 741
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorHoldingLimitExceeded: "Limit error : holding limit for the investor category exceeded",
+	CodeErrorAnnualLimitExceeded:  "Limit error : annual acquisition limit for the investor category exceeded",
+}
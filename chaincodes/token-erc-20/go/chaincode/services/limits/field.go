@@ -0,0 +1,11 @@
+package limits
+
+const (
+	FieldPartition    string = "partition"
+	FieldCategory     string = "category"
+	FieldHoldingLimit string = "holdingLimit"
+	FieldAnnualLimit  string = "annualLimit"
+	FieldHolder       string = "holder"
+
+	FieldAcquiredInYear string = "acquiredInYear"
+)
@@ -0,0 +1,53 @@
+package limits
+
+const (
+	DocType_PartitionLimits = "DOCTYPE_PARTITIONLIMITS"
+	DocType_Acquisition     = "DOCTYPE_ACQUISITION"
+
+	// 연간 한도는 최근 365일 누적 취득량 기준
+	RollingYearSeconds int64 = 365 * 24 * 60 * 60
+)
+
+// 0 이면 무제한
+type CategoryLimitStruct struct {
+	HoldingLimit int64 `json:"holdingLimit"`
+	AnnualLimit  int64 `json:"annualLimit"`
+}
+
+// partition 별 투자자 유형(kyc category) 한도. 설정이 없는 유형은 무제한
+type PartitionLimitsStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string                         `json:"partition"`
+	Limits    map[string]CategoryLimitStruct `json:"limits"`
+
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+type AcquisitionStruct struct {
+	At     int64 `json:"at"`
+	Amount int64 `json:"amount"`
+}
+
+// (holder, partition) 취득 이력. 1년이 지난 이력은 기록 시 제거.
+// 수량은 ScaleEpoch 기준이며 읽을 때 현재 분할 기준으로 환산
+type AcquisitionRecordStruct struct {
+	DocType string `json:"docType"`
+
+	Holder       string              `json:"holder"`
+	Partition    string              `json:"partition"`
+	Acquisitions []AcquisitionStruct `json:"acquisitions"`
+	ScaleEpoch   int64               `json:"scaleEpoch"`
+}
+
+// AcquiredSince since 이후 취득 합계
+func (r AcquisitionRecordStruct) AcquiredSince(since int64) int64 {
+	var total int64
+	for _, acquisition := range r.Acquisitions {
+		if acquisition.At > since {
+			total += acquisition.Amount
+		}
+	}
+	return total
+}
@@ -0,0 +1,324 @@
+package limits
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/kyc"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/split"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+)
+
+// SetCategoryLimit partition 의 한 투자자 유형 한도 설정
+func SetCategoryLimit(ctx contractapi.TransactionContextInterface, partition string, category string, limit CategoryLimitStruct, updatedBy string) (*PartitionLimitsStruct, error) {
+
+	if !kyc.IsValidCategory(category) {
+		return nil, fmt.Errorf("unknown investor category : %s", category)
+	}
+
+	if limit.HoldingLimit < 0 || limit.AnnualLimit < 0 {
+		return nil, fmt.Errorf("limit cannot be negative")
+	}
+
+	partitionLimits, exist, err := getLimits(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	partitionLimits.Limits[category] = limit
+	partitionLimits.UpdatedBy = updatedBy
+	partitionLimits.UpdatedAt = now
+
+	limitsKey, err := ctx.GetStub().CreateCompositeKey(DocType_PartitionLimits, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PartitionLimits, err)
+	}
+
+	if exist {
+		limitsToMap, err := ccutils.StructToMap(partitionLimits)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_PartitionLimits, limitsKey, limitsToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_PartitionLimits, limitsKey, *partitionLimits, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return partitionLimits, nil
+}
+
+func GetLimits(ctx contractapi.TransactionContextInterface, partition string) (*PartitionLimitsStruct, error) {
+	partitionLimits, _, err := getLimits(ctx, partition)
+	return partitionLimits, err
+}
+
+// HasLimits partition 에 0 이 아닌 한도가 설정되어 있는지. 한도는 분할 시 환산하지 않음
+func HasLimits(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	partitionLimits, err := GetLimits(ctx, partition)
+	if err != nil {
+		return false, err
+	}
+
+	for _, limit := range partitionLimits.Limits {
+		if limit.HoldingLimit > 0 || limit.AnnualLimit > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetAcquisitionRecord 이력이 없으면 빈 기록
+func GetAcquisitionRecord(ctx contractapi.TransactionContextInterface, holder string, partition string) (*AcquisitionRecordStruct, error) {
+	record, _, err := getAcquisitionRecord(ctx, holder, partition)
+	return record, err
+}
+
+// CheckAcquisition holder 가 partition 토큰을 amount 만큼 더 취득해도 되는지 확인.
+// KYC 등록이 없는 주소는 유형을 알 수 없으므로 한도를 적용하지 않음
+func CheckAcquisition(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64) error {
+
+	investor, err := kyc.GetInvestor(ctx, holder)
+	if err != nil {
+		return err
+	}
+
+	if investor == nil {
+		return nil
+	}
+
+	partitionLimits, err := GetLimits(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	limit, exist := partitionLimits.Limits[investor.Category]
+	if !exist {
+		return nil
+	}
+
+	if limit.HoldingLimit > 0 {
+		balance, err := token.BalanceOfByPartitionOrZero(ctx, holder, partition)
+		if err != nil {
+			return err
+		}
+
+		if balance+amount > limit.HoldingLimit {
+			return ccutils.CreateError(CodeErrorHoldingLimitExceeded, fmt.Errorf(ErrorCodeMessage[CodeErrorHoldingLimitExceeded]+" : %s, balance %d, amount %d, limit %d", investor.Category, balance, amount, limit.HoldingLimit))
+		}
+	}
+
+	if limit.AnnualLimit > 0 {
+		record, err := GetAcquisitionRecord(ctx, holder, partition)
+		if err != nil {
+			return err
+		}
+
+		now, err := ccutils.GetTxTimestamp(ctx)
+		if err != nil {
+			return err
+		}
+
+		acquired := record.AcquiredSince(now - RollingYearSeconds)
+		if acquired+amount > limit.AnnualLimit {
+			return ccutils.CreateError(CodeErrorAnnualLimitExceeded, fmt.Errorf(ErrorCodeMessage[CodeErrorAnnualLimitExceeded]+" : %s, acquired %d, amount %d, limit %d", investor.Category, acquired, amount, limit.AnnualLimit))
+		}
+	}
+
+	return nil
+}
+
+// RecordAcquisition 취득 이력 추가. 한 트랜잭션에서 (holder, partition) 당 한 번만 호출
+func RecordAcquisition(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64) error {
+
+	if amount <= 0 {
+		return nil
+	}
+
+	record, exist, err := getAcquisitionRecord(ctx, holder, partition)
+	if err != nil {
+		return err
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	acquisitions := []AcquisitionStruct{}
+	for _, acquisition := range record.Acquisitions {
+		if acquisition.At > now-RollingYearSeconds {
+			acquisitions = append(acquisitions, acquisition)
+		}
+	}
+	record.Acquisitions = append(acquisitions, AcquisitionStruct{At: now, Amount: amount})
+
+	recordKey, err := ctx.GetStub().CreateCompositeKey(DocType_Acquisition, []string{holder, partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Acquisition, err)
+	}
+
+	if exist {
+		recordToMap, err := ccutils.StructToMap(record)
+		if err != nil {
+			return err
+		}
+
+		return ledgermanager.UpdateState(DocType_Acquisition, recordKey, recordToMap, ctx)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Acquisition, recordKey, *record, ctx)
+	return err
+}
+
+// MigrateAcquisition 키 분실 복구 시 old holder 의 취득 이력을 new holder 이력에 합침 (복구로 한도가 초기화되지 않도록)
+func MigrateAcquisition(ctx contractapi.TransactionContextInterface, partition string, oldHolder string, newHolder string) error {
+
+	oldRecord, oldExist, err := getAcquisitionRecord(ctx, oldHolder, partition)
+	if err != nil {
+		return err
+	}
+
+	if !oldExist {
+		return nil
+	}
+
+	newRecord, newExist, err := getAcquisitionRecord(ctx, newHolder, partition)
+	if err != nil {
+		return err
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 두 이력 모두 현재 분할 기준으로 환산된 상태
+	acquisitions := []AcquisitionStruct{}
+	for _, acquisition := range append(newRecord.Acquisitions, oldRecord.Acquisitions...) {
+		if acquisition.At > now-RollingYearSeconds {
+			acquisitions = append(acquisitions, acquisition)
+		}
+	}
+	sort.SliceStable(acquisitions, func(i, j int) bool { return acquisitions[i].At < acquisitions[j].At })
+	newRecord.Acquisitions = acquisitions
+
+	newKey, err := ctx.GetStub().CreateCompositeKey(DocType_Acquisition, []string{newHolder, partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Acquisition, err)
+	}
+
+	if newExist {
+		recordToMap, err := ccutils.StructToMap(newRecord)
+		if err != nil {
+			return err
+		}
+
+		err = ledgermanager.UpdateState(DocType_Acquisition, newKey, recordToMap, ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_Acquisition, newKey, *newRecord, ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	oldKey, err := ctx.GetStub().CreateCompositeKey(DocType_Acquisition, []string{oldHolder, partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Acquisition, err)
+	}
+
+	return ledgermanager.DeleteState(DocType_Acquisition, oldKey, ctx)
+}
+
+func getLimits(ctx contractapi.TransactionContextInterface, partition string) (*PartitionLimitsStruct, bool, error) {
+
+	limitsKey, err := ctx.GetStub().CreateCompositeKey(DocType_PartitionLimits, []string{partition})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PartitionLimits, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(limitsKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !exist {
+		return &PartitionLimitsStruct{DocType: DocType_PartitionLimits, Partition: partition, Limits: map[string]CategoryLimitStruct{}}, false, nil
+	}
+
+	limitsBytes, err := ledgermanager.GetState(DocType_PartitionLimits, limitsKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	partitionLimits := PartitionLimitsStruct{}
+	if err := json.Unmarshal(limitsBytes, &partitionLimits); err != nil {
+		return nil, false, err
+	}
+
+	if partitionLimits.Limits == nil {
+		partitionLimits.Limits = map[string]CategoryLimitStruct{}
+	}
+
+	return &partitionLimits, true, nil
+}
+
+func getAcquisitionRecord(ctx contractapi.TransactionContextInterface, holder string, partition string) (*AcquisitionRecordStruct, bool, error) {
+
+	recordKey, err := ctx.GetStub().CreateCompositeKey(DocType_Acquisition, []string{holder, partition})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Acquisition, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(recordKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	scale, err := split.GetScale(ctx, partition)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !exist {
+		return &AcquisitionRecordStruct{DocType: DocType_Acquisition, Holder: holder, Partition: partition, Acquisitions: []AcquisitionStruct{}, ScaleEpoch: scale.Epoch}, false, nil
+	}
+
+	recordBytes, err := ledgermanager.GetState(DocType_Acquisition, recordKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	record := AcquisitionRecordStruct{}
+	if err := json.Unmarshal(recordBytes, &record); err != nil {
+		return nil, false, err
+	}
+
+	for i := range record.Acquisitions {
+		record.Acquisitions[i].Amount, _ = scale.Scale(record.Acquisitions[i].Amount, record.ScaleEpoch, scale.Epoch)
+	}
+	record.ScaleEpoch = scale.Epoch
+
+	return &record, true, nil
+}
@@ -0,0 +1,15 @@
+package multisig
+
+const CodeErrorProposalNotPending int = 620
+const CodeErrorProposalExpired int = 621
+const CodeErrorAlreadyApproved int = 622
+const CodeErrorDuplicateMSP int = 623
+const CodeErrorProposerApproval int = 624
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorProposalNotPending: "Proposal error : proposal is not pending",
+	CodeErrorProposalExpired:    "Proposal error : proposal is expired",
+	CodeErrorAlreadyApproved:    "Proposal error : approver already approved",
+	CodeErrorDuplicateMSP:       "Proposal error : an approver from the same MSP already approved",
+	CodeErrorProposerApproval:   "Proposal error : proposer cannot approve own proposal",
+}
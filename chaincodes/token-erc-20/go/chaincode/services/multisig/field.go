@@ -0,0 +1,13 @@
+package multisig
+
+const (
+	FieldActionType    string = "actionType"
+	FieldThreshold     string = "threshold"
+	FieldApproverRoles string = "approverRoles"
+	FieldDistinctMSP   string = "distinctMSP"
+	FieldExpirySeconds string = "expirySeconds"
+
+	FieldProposalId string = "proposalId"
+	FieldStatus     string = "status"
+	FieldProposer   string = "proposer"
+)
@@ -0,0 +1,63 @@
+package multisig
+
+const (
+	DocType_ApprovalPolicy = "DOCTYPE_APPROVALPOLICY"
+	DocType_Proposal       = "DOCTYPE_PROPOSAL"
+
+	// 기본 만료 시간 (7일)
+	DefaultExpirySeconds int64 = 7 * 24 * 60 * 60
+)
+
+// Proposal status
+const (
+	StatusPending   = "pending"
+	StatusExecuted  = "executed"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+// action type 별 M-of-N 승인 정책
+type ApprovalPolicyStruct struct {
+	DocType string `json:"docType"`
+
+	ActionType string `json:"actionType"`
+	// 실행에 필요한 승인 수 (M)
+	Threshold int64 `json:"threshold"`
+	// 승인 가능한 role 목록 (N)
+	ApproverRoles []string `json:"approverRoles"`
+	// true 면 MSP 당 한 명만 승인 가능
+	DistinctMSP   bool  `json:"distinctMSP"`
+	ExpirySeconds int64 `json:"expirySeconds"`
+}
+
+type ProposalStruct struct {
+	DocType string `json:"docType"`
+
+	ProposalId  string `json:"proposalId"`
+	ActionType  string `json:"actionType"`
+	Proposer    string `json:"proposer"`
+	ProposerMSP string `json:"proposerMSP"`
+
+	// 실행 시 사용할 원래 호출 파라메터
+	Args map[string]interface{} `json:"args"`
+
+	// 생성 시점의 정책
+	Threshold     int64    `json:"threshold"`
+	ApproverRoles []string `json:"approverRoles"`
+	DistinctMSP   bool     `json:"distinctMSP"`
+
+	Status    string           `json:"status"`
+	Approvals []ApprovalStruct `json:"approvals"`
+
+	CreatedAt int64  `json:"createdAt"`
+	ExpiresAt int64  `json:"expiresAt"`
+	ClosedAt  int64  `json:"closedAt"`
+	ClosedBy  string `json:"closedBy"`
+}
+
+type ApprovalStruct struct {
+	Approver    string `json:"approver"`
+	ApproverMSP string `json:"approverMSP"`
+	TxId        string `json:"txId"`
+	Timestamp   int64  `json:"timestamp"`
+}
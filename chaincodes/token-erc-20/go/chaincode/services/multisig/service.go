@@ -0,0 +1,270 @@
+package multisig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+func SetApprovalPolicy(ctx contractapi.TransactionContextInterface, policy ApprovalPolicyStruct) (*ApprovalPolicyStruct, error) {
+
+	if policy.Threshold < 0 {
+		return nil, fmt.Errorf("threshold cannot be negative")
+	}
+
+	if policy.Threshold > 0 && len(policy.ApproverRoles) == 0 {
+		return nil, fmt.Errorf("approver roles must not be empty")
+	}
+
+	if policy.ApproverRoles == nil {
+		policy.ApproverRoles = []string{}
+	}
+
+	if policy.ExpirySeconds <= 0 {
+		policy.ExpirySeconds = DefaultExpirySeconds
+	}
+
+	policyKey, err := ctx.GetStub().CreateCompositeKey(DocType_ApprovalPolicy, []string{policy.ActionType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_ApprovalPolicy, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(policyKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		policyToMap, err := ccutils.StructToMap(policy)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_ApprovalPolicy, policyKey, policyToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_ApprovalPolicy, policyKey, policy, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &policy, nil
+}
+
+// GetApprovalPolicy 정책이 없으면 nil
+func GetApprovalPolicy(ctx contractapi.TransactionContextInterface, actionType string) (*ApprovalPolicyStruct, error) {
+
+	policyKey, err := ctx.GetStub().CreateCompositeKey(DocType_ApprovalPolicy, []string{actionType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_ApprovalPolicy, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(policyKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	policyBytes, err := ledgermanager.GetState(DocType_ApprovalPolicy, policyKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := ApprovalPolicyStruct{}
+	if err := json.Unmarshal(policyBytes, &policy); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// IsApprovalRequired 해당 action 이 단독 실행 불가(M-of-N 승인 필요)인지 확인
+func IsApprovalRequired(policy *ApprovalPolicyStruct) bool {
+	return policy != nil && policy.Threshold > 0
+}
+
+func CreateProposal(ctx contractapi.TransactionContextInterface, policy ApprovalPolicyStruct, proposer string, proposerMSP string, args map[string]interface{}) (*ProposalStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := ProposalStruct{}
+	proposal.ProposalId = ctx.GetStub().GetTxID()
+	proposal.ActionType = policy.ActionType
+	proposal.Proposer = proposer
+	proposal.ProposerMSP = proposerMSP
+	proposal.Args = args
+	proposal.Threshold = policy.Threshold
+	proposal.ApproverRoles = policy.ApproverRoles
+	proposal.DistinctMSP = policy.DistinctMSP
+	proposal.Status = StatusPending
+	proposal.Approvals = []ApprovalStruct{}
+	proposal.CreatedAt = now
+	proposal.ExpiresAt = now + policy.ExpirySeconds
+
+	proposalKey, err := ctx.GetStub().CreateCompositeKey(DocType_Proposal, []string{proposal.ProposalId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Proposal, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Proposal, proposalKey, proposal, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proposal, nil
+}
+
+// GetProposal 만료 시간이 지난 pending proposal 은 expired 로 보여줌
+func GetProposal(ctx contractapi.TransactionContextInterface, proposalId string) (*ProposalStruct, error) {
+
+	proposalKey, err := ctx.GetStub().CreateCompositeKey(DocType_Proposal, []string{proposalId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Proposal, err)
+	}
+
+	proposalBytes, err := ledgermanager.GetState(DocType_Proposal, proposalKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := ProposalStruct{}
+	if err := json.Unmarshal(proposalBytes, &proposal); err != nil {
+		return nil, err
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if proposal.Status == StatusPending && now > proposal.ExpiresAt {
+		proposal.Status = StatusExpired
+	}
+
+	return &proposal, nil
+}
+
+// ApproveProposal 승인 추가. threshold 에 도달하면 true
+func ApproveProposal(ctx contractapi.TransactionContextInterface, proposalId string, approver string, approverMSP string) (*ProposalStruct, bool, error) {
+
+	proposal, err := GetProposal(ctx, proposalId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if proposal.Status == StatusExpired {
+		return nil, false, ccutils.CreateError(CodeErrorProposalExpired, fmt.Errorf(ErrorCodeMessage[CodeErrorProposalExpired]+" : "+proposalId))
+	}
+
+	if proposal.Status != StatusPending {
+		return nil, false, ccutils.CreateError(CodeErrorProposalNotPending, fmt.Errorf(ErrorCodeMessage[CodeErrorProposalNotPending]+" : "+proposalId))
+	}
+
+	// proposer 는 승인 수에 포함하지 않음
+	if proposal.Proposer == approver {
+		return nil, false, ccutils.CreateError(CodeErrorProposerApproval, fmt.Errorf(ErrorCodeMessage[CodeErrorProposerApproval]+" : "+approver))
+	}
+
+	for _, approval := range proposal.Approvals {
+		if approval.Approver == approver {
+			return nil, false, ccutils.CreateError(CodeErrorAlreadyApproved, fmt.Errorf(ErrorCodeMessage[CodeErrorAlreadyApproved]+" : "+approver))
+		}
+
+		if proposal.DistinctMSP && approval.ApproverMSP == approverMSP {
+			return nil, false, ccutils.CreateError(CodeErrorDuplicateMSP, fmt.Errorf(ErrorCodeMessage[CodeErrorDuplicateMSP]+" : "+approverMSP))
+		}
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	proposal.Approvals = append(proposal.Approvals, ApprovalStruct{Approver: approver, ApproverMSP: approverMSP, TxId: ctx.GetStub().GetTxID(), Timestamp: now})
+
+	err = updateProposal(ctx, *proposal)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return proposal, int64(len(proposal.Approvals)) >= proposal.Threshold, nil
+}
+
+// CloseProposal 만료된 proposal 도 취소(기록 정리)는 가능
+func CloseProposal(ctx contractapi.TransactionContextInterface, proposal ProposalStruct, status string, closedBy string) (*ProposalStruct, error) {
+
+	if proposal.Status != StatusPending && !(proposal.Status == StatusExpired && status == StatusCancelled) {
+		return nil, ccutils.CreateError(CodeErrorProposalNotPending, fmt.Errorf(ErrorCodeMessage[CodeErrorProposalNotPending]+" : "+proposal.ProposalId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal.Status = status
+	proposal.ClosedAt = now
+	proposal.ClosedBy = closedBy
+
+	err = updateProposal(ctx, proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proposal, nil
+}
+
+func GetProposalList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Proposal)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldActionType, FieldStatus, FieldProposer}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func updateProposal(ctx contractapi.TransactionContextInterface, proposal ProposalStruct) error {
+
+	proposalKey, err := ctx.GetStub().CreateCompositeKey(DocType_Proposal, []string{proposal.ProposalId})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Proposal, err)
+	}
+
+	proposalToMap, err := ccutils.StructToMap(proposal)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_Proposal, proposalKey, proposalToMap, ctx)
+}
@@ -0,0 +1,19 @@
+package offering
+
+const CodeErrorOfferingNotOpen int = 810
+const CodeErrorOfferingNotActive int = 811
+const CodeErrorHardCapExceeded int = 812
+const CodeErrorSubscriptionLimit int = 813
+const CodeErrorOfferingNotClosable int = 814
+const CodeErrorProceedsUnavailable int = 815
+const CodeErrorOfferingInProgress int = 816
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorOfferingNotOpen:     "Offering error : subscription window is not open",
+	CodeErrorOfferingNotActive:   "Offering error : offering is already closed",
+	CodeErrorHardCapExceeded:     "Offering error : hard cap exceeded",
+	CodeErrorSubscriptionLimit:   "Offering error : subscription is out of the per-investor limit",
+	CodeErrorOfferingNotClosable: "Offering error : subscription window has not ended and hard cap is not reached",
+	CodeErrorProceedsUnavailable: "Offering error : no proceeds to withdraw",
+	CodeErrorOfferingInProgress:  "Offering error : another offering is open for the partition",
+}
@@ -0,0 +1,18 @@
+package offering
+
+const (
+	FieldOfferingId        string = "offeringId"
+	FieldPartition         string = "partition"
+	FieldCashPartition     string = "cashPartition"
+	FieldPrice             string = "price"
+	FieldOpenAt            string = "openAt"
+	FieldCloseAt           string = "closeAt"
+	FieldSoftCap           string = "softCap"
+	FieldHardCap           string = "hardCap"
+	FieldMinSubscription   string = "minSubscription"
+	FieldMaxSubscription   string = "maxSubscription"
+	FieldAmount            string = "amount"
+	FieldInvestor          string = "investor"
+	FieldProceedsWithdrawn string = "proceedsWithdrawn"
+	FieldStatus            string = "status"
+)
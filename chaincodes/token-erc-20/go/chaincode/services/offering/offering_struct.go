@@ -0,0 +1,94 @@
+package offering
+
+const (
+	DocType_Offering     = "DOCTYPE_OFFERING"
+	DocType_Subscription = "DOCTYPE_OFFERINGSUBSCRIPTION"
+	// partition 별 진행 중인 공모. 한 partition 에 동시에 하나만 open
+	DocType_OpenOffering = "DOCTYPE_OPENOFFERING"
+)
+
+const (
+	// 공모 수량(security partition)을 보관하는 escrow wallet 이름 (wallet.EscrowAddress)
+	TokenEscrowName = "OFFERING"
+	// 청약 대금(cash partition)을 보관하는 escrow wallet 이름
+	CashEscrowName = "OFFERING_CASH"
+)
+
+// Offering status
+const (
+	StatusOpen      = "open"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Subscription status
+const (
+	SubscriptionSubscribed = "subscribed"
+	SubscriptionAllocated  = "allocated"
+	SubscriptionRefunded   = "refunded"
+)
+
+// partition 공모. 금액은 모두 cash partition 단위, Price 는 토큰 1 개당 cash
+type OfferingStruct struct {
+	DocType string `json:"docType"`
+
+	OfferingId    string `json:"offeringId"`
+	Partition     string `json:"partition"`
+	CashPartition string `json:"cashPartition"`
+	Issuer        string `json:"issuer"`
+	Price         int64  `json:"price"`
+
+	// unix seconds. OpenAt <= now < CloseAt 동안 청약 가능
+	OpenAt  int64 `json:"openAt"`
+	CloseAt int64 `json:"closeAt"`
+
+	// SoftCap 미달이면 전액 환불. HardCap / Price 만큼 토큰을 생성 시 escrow
+	SoftCap         int64 `json:"softCap"`
+	HardCap         int64 `json:"hardCap"`
+	MinSubscription int64 `json:"minSubscription"`
+	// 0 이면 HardCap 까지
+	MaxSubscription int64 `json:"maxSubscription"`
+	TokensOffered   int64 `json:"tokensOffered"`
+
+	Raised          int64 `json:"raised"`
+	SubscriberCount int64 `json:"subscriberCount"`
+
+	// close 결과
+	Status            string `json:"status"`
+	AllocatedAmount   int64  `json:"allocatedAmount"`
+	AllocatedTokens   int64  `json:"allocatedTokens"`
+	RefundedAmount    int64  `json:"refundedAmount"`
+	UnsoldTokens      int64  `json:"unsoldTokens"`
+	ProceedsWithdrawn bool   `json:"proceedsWithdrawn"`
+
+	CreatedBy string `json:"createdBy"`
+	CreatedAt int64  `json:"createdAt"`
+	ClosedBy  string `json:"closedBy"`
+	ClosedAt  int64  `json:"closedAt"`
+}
+
+type OpenOfferingStruct struct {
+	DocType string `json:"docType"`
+
+	Partition  string `json:"partition"`
+	OfferingId string `json:"offeringId"`
+}
+
+// 투자자별 청약. 추가 청약은 Amount 에 합산
+type SubscriptionStruct struct {
+	DocType string `json:"docType"`
+
+	OfferingId string `json:"offeringId"`
+	Investor   string `json:"investor"`
+	Amount     int64  `json:"amount"`
+	Tokens     int64  `json:"tokens"`
+
+	Status string `json:"status"`
+	// 배정/환불 수령 wallet (키 분실 복구 시 새 wallet), 환불 사유
+	Recipient string `json:"recipient"`
+	Reason    string `json:"reason"`
+
+	SubscribedAt int64 `json:"subscribedAt"`
+	SettledAt    int64 `json:"settledAt"`
+}
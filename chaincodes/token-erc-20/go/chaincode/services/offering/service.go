@@ -0,0 +1,433 @@
+package offering
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// CreateOffering 공모 기록 생성. 공모 수량의 escrow 이전은 controller 에서 처리
+func CreateOffering(ctx contractapi.TransactionContextInterface, offering OfferingStruct) (*OfferingStruct, error) {
+
+	if offering.Partition == offering.CashPartition {
+		return nil, fmt.Errorf("cash partition must be different from the partition : %s", offering.Partition)
+	}
+
+	if offering.Price <= 0 {
+		return nil, fmt.Errorf("invalid price : %d", offering.Price)
+	}
+
+	if offering.SoftCap <= 0 || offering.HardCap < offering.SoftCap {
+		return nil, fmt.Errorf("invalid cap : soft cap %d, hard cap %d", offering.SoftCap, offering.HardCap)
+	}
+
+	if offering.HardCap%offering.Price != 0 {
+		return nil, fmt.Errorf("hard cap must be a multiple of the price : hard cap %d, price %d", offering.HardCap, offering.Price)
+	}
+
+	if offering.MinSubscription < 0 || offering.MaxSubscription < 0 {
+		return nil, fmt.Errorf("invalid subscription limit : min %d, max %d", offering.MinSubscription, offering.MaxSubscription)
+	}
+
+	if offering.MaxSubscription != 0 && offering.MaxSubscription < offering.MinSubscription {
+		return nil, fmt.Errorf("max subscription must not be less than min subscription : min %d, max %d", offering.MinSubscription, offering.MaxSubscription)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if offering.OpenAt >= offering.CloseAt || offering.CloseAt <= now {
+		return nil, fmt.Errorf("invalid subscription window : openAt %d, closeAt %d", offering.OpenAt, offering.CloseAt)
+	}
+
+	openKey, err := ctx.GetStub().CreateCompositeKey(DocType_OpenOffering, []string{offering.Partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_OpenOffering, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(openKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		return nil, ccutils.CreateError(CodeErrorOfferingInProgress, fmt.Errorf(ErrorCodeMessage[CodeErrorOfferingInProgress]+" : "+offering.Partition))
+	}
+
+	offering.OfferingId = ctx.GetStub().GetTxID()
+	offering.TokensOffered = offering.HardCap / offering.Price
+	offering.Status = StatusOpen
+	offering.CreatedAt = now
+
+	offeringKey, err := ctx.GetStub().CreateCompositeKey(DocType_Offering, []string{offering.OfferingId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Offering, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Offering, offeringKey, offering, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	openOffering := OpenOfferingStruct{Partition: offering.Partition, OfferingId: offering.OfferingId}
+	_, err = ledgermanager.PutState(DocType_OpenOffering, openKey, openOffering, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &offering, nil
+}
+
+func GetOffering(ctx contractapi.TransactionContextInterface, offeringId string) (*OfferingStruct, error) {
+
+	offeringKey, err := ctx.GetStub().CreateCompositeKey(DocType_Offering, []string{offeringId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Offering, err)
+	}
+
+	offeringBytes, err := ledgermanager.GetState(DocType_Offering, offeringKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	offering := OfferingStruct{}
+	if err := json.Unmarshal(offeringBytes, &offering); err != nil {
+		return nil, err
+	}
+
+	return &offering, nil
+}
+
+// GetSubscription 청약 기록이 없으면 nil
+func GetSubscription(ctx contractapi.TransactionContextInterface, offeringId string, investor string) (*SubscriptionStruct, error) {
+
+	subscriptionKey, err := ctx.GetStub().CreateCompositeKey(DocType_Subscription, []string{offeringId, investor})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Subscription, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(subscriptionKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	subscriptionBytes, err := ledgermanager.GetState(DocType_Subscription, subscriptionKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := SubscriptionStruct{}
+	if err := json.Unmarshal(subscriptionBytes, &subscription); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// GetSubscriptions offering 의 전체 청약. close 정산용
+func GetSubscriptions(ctx contractapi.TransactionContextInterface, offeringId string) ([]SubscriptionStruct, error) {
+
+	subscriptionIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(DocType_Subscription, []string{offeringId})
+	if err != nil {
+		return nil, err
+	}
+	defer subscriptionIterator.Close()
+
+	subscriptions := []SubscriptionStruct{}
+	for subscriptionIterator.HasNext() {
+		queryResponse, err := subscriptionIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		subscription := SubscriptionStruct{}
+		if err := json.Unmarshal(queryResponse.Value, &subscription); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+// Subscribe 청약 한도 확인 후 청약 기록과 모집액 갱신. 대금 escrow 이전은 controller 에서 처리
+func Subscribe(ctx contractapi.TransactionContextInterface, offering OfferingStruct, investor string, amount int64) (*SubscriptionStruct, *OfferingStruct, error) {
+
+	if offering.Status != StatusOpen {
+		return nil, nil, ccutils.CreateError(CodeErrorOfferingNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorOfferingNotActive]+" : "+offering.OfferingId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if now < offering.OpenAt || now >= offering.CloseAt {
+		return nil, nil, ccutils.CreateError(CodeErrorOfferingNotOpen, fmt.Errorf(ErrorCodeMessage[CodeErrorOfferingNotOpen]+" : "+offering.OfferingId))
+	}
+
+	if amount <= 0 || amount%offering.Price != 0 {
+		return nil, nil, fmt.Errorf("amount must be a positive multiple of the price : amount %d, price %d", amount, offering.Price)
+	}
+
+	if offering.Raised+amount > offering.HardCap {
+		return nil, nil, ccutils.CreateError(CodeErrorHardCapExceeded, fmt.Errorf(ErrorCodeMessage[CodeErrorHardCapExceeded]+" : raised %d, requested %d, hard cap %d", offering.Raised, amount, offering.HardCap))
+	}
+
+	subscription, err := GetSubscription(ctx, offering.OfferingId, investor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exist := subscription != nil
+	if !exist {
+		subscription = &SubscriptionStruct{OfferingId: offering.OfferingId, Investor: investor, Status: SubscriptionSubscribed}
+		offering.SubscriberCount++
+	}
+
+	total := subscription.Amount + amount
+	if total < offering.MinSubscription || (offering.MaxSubscription != 0 && total > offering.MaxSubscription) {
+		return nil, nil, ccutils.CreateError(CodeErrorSubscriptionLimit, fmt.Errorf(ErrorCodeMessage[CodeErrorSubscriptionLimit]+" : subscribed %d, min %d, max %d", total, offering.MinSubscription, offering.MaxSubscription))
+	}
+
+	subscription.Amount = total
+	subscription.Tokens = total / offering.Price
+	subscription.SubscribedAt = now
+	offering.Raised += amount
+
+	subscriptionKey, err := ctx.GetStub().CreateCompositeKey(DocType_Subscription, []string{offering.OfferingId, investor})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Subscription, err)
+	}
+
+	if exist {
+		subscriptionToMap, err := ccutils.StructToMap(subscription)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_Subscription, subscriptionKey, subscriptionToMap, ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_Subscription, subscriptionKey, subscription, ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = putOffering(ctx, offering)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return subscription, &offering, nil
+}
+
+// CheckClosable 청약 기간이 끝났거나 hard cap 을 모두 채웠으면 누구나 close 가능
+func CheckClosable(ctx contractapi.TransactionContextInterface, offering OfferingStruct) error {
+
+	if offering.Status != StatusOpen {
+		return ccutils.CreateError(CodeErrorOfferingNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorOfferingNotActive]+" : "+offering.OfferingId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if now < offering.CloseAt && offering.Raised < offering.HardCap {
+		return ccutils.CreateError(CodeErrorOfferingNotClosable, fmt.Errorf(ErrorCodeMessage[CodeErrorOfferingNotClosable]+" : "+offering.OfferingId))
+	}
+
+	return nil
+}
+
+// SettleOffering close/cancel 결과 저장. subscriptions 는 배정/환불이 정해진 상태로 전달
+func SettleOffering(ctx contractapi.TransactionContextInterface, offering OfferingStruct, subscriptions []SubscriptionStruct, status string, closedBy string) (*OfferingStruct, error) {
+
+	if offering.Status != StatusOpen {
+		return nil, ccutils.CreateError(CodeErrorOfferingNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorOfferingNotActive]+" : "+offering.OfferingId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	offering.AllocatedAmount = 0
+	offering.AllocatedTokens = 0
+	offering.RefundedAmount = 0
+
+	for _, subscription := range subscriptions {
+		subscription.SettledAt = now
+
+		switch subscription.Status {
+		case SubscriptionAllocated:
+			offering.AllocatedAmount += subscription.Amount
+			offering.AllocatedTokens += subscription.Tokens
+		case SubscriptionRefunded:
+			offering.RefundedAmount += subscription.Amount
+		default:
+			return nil, fmt.Errorf("subscription of %s is not settled", subscription.Investor)
+		}
+
+		subscriptionKey, err := ctx.GetStub().CreateCompositeKey(DocType_Subscription, []string{subscription.OfferingId, subscription.Investor})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Subscription, err)
+		}
+
+		subscriptionToMap, err := ccutils.StructToMap(subscription)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_Subscription, subscriptionKey, subscriptionToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	openKey, err := ctx.GetStub().CreateCompositeKey(DocType_OpenOffering, []string{offering.Partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_OpenOffering, err)
+	}
+
+	err = ledgermanager.DeleteState(DocType_OpenOffering, openKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	offering.UnsoldTokens = offering.TokensOffered - offering.AllocatedTokens
+	offering.Status = status
+	offering.ClosedBy = closedBy
+	offering.ClosedAt = now
+
+	err = putOffering(ctx, offering)
+	if err != nil {
+		return nil, err
+	}
+
+	return &offering, nil
+}
+
+// WithdrawProceeds 성공한 공모의 배정 대금을 발행사가 한 번만 인출
+func WithdrawProceeds(ctx contractapi.TransactionContextInterface, offering OfferingStruct) (*OfferingStruct, error) {
+
+	if offering.Status != StatusSucceeded || offering.ProceedsWithdrawn || offering.AllocatedAmount == 0 {
+		return nil, ccutils.CreateError(CodeErrorProceedsUnavailable, fmt.Errorf(ErrorCodeMessage[CodeErrorProceedsUnavailable]+" : "+offering.OfferingId))
+	}
+
+	offering.ProceedsWithdrawn = true
+
+	err := putOffering(ctx, offering)
+	if err != nil {
+		return nil, err
+	}
+
+	return &offering, nil
+}
+
+// HasOpenOffering partition 을 토큰 또는 cash 로 쓰는 진행 중 공모나, 대금을 인출하지 않은 공모가 있는지
+func HasOpenOffering(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Offering)
+	queryBuilder.AddSelectorGroup(FieldStatus, StatusOpen)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldPartition, partition)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldCashPartition, partition)
+
+	exist, err := ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+	if err != nil || exist {
+		return exist, err
+	}
+
+	queryBuilder = ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Offering)
+	queryBuilder.AddSelectorGroup(FieldStatus, StatusSucceeded)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldPartition, partition)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldCashPartition, partition)
+	queryBuilder.AddSelectorGroup(FieldProceedsWithdrawn, false)
+
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetOfferingList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Offering)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldPartition, FieldCashPartition, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func GetSubscriptionList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Subscription)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldOfferingId, FieldInvestor, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func putOffering(ctx contractapi.TransactionContextInterface, offering OfferingStruct) error {
+
+	offeringKey, err := ctx.GetStub().CreateCompositeKey(DocType_Offering, []string{offering.OfferingId})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Offering, err)
+	}
+
+	offeringToMap, err := ccutils.StructToMap(offering)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_Offering, offeringKey, offeringToMap, ctx)
+}
@@ -98,3 +98,43 @@ func RevokeOperatorByPartition(ctx contractapi.TransactionContextInterface, oper
 
 	return nil
 }
+
+// MigrateOperator 모든 partition 에서 oldAddress 의 operator 권한을 newAddress 로 옮김
+func MigrateOperator(ctx contractapi.TransactionContextInterface, oldAddress string, newAddress string) error {
+
+	operatorBytes, err := ledgermanager.GetState(DocType_Operator, "Operator", ctx)
+	if err != nil {
+		return err
+	}
+
+	operatorStruct := OperatorsStruct{}
+	err = json.Unmarshal(operatorBytes, &operatorStruct)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for partition, operators := range operatorStruct.Operator {
+		grant, exist := operators[oldAddress]
+		if !exist {
+			continue
+		}
+
+		if grant.Role != "" || operators[newAddress].Role == "" {
+			operatorStruct.Operator[partition][newAddress] = grant
+		}
+		delete(operatorStruct.Operator[partition], oldAddress)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	operatorToMap, err := ccutils.StructToMap(operatorStruct)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_Operator, "Operator", operatorToMap, ctx)
+}
@@ -0,0 +1,13 @@
+package orderbook
+
+const CodeErrorInvalidSide int = 850
+const CodeErrorOrderNotOpen int = 851
+const CodeErrorNotTrader int = 852
+const CodeErrorOrderExpired int = 853
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorInvalidSide:  "order book error : side must be buy or sell",
+	CodeErrorOrderNotOpen: "order book error : order is already closed",
+	CodeErrorNotTrader:    "order book error : only the trader can cancel the order before expiration",
+	CodeErrorOrderExpired: "order book error : order is expired",
+}
@@ -0,0 +1,22 @@
+package orderbook
+
+const (
+	FieldOrderId       string = "orderId"
+	FieldSide          string = "side"
+	FieldPartition     string = "partition"
+	FieldCashPartition string = "cashPartition"
+	FieldPrice         string = "price"
+	FieldQuantity      string = "quantity"
+	FieldExpiration    string = "expiration"
+	FieldTrader        string = "trader"
+	FieldStatus        string = "status"
+	FieldMaxFills      string = "maxFills"
+	FieldBuyer         string = "buyer"
+	FieldSeller        string = "seller"
+	FieldBuyOrderId    string = "buyOrderId"
+	FieldSellOrderId   string = "sellOrderId"
+	FieldBids          string = "bids"
+	FieldAsks          string = "asks"
+	FieldFills         string = "fills"
+	FieldExpired       string = "expired"
+)
@@ -0,0 +1,132 @@
+package orderbook
+
+const (
+	DocType_Order     = "DOCTYPE_ORDER"
+	DocType_OpenOrder = "DOCTYPE_OPENORDER"
+	DocType_Fill      = "DOCTYPE_ORDERFILL"
+)
+
+const (
+	// 주문의 증권(매도)과 대금(매수)을 보관하는 escrow wallet 이름 (wallet.EscrowAddress).
+	// 체결은 wallet.SettleBatch 로 반영하므로 partition 별로 나누지 않음
+	EscrowName = "ORDERBOOK"
+)
+
+// Side
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// Order status
+const (
+	StatusOpen      = "open"
+	StatusFilled    = "filled"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+// 증권 partition 을 cash partition 가격으로 사고파는 지정가 주문.
+// 매도는 증권 Quantity, 매수는 대금 Price * Quantity 를 주문 시 escrow
+type OrderStruct struct {
+	DocType string `json:"docType"`
+
+	OrderId       string `json:"orderId"`
+	Side          string `json:"side"`
+	Trader        string `json:"trader"`
+	Partition     string `json:"partition"`
+	CashPartition string `json:"cashPartition"`
+
+	// 토큰 1 개당 CashPartition 수량
+	Price    int64 `json:"price"`
+	Quantity int64 `json:"quantity"`
+	Filled   int64 `json:"filled"`
+
+	// 남은 escrow. 매도는 증권, 매수는 대금 (가격 개선분은 체결 시 환불).
+	// 취소/만료 시 Refunded 로 옮기고 0 이 됨
+	Escrowed int64 `json:"escrowed"`
+	Refunded int64 `json:"refunded"`
+
+	// unix seconds, 0 이면 만료 없음
+	Expiration int64 `json:"expiration"`
+
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+	ClosedBy  string `json:"closedBy"`
+}
+
+func (o OrderStruct) Remaining() int64 {
+
+	return o.Quantity - o.Filled
+}
+
+func (o OrderStruct) IsExpired(now int64) bool {
+
+	return o.Expiration > 0 && now >= o.Expiration
+}
+
+// EscrowPartition 주문이 escrow 한 partition
+func (o OrderStruct) EscrowPartition() string {
+
+	if o.Side == SideSell {
+		return o.Partition
+	}
+	return o.CashPartition
+}
+
+// Before 가격-시간 우선순위. 매수는 높은 가격, 매도는 낮은 가격이 앞서고,
+// 같은 가격이면 먼저 들어온 주문 (같은 초면 orderId 순)
+func (o OrderStruct) Before(other OrderStruct) bool {
+
+	if o.Price != other.Price {
+		if o.Side == SideBuy {
+			return o.Price > other.Price
+		}
+		return o.Price < other.Price
+	}
+
+	if o.CreatedAt != other.CreatedAt {
+		return o.CreatedAt < other.CreatedAt
+	}
+
+	return o.OrderId < other.OrderId
+}
+
+// 체결 기록. 체결 가격은 먼저 들어와 있던 주문의 가격
+type FillStruct struct {
+	DocType string `json:"docType"`
+
+	FillId        string `json:"fillId"`
+	BuyOrderId    string `json:"buyOrderId"`
+	SellOrderId   string `json:"sellOrderId"`
+	Buyer         string `json:"buyer"`
+	Seller        string `json:"seller"`
+	Partition     string `json:"partition"`
+	CashPartition string `json:"cashPartition"`
+	Price         int64  `json:"price"`
+	Quantity      int64  `json:"quantity"`
+	CashAmount    int64  `json:"cashAmount"`
+	MatchedAt     int64  `json:"matchedAt"`
+}
+
+// 호가 단위 잔량
+type PriceLevelStruct struct {
+	Price    int64 `json:"price"`
+	Quantity int64 `json:"quantity"`
+	Orders   int64 `json:"orders"`
+}
+
+// 미체결 주문 색인. key 는 (partition, cashPartition, side, orderId)
+type OpenOrderStruct struct {
+	DocType string `json:"docType"`
+
+	OrderId string `json:"orderId"`
+}
+
+// MatchOrders 한 번의 결과. Orders 는 체결되거나 만료된 주문의 최종 상태
+type MatchResultStruct struct {
+	Fills   []FillStruct  `json:"fills"`
+	Orders  []OrderStruct `json:"orders"`
+	Expired []OrderStruct `json:"expired"`
+}
@@ -0,0 +1,460 @@
+package orderbook
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// FillCheck 체결 전 확인. 실패한 매수/매도 쌍은 건너뛰고 다음 상대 주문과 매칭
+type FillCheck func(buy OrderStruct, sell OrderStruct, quantity int64, cashAmount int64) error
+
+// PlaceOrder 주문 기록과 미체결 색인 생성. escrow 이전은 controller 에서 처리
+func PlaceOrder(ctx contractapi.TransactionContextInterface, order OrderStruct) (*OrderStruct, error) {
+
+	if order.Side != SideBuy && order.Side != SideSell {
+		return nil, ccutils.CreateError(CodeErrorInvalidSide, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidSide]+" : "+order.Side))
+	}
+
+	if order.Price <= 0 || order.Quantity <= 0 {
+		return nil, fmt.Errorf("invalid order : price %d, quantity %d", order.Price, order.Quantity)
+	}
+
+	if order.Quantity > math.MaxInt64/order.Price {
+		return nil, fmt.Errorf("order value overflows : price %d, quantity %d", order.Price, order.Quantity)
+	}
+
+	if order.Partition == order.CashPartition {
+		return nil, fmt.Errorf("cash partition must be different from the partition : %s", order.Partition)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Expiration != 0 && order.Expiration <= now {
+		return nil, fmt.Errorf("expiration must be in the future : %d", order.Expiration)
+	}
+
+	order.OrderId = ctx.GetStub().GetTxID()
+	order.Status = StatusOpen
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	order.Escrowed = order.Quantity
+	if order.Side == SideBuy {
+		order.Escrowed = order.Price * order.Quantity
+	}
+
+	orderKey, err := ctx.GetStub().CreateCompositeKey(DocType_Order, []string{order.OrderId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Order, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Order, orderKey, order, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	openKey, err := openOrderKey(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = ledgermanager.PutState(DocType_OpenOrder, openKey, OpenOrderStruct{OrderId: order.OrderId}, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func GetOrder(ctx contractapi.TransactionContextInterface, orderId string) (*OrderStruct, error) {
+
+	orderKey, err := ctx.GetStub().CreateCompositeKey(DocType_Order, []string{orderId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Order, err)
+	}
+
+	orderBytes, err := ledgermanager.GetState(DocType_Order, orderKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order := OrderStruct{}
+	if err := json.Unmarshal(orderBytes, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// GetOpenOrders side 의 미체결 주문을 가격-시간 우선순위로 정렬
+func GetOpenOrders(ctx contractapi.TransactionContextInterface, partition string, cashPartition string, side string) ([]OrderStruct, error) {
+
+	openIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(DocType_OpenOrder, []string{partition, cashPartition, side})
+	if err != nil {
+		return nil, err
+	}
+	defer openIterator.Close()
+
+	orders := []OrderStruct{}
+	for openIterator.HasNext() {
+		queryResponse, err := openIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		openOrder := OpenOrderStruct{}
+		if err := json.Unmarshal(queryResponse.Value, &openOrder); err != nil {
+			return nil, err
+		}
+
+		order, err := GetOrder(ctx, openOrder.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Before(orders[j]) })
+
+	return orders, nil
+}
+
+// CancelOrder 만료 전에는 주문자만 (cancelled), 만료 후에는 누구나 (expired).
+// 남은 escrow 는 Refunded 로 옮기고, 환불 이전은 controller 에서 처리
+func CancelOrder(ctx contractapi.TransactionContextInterface, order OrderStruct, cancelledBy string) (*OrderStruct, error) {
+
+	if order.Status != StatusOpen {
+		return nil, ccutils.CreateError(CodeErrorOrderNotOpen, fmt.Errorf(ErrorCodeMessage[CodeErrorOrderNotOpen]+" : "+order.OrderId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := StatusExpired
+	if !order.IsExpired(now) {
+		if cancelledBy != order.Trader {
+			return nil, ccutils.CreateError(CodeErrorNotTrader, fmt.Errorf(ErrorCodeMessage[CodeErrorNotTrader]+" : "+cancelledBy))
+		}
+		status = StatusCancelled
+	}
+
+	order = closeOrder(order, status, cancelledBy, now)
+
+	err = putOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// Match 매수 최고가부터 가격이 맞는 매도 주문과 가격-시간 순으로 체결. 체결 가격은 먼저 들어와 있던 주문의 가격.
+// 같은 주문자끼리는 체결하지 않고, 만료된 주문은 닫음. 주문/체결 기록까지 저장하고 잔고 이전은 controller 에서 처리
+func Match(ctx contractapi.TransactionContextInterface, partition string, cashPartition string, maxFills int, check FillCheck) (*MatchResultStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := MatchResultStruct{Fills: []FillStruct{}, Orders: []OrderStruct{}, Expired: []OrderStruct{}}
+
+	activeOrders := func(side string) ([]OrderStruct, error) {
+		orders, err := GetOpenOrders(ctx, partition, cashPartition, side)
+		if err != nil {
+			return nil, err
+		}
+
+		active := []OrderStruct{}
+		for _, order := range orders {
+			if order.IsExpired(now) {
+				result.Expired = append(result.Expired, closeOrder(order, StatusExpired, "", now))
+				continue
+			}
+			active = append(active, order)
+		}
+		return active, nil
+	}
+
+	bids, err := activeOrders(SideBuy)
+	if err != nil {
+		return nil, err
+	}
+
+	asks, err := activeOrders(SideSell)
+	if err != nil {
+		return nil, err
+	}
+
+	touchedBids := make(map[int]bool)
+	touchedAsks := make(map[int]bool)
+
+matching:
+	for i := range bids {
+		for j := range asks {
+			bid := &bids[i]
+			ask := &asks[j]
+
+			if bid.Remaining() == 0 {
+				break
+			}
+
+			if ask.Price > bid.Price {
+				break
+			}
+
+			if ask.Remaining() == 0 || ask.Trader == bid.Trader {
+				continue
+			}
+
+			quantity := bid.Remaining()
+			if ask.Remaining() < quantity {
+				quantity = ask.Remaining()
+			}
+
+			price := bid.Price
+			if ask.CreatedAt < bid.CreatedAt || (ask.CreatedAt == bid.CreatedAt && ask.OrderId < bid.OrderId) {
+				price = ask.Price
+			}
+			cashAmount := price * quantity
+
+			if check != nil && check(*bid, *ask, quantity, cashAmount) != nil {
+				continue
+			}
+
+			bid.Filled += quantity
+			bid.Escrowed -= bid.Price * quantity
+			bid.UpdatedAt = now
+			if bid.Remaining() == 0 {
+				bid.Status = StatusFilled
+			}
+			touchedBids[i] = true
+
+			ask.Filled += quantity
+			ask.Escrowed -= quantity
+			ask.UpdatedAt = now
+			if ask.Remaining() == 0 {
+				ask.Status = StatusFilled
+			}
+			touchedAsks[j] = true
+
+			fill := FillStruct{
+				FillId:        fmt.Sprintf("%s_%d", ctx.GetStub().GetTxID(), len(result.Fills)),
+				BuyOrderId:    bid.OrderId,
+				SellOrderId:   ask.OrderId,
+				Buyer:         bid.Trader,
+				Seller:        ask.Trader,
+				Partition:     partition,
+				CashPartition: cashPartition,
+				Price:         price,
+				Quantity:      quantity,
+				CashAmount:    cashAmount,
+				MatchedAt:     now,
+			}
+			result.Fills = append(result.Fills, fill)
+
+			if maxFills > 0 && len(result.Fills) >= maxFills {
+				break matching
+			}
+		}
+	}
+
+	for i := range bids {
+		if touchedBids[i] {
+			result.Orders = append(result.Orders, bids[i])
+		}
+	}
+	for j := range asks {
+		if touchedAsks[j] {
+			result.Orders = append(result.Orders, asks[j])
+		}
+	}
+	result.Orders = append(result.Orders, result.Expired...)
+
+	for _, order := range result.Orders {
+		err = putOrder(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, fill := range result.Fills {
+		fillKey, err := ctx.GetStub().CreateCompositeKey(DocType_Fill, []string{fill.FillId})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Fill, err)
+		}
+
+		_, err = ledgermanager.PutState(DocType_Fill, fillKey, fill, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
+}
+
+// GetDepth 만료되지 않은 미체결 주문의 호가별 잔량. 매수는 높은 가격, 매도는 낮은 가격부터
+func GetDepth(ctx contractapi.TransactionContextInterface, partition string, cashPartition string) (map[string]interface{}, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := map[string]interface{}{
+		FieldPartition:     partition,
+		FieldCashPartition: cashPartition,
+	}
+
+	for side, field := range map[string]string{SideBuy: FieldBids, SideSell: FieldAsks} {
+		orders, err := GetOpenOrders(ctx, partition, cashPartition, side)
+		if err != nil {
+			return nil, err
+		}
+
+		levels := []PriceLevelStruct{}
+		for _, order := range orders {
+			if order.IsExpired(now) {
+				continue
+			}
+
+			if len(levels) == 0 || levels[len(levels)-1].Price != order.Price {
+				levels = append(levels, PriceLevelStruct{Price: order.Price})
+			}
+			levels[len(levels)-1].Quantity += order.Remaining()
+			levels[len(levels)-1].Orders++
+		}
+		depth[field] = levels
+	}
+
+	return depth, nil
+}
+
+// HasOpenOrder partition 을 토큰 또는 cash 로 쓰는 미체결 주문이 있는지
+func HasOpenOrder(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Order)
+	queryBuilder.AddSelectorGroup(FieldStatus, StatusOpen)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldPartition, partition)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldCashPartition, partition)
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetOrderList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Order)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldTrader, FieldSide, FieldPartition, FieldCashPartition, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func GetFillList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Fill)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldBuyer, FieldSeller, FieldBuyOrderId, FieldSellOrderId, FieldPartition, FieldCashPartition}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+// closeOrder 남은 escrow 를 Refunded 로 옮김
+func closeOrder(order OrderStruct, status string, closedBy string, now int64) OrderStruct {
+
+	order.Refunded = order.Escrowed
+	order.Escrowed = 0
+	order.Status = status
+	order.ClosedBy = closedBy
+	order.UpdatedAt = now
+
+	return order
+}
+
+func openOrderKey(ctx contractapi.TransactionContextInterface, order OrderStruct) (string, error) {
+
+	openKey, err := ctx.GetStub().CreateCompositeKey(DocType_OpenOrder, []string{order.Partition, order.CashPartition, order.Side, order.OrderId})
+	if err != nil {
+		return "", fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_OpenOrder, err)
+	}
+
+	return openKey, nil
+}
+
+// putOrder 주문 갱신. 닫힌 주문은 미체결 색인에서 제거
+func putOrder(ctx contractapi.TransactionContextInterface, order OrderStruct) error {
+
+	orderKey, err := ctx.GetStub().CreateCompositeKey(DocType_Order, []string{order.OrderId})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Order, err)
+	}
+
+	orderToMap, err := ccutils.StructToMap(order)
+	if err != nil {
+		return err
+	}
+
+	err = ledgermanager.UpdateState(DocType_Order, orderKey, orderToMap, ctx)
+	if err != nil {
+		return err
+	}
+
+	if order.Status == StatusOpen {
+		return nil
+	}
+
+	openKey, err := openOrderKey(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.DeleteState(DocType_OpenOrder, openKey, ctx)
+}
@@ -0,0 +1,17 @@
+package ownership
+
+const CodeErrorAlreadyInitialized int = 610
+const CodeErrorNotInitialized int = 611
+const CodeErrorNotOwner int = 612
+const CodeErrorNotPendingOwner int = 613
+const CodeErrorFeatureDisabled int = 614
+const CodeErrorProtectedFeature int = 615
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorAlreadyInitialized: "Init error : contract is already initialized",
+	CodeErrorNotInitialized:     "Init error : contract is not initialized",
+	CodeErrorNotOwner:           "Ownership error : caller is not the owner",
+	CodeErrorNotPendingOwner:    "Ownership error : caller is not the pending owner",
+	CodeErrorFeatureDisabled:    "Config error : function is disabled",
+	CodeErrorProtectedFeature:   "Config error : function cannot be disabled",
+}
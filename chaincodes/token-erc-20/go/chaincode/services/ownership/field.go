@@ -0,0 +1,9 @@
+package ownership
+
+const (
+	FieldNewOwner        string = "newOwner"
+	FieldOwnerMSP        string = "ownerMSP"
+	FieldDefaultPageSize string = "defaultPageSize"
+	FieldTimezone        string = "timezone"
+	FieldFeatures        string = "features"
+)
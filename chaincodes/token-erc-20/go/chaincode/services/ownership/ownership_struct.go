@@ -0,0 +1,49 @@
+package ownership
+
+const (
+	DocType_Ownership = "DOCTYPE_OWNERSHIP"
+
+	OwnershipKey = "Ownership"
+
+	DefaultPageSize int32  = 10
+	DefaultTimezone string = "Asia/Seoul"
+
+	// 날짜 표시 형식
+	DateFormat = "2006-01-02 15:04:05"
+)
+
+// false 로 꺼두면 role / config 를 되돌릴 수 없어 contract 가 잠기는 함수
+var ProtectedFeatures = []string{
+	"GrantRole",
+	"RevokeRole",
+	"Unpause",
+	"ScheduleAction",
+	"ExecuteScheduledAction",
+	"CancelScheduledAction",
+}
+
+type OwnershipStruct struct {
+	DocType string `json:"docType"`
+
+	Owner    string `json:"owner"`
+	OwnerMSP string `json:"ownerMSP"`
+
+	// 2단계 소유권 이전 (TransferOwnership -> AcceptOwnership)
+	PendingOwner string `json:"pendingOwner"`
+
+	// IsInit 시점의 체인코드 버전
+	Version string `json:"version"`
+
+	Config ContractConfig `json:"config"`
+}
+
+type ContractConfig struct {
+	// pageSize 를 주지 않은 목록 조회에 쓰이는 기본값
+	DefaultPageSize int32 `json:"defaultPageSize"`
+
+	// 응답에서 날짜를 표시할 때 쓰는 IANA timezone
+	Timezone string `json:"timezone"`
+
+	// 함수 이름 -> 사용 여부. false 로 명시된 함수만 호출이 막힘
+	Features map[string]bool `json:"features"`
+}
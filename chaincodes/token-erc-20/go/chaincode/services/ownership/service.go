@@ -0,0 +1,356 @@
+package ownership
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	// peer 이미지에 tzdata 가 없어도 모든 peer 가 같은 결과를 내도록 포함
+	_ "time/tzdata"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+func IsInitialized(ctx contractapi.TransactionContextInterface) (bool, error) {
+	return ledgermanager.CheckExistState(OwnershipKey, ctx)
+}
+
+func InitOwnership(ctx contractapi.TransactionContextInterface, ownership OwnershipStruct) (*OwnershipStruct, error) {
+
+	initialized, err := IsInitialized(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 두번째 초기화 시도
+	if initialized {
+		return nil, ccutils.CreateError(CodeErrorAlreadyInitialized, fmt.Errorf(ErrorCodeMessage[CodeErrorAlreadyInitialized]))
+	}
+
+	config, err := NormalizeConfig(ownership.Config)
+	if err != nil {
+		return nil, err
+	}
+	ownership.Config = *config
+
+	_, err = ledgermanager.PutState(DocType_Ownership, OwnershipKey, ownership, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ownership, nil
+}
+
+func GetOwnership(ctx contractapi.TransactionContextInterface) (*OwnershipStruct, error) {
+
+	initialized, err := IsInitialized(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !initialized {
+		return nil, ccutils.CreateError(CodeErrorNotInitialized, fmt.Errorf(ErrorCodeMessage[CodeErrorNotInitialized]))
+	}
+
+	ownershipBytes, err := ledgermanager.GetState(DocType_Ownership, OwnershipKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ownership := OwnershipStruct{}
+	if err := json.Unmarshal(ownershipBytes, &ownership); err != nil {
+		return nil, err
+	}
+
+	return &ownership, nil
+}
+
+func CheckOwner(ctx contractapi.TransactionContextInterface, caller string) error {
+
+	ownership, err := GetOwnership(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ownership.Owner != caller {
+		return ccutils.CreateError(CodeErrorNotOwner, fmt.Errorf(ErrorCodeMessage[CodeErrorNotOwner]))
+	}
+
+	return nil
+}
+
+// TransferOwnership 소유권 이전 1단계. newOwner 가 AcceptOwnership 을 호출해야 이전이 완료됨
+func TransferOwnership(ctx contractapi.TransactionContextInterface, caller string, newOwner string) (*OwnershipStruct, error) {
+
+	ownership, err := GetOwnership(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownership.Owner != caller {
+		return nil, ccutils.CreateError(CodeErrorNotOwner, fmt.Errorf(ErrorCodeMessage[CodeErrorNotOwner]))
+	}
+
+	if newOwner == ownership.Owner {
+		return nil, fmt.Errorf("new owner is already the owner")
+	}
+
+	ownership.PendingOwner = newOwner
+
+	err = updateOwnership(ctx, *ownership)
+	if err != nil {
+		return nil, err
+	}
+
+	return ownership, nil
+}
+
+// CheckPendingOwner caller 가 이전 대기 중인 새 owner 인지 확인
+func CheckPendingOwner(ctx contractapi.TransactionContextInterface, caller string) error {
+
+	ownership, err := GetOwnership(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ownership.PendingOwner == "" || ownership.PendingOwner != caller {
+		return ccutils.CreateError(CodeErrorNotPendingOwner, fmt.Errorf(ErrorCodeMessage[CodeErrorNotPendingOwner]))
+	}
+
+	return nil
+}
+
+// AcceptOwnership 소유권 이전 2단계. 이전 owner 주소를 함께 돌려줌
+func AcceptOwnership(ctx contractapi.TransactionContextInterface, caller string, callerMSP string) (*OwnershipStruct, string, error) {
+
+	ownership, err := GetOwnership(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if ownership.PendingOwner == "" || ownership.PendingOwner != caller {
+		return nil, "", ccutils.CreateError(CodeErrorNotPendingOwner, fmt.Errorf(ErrorCodeMessage[CodeErrorNotPendingOwner]))
+	}
+
+	previousOwner := ownership.Owner
+
+	ownership.Owner = caller
+	ownership.OwnerMSP = callerMSP
+	ownership.PendingOwner = ""
+
+	err = updateOwnership(ctx, *ownership)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return ownership, previousOwner, nil
+}
+
+func GetConfig(ctx contractapi.TransactionContextInterface) (*ContractConfig, error) {
+
+	ownership, err := GetOwnership(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ownership.Config, nil
+}
+
+// SetConfig contract config 교체. timelock 을 거쳐 실행됨
+func SetConfig(ctx contractapi.TransactionContextInterface, config ContractConfig) (*ContractConfig, error) {
+
+	ownership, err := GetOwnership(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newConfig, err := NormalizeConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	ownership.Config = *newConfig
+
+	err = updateOwnership(ctx, *ownership)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConfig, nil
+}
+
+// ParseConfig IsInit 과 SetContractConfig action 의 config 파라메터
+// {"defaultPageSize":10,"timezone":"Asia/Seoul","features":{"AirDrop":false}}
+func ParseConfig(args map[string]interface{}) (*ContractConfig, error) {
+
+	err := ccutils.CheckTypeInt64([]string{FieldDefaultPageSize}, args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ccutils.CheckTypeString([]string{FieldTimezone}, args)
+	if err != nil {
+		return nil, err
+	}
+
+	config := ContractConfig{}
+	config.Features = make(map[string]bool)
+
+	if value, exist := args[FieldDefaultPageSize]; exist {
+		config.DefaultPageSize = int32(value.(float64))
+	}
+
+	if value, exist := args[FieldTimezone]; exist {
+		config.Timezone = value.(string)
+	}
+
+	if value, exist := args[FieldFeatures]; exist {
+		features, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("check parameter type : parameter field = %v is not object", FieldFeatures)
+		}
+
+		for feature, enabled := range features {
+			enabledBool, ok := enabled.(bool)
+			if !ok {
+				return nil, fmt.Errorf("feature %s must be a bool", feature)
+			}
+			config.Features[feature] = enabledBool
+		}
+	}
+
+	return &config, nil
+}
+
+// FormatTimestamp unix 초를 config 의 timezone 으로 표시. 초기화 전이거나 설정값이 없으면 DefaultTimezone
+func FormatTimestamp(ctx contractapi.TransactionContextInterface, timestamp int64) (string, error) {
+
+	timezone := DefaultTimezone
+
+	initialized, err := IsInitialized(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if initialized {
+		config, err := GetConfig(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		if config.Timezone != "" {
+			timezone = config.Timezone
+		}
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "", fmt.Errorf("invalid timezone %s : %v", timezone, err)
+	}
+
+	return time.Unix(timestamp, 0).In(location).Format(DateFormat), nil
+}
+
+// GetDefaultPageSize 초기화 전이거나 설정값이 없으면 DefaultPageSize
+func GetDefaultPageSize(ctx contractapi.TransactionContextInterface) (int32, error) {
+
+	initialized, err := IsInitialized(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if !initialized {
+		return DefaultPageSize, nil
+	}
+
+	config, err := GetConfig(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if config.DefaultPageSize <= 0 {
+		return DefaultPageSize, nil
+	}
+
+	return config.DefaultPageSize, nil
+}
+
+// IsFeatureEnabled Features 에 false 로 명시된 경우에만 비활성. 초기화 전에는 모두 활성
+func IsFeatureEnabled(ctx contractapi.TransactionContextInterface, feature string) (bool, error) {
+
+	initialized, err := IsInitialized(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if !initialized {
+		return true, nil
+	}
+
+	config, err := GetConfig(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	enabled, exist := config.Features[feature]
+	if !exist {
+		return true, nil
+	}
+
+	return enabled, nil
+}
+
+func CheckFeatureEnabled(ctx contractapi.TransactionContextInterface, feature string) error {
+
+	enabled, err := IsFeatureEnabled(ctx, feature)
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		return ccutils.CreateError(CodeErrorFeatureDisabled, fmt.Errorf(ErrorCodeMessage[CodeErrorFeatureDisabled]+" : "+feature))
+	}
+
+	return nil
+}
+
+// NormalizeConfig 기본값을 채우고 timezone 과 잠금 위험이 있는 feature 를 확인
+func NormalizeConfig(config ContractConfig) (*ContractConfig, error) {
+
+	if config.DefaultPageSize <= 0 {
+		config.DefaultPageSize = DefaultPageSize
+	}
+
+	if config.Timezone == "" {
+		config.Timezone = DefaultTimezone
+	}
+
+	_, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %s : %v", config.Timezone, err)
+	}
+
+	if config.Features == nil {
+		config.Features = make(map[string]bool)
+	}
+
+	for _, feature := range ProtectedFeatures {
+		if enabled, exist := config.Features[feature]; exist && !enabled {
+			return nil, ccutils.CreateError(CodeErrorProtectedFeature, fmt.Errorf(ErrorCodeMessage[CodeErrorProtectedFeature]+" : "+feature))
+		}
+	}
+
+	return &config, nil
+}
+
+func updateOwnership(ctx contractapi.TransactionContextInterface, ownership OwnershipStruct) error {
+
+	ownershipToMap, err := ccutils.StructToMap(ownership)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_Ownership, OwnershipKey, ownershipToMap, ctx)
+}
@@ -0,0 +1,9 @@
+package pause
+
+const CodeErrorContractPaused int = 670
+const CodeErrorPartitionPaused int = 671
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorContractPaused:  "Pause error : operation is paused for the whole contract",
+	CodeErrorPartitionPaused: "Pause error : operation is paused for the partition",
+}
@@ -0,0 +1,6 @@
+package pause
+
+const (
+	FieldPartition  string = "partition"
+	FieldOperations string = "operations"
+)
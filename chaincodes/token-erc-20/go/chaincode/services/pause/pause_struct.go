@@ -0,0 +1,62 @@
+package pause
+
+const (
+	DocType_Pause = "DOCTYPE_PAUSE"
+
+	// partition 이 비어 있으면 contract 전체
+	PartitionAll = ""
+)
+
+// 일시 정지 대상 operation
+const (
+	OperationIssuance   = "issuance"
+	OperationTransfer   = "transfer"
+	OperationRedemption = "redemption"
+)
+
+var Operations = []string{OperationIssuance, OperationTransfer, OperationRedemption}
+
+func IsValidOperation(operation string) bool {
+	for _, value := range Operations {
+		if value == operation {
+			return true
+		}
+	}
+	return false
+}
+
+type PauseStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string `json:"partition"`
+
+	IssuancePaused   bool `json:"issuancePaused"`
+	TransferPaused   bool `json:"transferPaused"`
+	RedemptionPaused bool `json:"redemptionPaused"`
+
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+func (p *PauseStruct) IsPaused(operation string) bool {
+	switch operation {
+	case OperationIssuance:
+		return p.IssuancePaused
+	case OperationTransfer:
+		return p.TransferPaused
+	case OperationRedemption:
+		return p.RedemptionPaused
+	}
+	return false
+}
+
+func (p *PauseStruct) setPaused(operation string, paused bool) {
+	switch operation {
+	case OperationIssuance:
+		p.IssuancePaused = paused
+	case OperationTransfer:
+		p.TransferPaused = paused
+	case OperationRedemption:
+		p.RedemptionPaused = paused
+	}
+}
@@ -0,0 +1,123 @@
+package pause
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// GetPause 기록이 없으면 모두 정지되지 않은 상태
+func GetPause(ctx contractapi.TransactionContextInterface, partition string) (*PauseStruct, error) {
+
+	pauseKey, err := ctx.GetStub().CreateCompositeKey(DocType_Pause, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Pause, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(pauseKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &PauseStruct{DocType: DocType_Pause, Partition: partition}, nil
+	}
+
+	pauseBytes, err := ledgermanager.GetState(DocType_Pause, pauseKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pause := PauseStruct{}
+	if err := json.Unmarshal(pauseBytes, &pause); err != nil {
+		return nil, err
+	}
+
+	return &pause, nil
+}
+
+func SetPause(ctx contractapi.TransactionContextInterface, partition string, operations []string, paused bool, updatedBy string) (*PauseStruct, error) {
+
+	for _, operation := range operations {
+		if !IsValidOperation(operation) {
+			return nil, fmt.Errorf("unknown operation : %s", operation)
+		}
+	}
+
+	pause, err := GetPause(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, operation := range operations {
+		pause.setPaused(operation, paused)
+	}
+	pause.UpdatedBy = updatedBy
+	pause.UpdatedAt = now
+
+	pauseKey, err := ctx.GetStub().CreateCompositeKey(DocType_Pause, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Pause, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(pauseKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		pauseToMap, err := ccutils.StructToMap(pause)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_Pause, pauseKey, pauseToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_Pause, pauseKey, *pause, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pause, nil
+}
+
+// CheckNotPaused contract 전체, partition 순서로 확인
+func CheckNotPaused(ctx contractapi.TransactionContextInterface, partition string, operation string) error {
+
+	contractPause, err := GetPause(ctx, PartitionAll)
+	if err != nil {
+		return err
+	}
+
+	if contractPause.IsPaused(operation) {
+		return ccutils.CreateError(CodeErrorContractPaused, fmt.Errorf(ErrorCodeMessage[CodeErrorContractPaused]+" : "+operation))
+	}
+
+	if partition == PartitionAll {
+		return nil
+	}
+
+	partitionPause, err := GetPause(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	if partitionPause.IsPaused(operation) {
+		return ccutils.CreateError(CodeErrorPartitionPaused, fmt.Errorf(ErrorCodeMessage[CodeErrorPartitionPaused]+" : "+partition+", "+operation))
+	}
+
+	return nil
+}
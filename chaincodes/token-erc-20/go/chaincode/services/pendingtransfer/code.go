@@ -0,0 +1,17 @@
+package pendingtransfer
+
+const CodeErrorRequestNotPending int = 760
+const CodeErrorRequestExpired int = 761
+const CodeErrorRequestNotExpired int = 762
+const CodeErrorInsufficientUnreserved int = 763
+const CodeErrorNotApprover int = 764
+const CodeErrorInvalidExpiry int = 765
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorRequestNotPending:      "Pending transfer error : request is not pending",
+	CodeErrorRequestExpired:         "Pending transfer error : request has expired",
+	CodeErrorRequestNotExpired:      "Pending transfer error : request has not expired yet",
+	CodeErrorInsufficientUnreserved: "Pending transfer error : insufficient unreserved balance",
+	CodeErrorNotApprover:            "Pending transfer error : caller is not an approver of the partition",
+	CodeErrorInvalidExpiry:          "Pending transfer error : expiresAt must be in the future",
+}
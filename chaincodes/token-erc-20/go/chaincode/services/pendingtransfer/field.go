@@ -0,0 +1,15 @@
+package pendingtransfer
+
+const (
+	FieldRequestId string = "requestId"
+	FieldFrom      string = "from"
+	FieldTo        string = "to"
+	FieldPartition string = "partition"
+	FieldAmount    string = "amount"
+	FieldStatus    string = "status"
+	FieldExpiresAt string = "expiresAt"
+	FieldReason    string = "reason"
+	FieldApprovers string = "approvers"
+
+	FieldReserved string = "reserved"
+)
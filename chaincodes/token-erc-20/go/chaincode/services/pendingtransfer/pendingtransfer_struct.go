@@ -0,0 +1,79 @@
+package pendingtransfer
+
+const (
+	DocType_PendingTransfer     = "DOCTYPE_PENDINGTRANSFER"
+	DocType_TransferReservation = "DOCTYPE_TRANSFERRESERVATION"
+	DocType_TransferApprovers   = "DOCTYPE_TRANSFERAPPROVERS"
+)
+
+// Pending transfer status
+const (
+	StatusPending   = "pending"
+	StatusExecuted  = "executed"
+	StatusRejected  = "rejected"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+const (
+	// expiresAt 생략 시 요청 시점부터의 유효 기간
+	DefaultExpirySeconds int64 = 7 * 24 * 60 * 60
+)
+
+// 발행사 승인을 기다리는 2차 거래. 승인 시 From 에서 To 로 이전 실행
+type PendingTransferStruct struct {
+	DocType string `json:"docType"`
+
+	RequestId string `json:"requestId"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Partition string `json:"partition"`
+	Amount    int64  `json:"amount"`
+
+	Status    string `json:"status"`
+	ExpiresAt int64  `json:"expiresAt"`
+	Reason    string `json:"reason"`
+
+	RequestedAt int64  `json:"requestedAt"`
+	ClosedAt    int64  `json:"closedAt"`
+	ClosedBy    string `json:"closedBy"`
+}
+
+type ReservedStruct struct {
+	Amount    int64 `json:"amount"`
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// (holder, partition) 의 대기 중인 요청별 예약 수량. key 는 requestId
+type TransferReservationStruct struct {
+	DocType string `json:"docType"`
+
+	Holder       string                    `json:"holder"`
+	Partition    string                    `json:"partition"`
+	Reservations map[string]ReservedStruct `json:"reservations"`
+}
+
+// ReservedAmount now 시점에 만료되지 않은 예약 합계. except 요청은 제외
+func (r TransferReservationStruct) ReservedAmount(now int64, except string) int64 {
+
+	var total int64
+	for requestId, reserved := range r.Reservations {
+		if requestId == except || now >= reserved.ExpiresAt {
+			continue
+		}
+		total += reserved.Amount
+	}
+
+	return total
+}
+
+// partition 별로 발행사 대신 요청을 승인/거절할 수 있는 주소
+type TransferApproversStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string   `json:"partition"`
+	Approvers []string `json:"approvers"`
+
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
@@ -0,0 +1,341 @@
+package pendingtransfer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// CreateRequest 요청 저장 후 From 의 잔고에서 Amount 만큼 예약. 출금 가능 여부는 controller 에서 확인
+func CreateRequest(ctx contractapi.TransactionContextInterface, request PendingTransferStruct) (*PendingTransferStruct, error) {
+
+	if request.Amount <= 0 {
+		return nil, fmt.Errorf("invalid amount : %d", request.Amount)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.ExpiresAt == 0 {
+		request.ExpiresAt = now + DefaultExpirySeconds
+	}
+
+	if request.ExpiresAt <= now {
+		return nil, ccutils.CreateError(CodeErrorInvalidExpiry, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidExpiry]+" : %d", request.ExpiresAt))
+	}
+
+	request.RequestId = ctx.GetStub().GetTxID()
+	request.Status = StatusPending
+	request.RequestedAt = now
+
+	requestKey, err := ctx.GetStub().CreateCompositeKey(DocType_PendingTransfer, []string{request.RequestId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PendingTransfer, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_PendingTransfer, requestKey, request, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, exist, err := getReservation(ctx, request.From, request.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	// 만료된 예약은 여기서 정리
+	for requestId, reserved := range reservation.Reservations {
+		if now >= reserved.ExpiresAt {
+			delete(reservation.Reservations, requestId)
+		}
+	}
+	reservation.Reservations[request.RequestId] = ReservedStruct{Amount: request.Amount, ExpiresAt: request.ExpiresAt}
+
+	err = putReservation(ctx, *reservation, exist)
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+func GetRequest(ctx contractapi.TransactionContextInterface, requestId string) (*PendingTransferStruct, error) {
+
+	requestKey, err := ctx.GetStub().CreateCompositeKey(DocType_PendingTransfer, []string{requestId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PendingTransfer, err)
+	}
+
+	requestBytes, err := ledgermanager.GetState(DocType_PendingTransfer, requestKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := PendingTransferStruct{}
+	if err := json.Unmarshal(requestBytes, &request); err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// CloseRequest 요청을 종료하고 예약 해제.
+// executed/rejected/cancelled 는 만료 전에만, expired 는 만료 후에만 가능
+func CloseRequest(ctx contractapi.TransactionContextInterface, request PendingTransferStruct, status string, closedBy string, reason string) (*PendingTransferStruct, error) {
+
+	if request.Status != StatusPending {
+		return nil, ccutils.CreateError(CodeErrorRequestNotPending, fmt.Errorf(ErrorCodeMessage[CodeErrorRequestNotPending]+" : "+request.RequestId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expired := now >= request.ExpiresAt
+	if status == StatusExpired && !expired {
+		return nil, ccutils.CreateError(CodeErrorRequestNotExpired, fmt.Errorf(ErrorCodeMessage[CodeErrorRequestNotExpired]+" : "+request.RequestId))
+	}
+
+	if status != StatusExpired && expired {
+		return nil, ccutils.CreateError(CodeErrorRequestExpired, fmt.Errorf(ErrorCodeMessage[CodeErrorRequestExpired]+" : "+request.RequestId))
+	}
+
+	request.Status = status
+	request.Reason = reason
+	request.ClosedAt = now
+	request.ClosedBy = closedBy
+
+	requestKey, err := ctx.GetStub().CreateCompositeKey(DocType_PendingTransfer, []string{request.RequestId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PendingTransfer, err)
+	}
+
+	requestToMap, err := ccutils.StructToMap(request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_PendingTransfer, requestKey, requestToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, exist, err := getReservation(ctx, request.From, request.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		delete(reservation.Reservations, request.RequestId)
+
+		err = putReservation(ctx, *reservation, exist)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &request, nil
+}
+
+// GetReservedAmount holder 의 partition 잔고 중 대기 요청에 묶인 수량. except 요청은 제외
+func GetReservedAmount(ctx contractapi.TransactionContextInterface, holder string, partition string, except string) (int64, error) {
+
+	reservation, exist, err := getReservation(ctx, holder, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exist {
+		return 0, nil
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return reservation.ReservedAmount(now, except), nil
+}
+
+func SetApprovers(ctx contractapi.TransactionContextInterface, partition string, approvers []string, updatedBy string) (*TransferApproversStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	approversStruct := TransferApproversStruct{DocType: DocType_TransferApprovers, Partition: partition, Approvers: approvers, UpdatedBy: updatedBy, UpdatedAt: now}
+
+	approversKey, err := ctx.GetStub().CreateCompositeKey(DocType_TransferApprovers, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TransferApprovers, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(approversKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		approversToMap, err := ccutils.StructToMap(approversStruct)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_TransferApprovers, approversKey, approversToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_TransferApprovers, approversKey, approversStruct, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &approversStruct, nil
+}
+
+// GetApprovers 설정이 없으면 빈 목록
+func GetApprovers(ctx contractapi.TransactionContextInterface, partition string) (*TransferApproversStruct, error) {
+
+	approversKey, err := ctx.GetStub().CreateCompositeKey(DocType_TransferApprovers, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TransferApprovers, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(approversKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &TransferApproversStruct{DocType: DocType_TransferApprovers, Partition: partition, Approvers: []string{}}, nil
+	}
+
+	approversBytes, err := ledgermanager.GetState(DocType_TransferApprovers, approversKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	approversStruct := TransferApproversStruct{}
+	if err := json.Unmarshal(approversBytes, &approversStruct); err != nil {
+		return nil, err
+	}
+
+	return &approversStruct, nil
+}
+
+// IsApprover address 가 partition 의 위임 승인자인지
+func IsApprover(ctx contractapi.TransactionContextInterface, partition string, address string) (bool, error) {
+
+	approversStruct, err := GetApprovers(ctx, partition)
+	if err != nil {
+		return false, err
+	}
+
+	for _, approver := range approversStruct.Approvers {
+		if approver == address {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// HasPendingRequest partition 에 승인 대기 중인 요청이 있는지
+func HasPendingRequest(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_PendingTransfer)
+	queryBuilder.AddSelectorGroup(FieldPartition, partition)
+	queryBuilder.AddSelectorGroup(FieldStatus, StatusPending)
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetRequestList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_PendingTransfer)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldFrom, FieldTo, FieldPartition, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func getReservation(ctx contractapi.TransactionContextInterface, holder string, partition string) (*TransferReservationStruct, bool, error) {
+
+	reservationKey, err := ctx.GetStub().CreateCompositeKey(DocType_TransferReservation, []string{holder, partition})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TransferReservation, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(reservationKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !exist {
+		return &TransferReservationStruct{DocType: DocType_TransferReservation, Holder: holder, Partition: partition, Reservations: map[string]ReservedStruct{}}, false, nil
+	}
+
+	reservationBytes, err := ledgermanager.GetState(DocType_TransferReservation, reservationKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reservation := TransferReservationStruct{}
+	if err := json.Unmarshal(reservationBytes, &reservation); err != nil {
+		return nil, false, err
+	}
+
+	if reservation.Reservations == nil {
+		reservation.Reservations = map[string]ReservedStruct{}
+	}
+
+	return &reservation, true, nil
+}
+
+func putReservation(ctx contractapi.TransactionContextInterface, reservation TransferReservationStruct, exist bool) error {
+
+	reservationKey, err := ctx.GetStub().CreateCompositeKey(DocType_TransferReservation, []string{reservation.Holder, reservation.Partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TransferReservation, err)
+	}
+
+	if exist {
+		reservationToMap, err := ccutils.StructToMap(reservation)
+		if err != nil {
+			return err
+		}
+
+		return ledgermanager.UpdateState(DocType_TransferReservation, reservationKey, reservationToMap, ctx)
+	}
+
+	_, err = ledgermanager.PutState(DocType_TransferReservation, reservationKey, reservation, ctx)
+	return err
+}
@@ -0,0 +1,15 @@
+package pledge
+
+const CodeErrorInsufficientUnpledged int = 860
+const CodeErrorPledgeNotActive int = 861
+const CodeErrorNotPledgee int = 862
+const CodeErrorNotDefaulted int = 863
+const CodeErrorGracePeriod int = 864
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorInsufficientUnpledged: "Pledge error : insufficient balance not pledged",
+	CodeErrorPledgeNotActive:       "Pledge error : pledge is already closed",
+	CodeErrorNotPledgee:            "Pledge error : caller is not the pledgee of the pledge",
+	CodeErrorNotDefaulted:          "Pledge error : default must be declared before enforcement",
+	CodeErrorGracePeriod:           "Pledge error : grace period after the default declaration has not passed",
+}
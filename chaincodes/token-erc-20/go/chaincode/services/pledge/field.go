@@ -0,0 +1,17 @@
+package pledge
+
+const (
+	FieldPledgeId    string = "pledgeId"
+	FieldPartition   string = "partition"
+	FieldHolder      string = "holder"
+	FieldPledgee     string = "pledgee"
+	FieldAmount      string = "amount"
+	FieldGracePeriod string = "gracePeriod"
+	FieldReason      string = "reason"
+	FieldStatus      string = "status"
+	FieldPledged     string = "pledged"
+	FieldFree        string = "free"
+	FieldBalance     string = "balance"
+	FieldPledges     string = "pledges"
+	FieldTotals      string = "totals"
+)
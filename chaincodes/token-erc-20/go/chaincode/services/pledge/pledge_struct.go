@@ -0,0 +1,90 @@
+package pledge
+
+const (
+	DocType_Pledge         = "DOCTYPE_PLEDGE"
+	DocType_PledgedBalance = "DOCTYPE_PLEDGEDBALANCE"
+	DocType_PledgeeRight   = "DOCTYPE_PLEDGEERIGHT"
+)
+
+// Pledge status
+const (
+	StatusActive    = "active"
+	StatusDefaulted = "defaulted"
+	StatusReleased  = "released"
+	StatusEnforced  = "enforced"
+)
+
+// Holder 의 partition 잔고 중 Pledgee 에게 담보로 제공한 수량. 질권이 살아 있는 동안 출금 불가.
+// Pledgee 가 해제하거나, 채무 불이행 선언 후 GracePeriod 가 지나면 집행해서 Pledgee 에게 이전
+type PledgeStruct struct {
+	DocType string `json:"docType"`
+
+	PledgeId  string `json:"pledgeId"`
+	Partition string `json:"partition"`
+	Holder    string `json:"holder"`
+	Pledgee   string `json:"pledgee"`
+	Amount    int64  `json:"amount"`
+
+	// 채무 불이행 선언 후 집행까지 기다리는 시간 (seconds)
+	GracePeriod int64  `json:"gracePeriod"`
+	Reason      string `json:"reason"`
+	DefaultedAt int64  `json:"defaultedAt"`
+
+	Status    string `json:"status"`
+	CreatedBy string `json:"createdBy"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// IsActive 해제/집행 전. 채무 불이행이 선언된 질권도 계속 묶여 있음
+func (p PledgeStruct) IsActive() bool {
+
+	return p.Status == StatusActive || p.Status == StatusDefaulted
+}
+
+// EnforceableAt 집행 가능 시각. 채무 불이행 선언 전이면 0
+func (p PledgeStruct) EnforceableAt() int64 {
+
+	if p.Status != StatusDefaulted {
+		return 0
+	}
+	return p.DefaultedAt + p.GracePeriod
+}
+
+// (holder, partition) 의 활성 질권별 수량. key 는 pledgeId
+type PledgedBalanceStruct struct {
+	DocType string `json:"docType"`
+
+	Holder    string           `json:"holder"`
+	Partition string           `json:"partition"`
+	Pledges   map[string]int64 `json:"pledges"`
+}
+
+// PledgedAmount 활성 질권 합계. except 질권은 제외
+func (b PledgedBalanceStruct) PledgedAmount(except string) int64 {
+
+	var total int64
+	for pledgeId, amount := range b.Pledges {
+		if pledgeId == except {
+			continue
+		}
+		total += amount
+	}
+
+	return total
+}
+
+// 질권자의 활성 질권 색인. key 는 (pledgee, pledgeId)
+type PledgeeRightStruct struct {
+	DocType string `json:"docType"`
+
+	PledgeId string `json:"pledgeId"`
+}
+
+// 질권자의 partition 별 권리 합계
+type PledgeeTotalStruct struct {
+	Partition string `json:"partition"`
+	Pledged   int64  `json:"pledged"`
+	Defaulted int64  `json:"defaulted"`
+}
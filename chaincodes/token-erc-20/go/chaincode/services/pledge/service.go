@@ -0,0 +1,405 @@
+package pledge
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// CreatePledge 질권 저장 후 Holder 의 잔고에서 Amount 만큼 묶음. 출금 가능 여부는 controller 에서 확인
+func CreatePledge(ctx contractapi.TransactionContextInterface, pledge PledgeStruct) (*PledgeStruct, error) {
+
+	if pledge.Amount <= 0 {
+		return nil, fmt.Errorf("invalid amount : %d", pledge.Amount)
+	}
+
+	if pledge.GracePeriod < 0 {
+		return nil, fmt.Errorf("invalid grace period : %d", pledge.GracePeriod)
+	}
+
+	if pledge.Holder == pledge.Pledgee {
+		return nil, fmt.Errorf("cannot pledge to the same wallet")
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pledge.PledgeId = ctx.GetStub().GetTxID()
+	pledge.Status = StatusActive
+	pledge.CreatedAt = now
+	pledge.UpdatedBy = pledge.CreatedBy
+	pledge.UpdatedAt = now
+
+	pledgeKey, err := ctx.GetStub().CreateCompositeKey(DocType_Pledge, []string{pledge.PledgeId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Pledge, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Pledge, pledgeKey, pledge, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rightKey, err := ctx.GetStub().CreateCompositeKey(DocType_PledgeeRight, []string{pledge.Pledgee, pledge.PledgeId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PledgeeRight, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_PledgeeRight, rightKey, PledgeeRightStruct{PledgeId: pledge.PledgeId}, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pledgedBalance, exist, err := getPledgedBalance(ctx, pledge.Holder, pledge.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	pledgedBalance.Pledges[pledge.PledgeId] = pledge.Amount
+
+	err = putPledgedBalance(ctx, *pledgedBalance, exist)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pledge, nil
+}
+
+func GetPledge(ctx contractapi.TransactionContextInterface, pledgeId string) (*PledgeStruct, error) {
+
+	pledgeKey, err := ctx.GetStub().CreateCompositeKey(DocType_Pledge, []string{pledgeId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Pledge, err)
+	}
+
+	pledgeBytes, err := ledgermanager.GetState(DocType_Pledge, pledgeKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pledge := PledgeStruct{}
+	if err := json.Unmarshal(pledgeBytes, &pledge); err != nil {
+		return nil, err
+	}
+
+	return &pledge, nil
+}
+
+// ReleasePledge 질권자가 질권을 풀어줌. 채무 불이행 선언 후에도 가능
+func ReleasePledge(ctx contractapi.TransactionContextInterface, pledge PledgeStruct, releasedBy string) (*PledgeStruct, error) {
+
+	err := checkPledgee(pledge, releasedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	pledge.Status = StatusReleased
+
+	return updatePledge(ctx, pledge, releasedBy)
+}
+
+// DeclareDefault 질권자의 채무 불이행 선언. GracePeriod 가 지나면 집행 가능
+func DeclareDefault(ctx contractapi.TransactionContextInterface, pledge PledgeStruct, reason string, declaredBy string) (*PledgeStruct, error) {
+
+	err := checkPledgee(pledge, declaredBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if pledge.Status != StatusActive {
+		return nil, fmt.Errorf("default is already declared : %s", pledge.PledgeId)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pledge.Status = StatusDefaulted
+	pledge.Reason = reason
+	pledge.DefaultedAt = now
+
+	return updatePledge(ctx, pledge, declaredBy)
+}
+
+// EnforcePledge 채무 불이행 선언 후 GracePeriod 가 지난 질권을 집행 기록. 실제 이전은 controller 에서 처리
+func EnforcePledge(ctx contractapi.TransactionContextInterface, pledge PledgeStruct, enforcedBy string) (*PledgeStruct, error) {
+
+	err := checkPledgee(pledge, enforcedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if pledge.Status != StatusDefaulted {
+		return nil, ccutils.CreateError(CodeErrorNotDefaulted, fmt.Errorf(ErrorCodeMessage[CodeErrorNotDefaulted]+" : "+pledge.PledgeId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if now < pledge.EnforceableAt() {
+		return nil, ccutils.CreateError(CodeErrorGracePeriod, fmt.Errorf(ErrorCodeMessage[CodeErrorGracePeriod]+" : enforceable at %d", pledge.EnforceableAt()))
+	}
+
+	pledge.Status = StatusEnforced
+
+	return updatePledge(ctx, pledge, enforcedBy)
+}
+
+// GetPledgedAmount holder 의 partition 잔고 중 활성 질권에 묶인 수량. except 질권은 제외
+func GetPledgedAmount(ctx contractapi.TransactionContextInterface, holder string, partition string, except string) (int64, error) {
+
+	pledgedBalance, exist, err := getPledgedBalance(ctx, holder, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exist {
+		return 0, nil
+	}
+
+	return pledgedBalance.PledgedAmount(except), nil
+}
+
+// GetPledgeeRights 질권자의 활성 질권과 partition 별 합계. partition 이 비어 있으면 전체
+func GetPledgeeRights(ctx contractapi.TransactionContextInterface, pledgee string, partition string) (map[string]interface{}, error) {
+
+	rightIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(DocType_PledgeeRight, []string{pledgee})
+	if err != nil {
+		return nil, err
+	}
+	defer rightIterator.Close()
+
+	pledges := []PledgeStruct{}
+	totals := make(map[string]*PledgeeTotalStruct)
+	for rightIterator.HasNext() {
+		queryResponse, err := rightIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		right := PledgeeRightStruct{}
+		if err := json.Unmarshal(queryResponse.Value, &right); err != nil {
+			return nil, err
+		}
+
+		pledge, err := GetPledge(ctx, right.PledgeId)
+		if err != nil {
+			return nil, err
+		}
+
+		if partition != "" && pledge.Partition != partition {
+			continue
+		}
+		pledges = append(pledges, *pledge)
+
+		if totals[pledge.Partition] == nil {
+			totals[pledge.Partition] = &PledgeeTotalStruct{Partition: pledge.Partition}
+		}
+		totals[pledge.Partition].Pledged += pledge.Amount
+		if pledge.Status == StatusDefaulted {
+			totals[pledge.Partition].Defaulted += pledge.Amount
+		}
+	}
+
+	partitions := make([]string, 0, len(totals))
+	for totalPartition := range totals {
+		partitions = append(partitions, totalPartition)
+	}
+	sort.Strings(partitions)
+
+	totalList := []PledgeeTotalStruct{}
+	for _, totalPartition := range partitions {
+		totalList = append(totalList, *totals[totalPartition])
+	}
+
+	rights := map[string]interface{}{
+		FieldPledgee: pledgee,
+		FieldPledges: pledges,
+		FieldTotals:  totalList,
+	}
+
+	return rights, nil
+}
+
+// HasPledgeeRights pledgee 로 지정된 활성 질권이 하나라도 있는지
+func HasPledgeeRights(ctx contractapi.TransactionContextInterface, pledgee string) (bool, error) {
+
+	rightIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(DocType_PledgeeRight, []string{pledgee})
+	if err != nil {
+		return false, err
+	}
+	defer rightIterator.Close()
+
+	return rightIterator.HasNext(), nil
+}
+
+// HasActivePledge partition 에 해제/집행되지 않은 질권이 있는지
+func HasActivePledge(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Pledge)
+	queryBuilder.AddSelectorGroup(FieldPartition, partition)
+	queryBuilder.AddSelectorGroupCondition(FieldStatus, "$in", []string{StatusActive, StatusDefaulted})
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetPledgeList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Pledge)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldHolder, FieldPledgee, FieldPartition, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+// checkPledgee 활성 질권이고 호출자가 질권자인지
+func checkPledgee(pledge PledgeStruct, caller string) error {
+
+	if !pledge.IsActive() {
+		return ccutils.CreateError(CodeErrorPledgeNotActive, fmt.Errorf(ErrorCodeMessage[CodeErrorPledgeNotActive]+" : "+pledge.PledgeId))
+	}
+
+	if caller != pledge.Pledgee {
+		return ccutils.CreateError(CodeErrorNotPledgee, fmt.Errorf(ErrorCodeMessage[CodeErrorNotPledgee]+" : "+caller))
+	}
+
+	return nil
+}
+
+// updatePledge 질권 저장. 해제/집행된 질권은 묶인 수량과 질권자 색인에서 제거
+func updatePledge(ctx contractapi.TransactionContextInterface, pledge PledgeStruct, updatedBy string) (*PledgeStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pledge.UpdatedBy = updatedBy
+	pledge.UpdatedAt = now
+
+	pledgeKey, err := ctx.GetStub().CreateCompositeKey(DocType_Pledge, []string{pledge.PledgeId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Pledge, err)
+	}
+
+	pledgeToMap, err := ccutils.StructToMap(pledge)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_Pledge, pledgeKey, pledgeToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pledge.IsActive() {
+		return &pledge, nil
+	}
+
+	rightKey, err := ctx.GetStub().CreateCompositeKey(DocType_PledgeeRight, []string{pledge.Pledgee, pledge.PledgeId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PledgeeRight, err)
+	}
+
+	err = ledgermanager.DeleteState(DocType_PledgeeRight, rightKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pledgedBalance, exist, err := getPledgedBalance(ctx, pledge.Holder, pledge.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		delete(pledgedBalance.Pledges, pledge.PledgeId)
+
+		err = putPledgedBalance(ctx, *pledgedBalance, exist)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &pledge, nil
+}
+
+func getPledgedBalance(ctx contractapi.TransactionContextInterface, holder string, partition string) (*PledgedBalanceStruct, bool, error) {
+
+	pledgedKey, err := ctx.GetStub().CreateCompositeKey(DocType_PledgedBalance, []string{holder, partition})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PledgedBalance, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(pledgedKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !exist {
+		return &PledgedBalanceStruct{DocType: DocType_PledgedBalance, Holder: holder, Partition: partition, Pledges: map[string]int64{}}, false, nil
+	}
+
+	pledgedBytes, err := ledgermanager.GetState(DocType_PledgedBalance, pledgedKey, ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pledgedBalance := PledgedBalanceStruct{}
+	if err := json.Unmarshal(pledgedBytes, &pledgedBalance); err != nil {
+		return nil, false, err
+	}
+
+	if pledgedBalance.Pledges == nil {
+		pledgedBalance.Pledges = map[string]int64{}
+	}
+
+	return &pledgedBalance, true, nil
+}
+
+func putPledgedBalance(ctx contractapi.TransactionContextInterface, pledgedBalance PledgedBalanceStruct, exist bool) error {
+
+	pledgedKey, err := ctx.GetStub().CreateCompositeKey(DocType_PledgedBalance, []string{pledgedBalance.Holder, pledgedBalance.Partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PledgedBalance, err)
+	}
+
+	if exist {
+		pledgedToMap, err := ccutils.StructToMap(pledgedBalance)
+		if err != nil {
+			return err
+		}
+
+		return ledgermanager.UpdateState(DocType_PledgedBalance, pledgedKey, pledgedToMap, ctx)
+	}
+
+	_, err = ledgermanager.PutState(DocType_PledgedBalance, pledgedKey, pledgedBalance, ctx)
+	return err
+}
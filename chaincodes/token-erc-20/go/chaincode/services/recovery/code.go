@@ -0,0 +1,11 @@
+package recovery
+
+const CodeErrorRequestNotPending int = 650
+const CodeErrorRequestAlreadyPending int = 651
+const CodeErrorWalletEncumbered int = 652
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorRequestNotPending:     "Recovery error : request is not pending",
+	CodeErrorRequestAlreadyPending: "Recovery error : a pending request already exists for the wallet",
+	CodeErrorWalletEncumbered:      "Recovery error : old wallet still has encumbered tokens",
+}
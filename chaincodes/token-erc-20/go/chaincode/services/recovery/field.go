@@ -0,0 +1,8 @@
+package recovery
+
+const (
+	FieldRequestId  string = "requestId"
+	FieldOldAddress string = "oldAddress"
+	FieldNewAddress string = "newAddress"
+	FieldStatus     string = "status"
+)
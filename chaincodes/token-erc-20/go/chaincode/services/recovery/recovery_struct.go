@@ -0,0 +1,32 @@
+package recovery
+
+const (
+	DocType_RecoveryRequest = "DOCTYPE_RECOVERYREQUEST"
+)
+
+// Recovery request status
+const (
+	StatusPending  = "pending"
+	StatusExecuted = "executed"
+	StatusRejected = "rejected"
+)
+
+// 분실한 identity 의 wallet(old) 을 새 identity 의 wallet(new) 으로 이전 요청
+type RecoveryRequestStruct struct {
+	DocType string `json:"docType"`
+
+	RequestId  string `json:"requestId"`
+	OldAddress string `json:"oldAddress"`
+	NewAddress string `json:"newAddress"`
+
+	// 요청한 새 identity
+	MSPID   string `json:"mspId"`
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer"`
+
+	Status string `json:"status"`
+
+	RequestedAt int64  `json:"requestedAt"`
+	ClosedAt    int64  `json:"closedAt"`
+	ClosedBy    string `json:"closedBy"`
+}
@@ -0,0 +1,135 @@
+package recovery
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// CreateRequest old wallet 당 pending 요청은 하나만 허용
+func CreateRequest(ctx contractapi.TransactionContextInterface, request RecoveryRequestStruct) (*RecoveryRequestStruct, error) {
+
+	if request.OldAddress == request.NewAddress {
+		return nil, fmt.Errorf("old and new wallet must be different")
+	}
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_RecoveryRequest)
+	queryBuilder.AddSelectorGroup(FieldOldAddress, request.OldAddress)
+	queryBuilder.AddSelectorGroup(FieldStatus, StatusPending)
+
+	iterator, err := ctx.GetStub().GetQueryResult(queryBuilder.MakeQueryString())
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	if iterator.HasNext() {
+		return nil, ccutils.CreateError(CodeErrorRequestAlreadyPending, fmt.Errorf(ErrorCodeMessage[CodeErrorRequestAlreadyPending]+" : "+request.OldAddress))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request.RequestId = ctx.GetStub().GetTxID()
+	request.Status = StatusPending
+	request.RequestedAt = now
+
+	requestKey, err := ctx.GetStub().CreateCompositeKey(DocType_RecoveryRequest, []string{request.RequestId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_RecoveryRequest, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_RecoveryRequest, requestKey, request, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+func GetRequest(ctx contractapi.TransactionContextInterface, requestId string) (*RecoveryRequestStruct, error) {
+
+	requestKey, err := ctx.GetStub().CreateCompositeKey(DocType_RecoveryRequest, []string{requestId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_RecoveryRequest, err)
+	}
+
+	requestBytes, err := ledgermanager.GetState(DocType_RecoveryRequest, requestKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := RecoveryRequestStruct{}
+	if err := json.Unmarshal(requestBytes, &request); err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+func CloseRequest(ctx contractapi.TransactionContextInterface, request RecoveryRequestStruct, status string, closedBy string) (*RecoveryRequestStruct, error) {
+
+	if request.Status != StatusPending {
+		return nil, ccutils.CreateError(CodeErrorRequestNotPending, fmt.Errorf(ErrorCodeMessage[CodeErrorRequestNotPending]+" : "+request.RequestId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Status = status
+	request.ClosedAt = now
+	request.ClosedBy = closedBy
+
+	requestKey, err := ctx.GetStub().CreateCompositeKey(DocType_RecoveryRequest, []string{request.RequestId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_RecoveryRequest, err)
+	}
+
+	requestToMap, err := ccutils.StructToMap(request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_RecoveryRequest, requestKey, requestToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+func GetRequestList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_RecoveryRequest)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldOldAddress, FieldNewAddress, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
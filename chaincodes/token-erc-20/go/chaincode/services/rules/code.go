@@ -0,0 +1,11 @@
+package rules
+
+const CodeErrorUnknownRuleType int = 700
+const CodeErrorInvalidRuleParams int = 701
+
+// 각 rule module 은 710 부터 자신의 package 에 reason code 를 정의
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorUnknownRuleType:   "Rule error : unknown rule type",
+	CodeErrorInvalidRuleParams: "Rule error : invalid rule parameters",
+}
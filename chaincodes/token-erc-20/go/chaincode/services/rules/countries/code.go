@@ -0,0 +1,7 @@
+package countries
+
+const CodeErrorCountryNotAllowed int = 714
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorCountryNotAllowed: "Rule error : investor country is not allowed",
+}
@@ -0,0 +1,57 @@
+package countries
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/kyc"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+)
+
+const (
+	Type = "allowedCountries"
+
+	// KYC 등록 정보의 countryCode 목록
+	ParamCountries = "countries"
+)
+
+type Module struct{}
+
+func (Module) Type() string {
+	return Type
+}
+
+func (Module) ValidateParams(params map[string]interface{}) error {
+	_, err := rules.ParamStringArray(params, ParamCountries)
+	return err
+}
+
+// 수령인의 KYC 국가 코드가 목록에 있어야 함. KYC 정보가 없으면 거부
+func (Module) Check(ctx contractapi.TransactionContextInterface, params map[string]interface{}, check rules.CheckStruct) error {
+
+	if check.Operation == rules.OperationRedemption {
+		return nil
+	}
+
+	countries, err := rules.ParamStringArray(params, ParamCountries)
+	if err != nil {
+		return err
+	}
+
+	investor, err := kyc.GetInvestor(ctx, check.To)
+	if err != nil {
+		return err
+	}
+
+	if investor != nil {
+		for _, country := range countries {
+			if investor.CountryCode == country {
+				return nil
+			}
+		}
+	}
+
+	return ccutils.CreateError(CodeErrorCountryNotAllowed, fmt.Errorf(ErrorCodeMessage[CodeErrorCountryNotAllowed]+" : "+check.To))
+}
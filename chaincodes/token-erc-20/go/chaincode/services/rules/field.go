@@ -0,0 +1,13 @@
+package rules
+
+const (
+	FieldPartition string = "partition"
+	FieldRules     string = "rules"
+	FieldType      string = "type"
+	FieldParams    string = "params"
+	FieldRuleTypes string = "ruleTypes"
+	FieldOperation string = "operation"
+	FieldFrom      string = "from"
+	FieldTo        string = "to"
+	FieldAmount    string = "amount"
+)
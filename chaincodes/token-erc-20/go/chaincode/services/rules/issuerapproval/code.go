@@ -0,0 +1,7 @@
+package issuerapproval
+
+const CodeErrorIssuerApprovalRequired int = 715
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorIssuerApprovalRequired: "Rule error : transfer requires issuer approval",
+}
@@ -0,0 +1,34 @@
+package issuerapproval
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+)
+
+const (
+	Type = "issuerApproval"
+)
+
+type Module struct{}
+
+func (Module) Type() string {
+	return Type
+}
+
+func (Module) ValidateParams(params map[string]interface{}) error {
+	return nil
+}
+
+// 2차 거래는 발행사 승인 없이 직접 이전할 수 없음. RequestTransferByPartition 으로 요청 후 승인받아야 함
+func (Module) Check(ctx contractapi.TransactionContextInterface, params map[string]interface{}, check rules.CheckStruct) error {
+
+	if check.Operation != rules.OperationTransfer || check.Approved {
+		return nil
+	}
+
+	return ccutils.CreateError(CodeErrorIssuerApprovalRequired, fmt.Errorf(ErrorCodeMessage[CodeErrorIssuerApprovalRequired]+" : "+check.Partition))
+}
@@ -0,0 +1,7 @@
+package lockup
+
+const CodeErrorLockupActive int = 710
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorLockupActive: "Rule error : partition is in the lock-up period",
+}
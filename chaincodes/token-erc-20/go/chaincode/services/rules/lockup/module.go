@@ -0,0 +1,51 @@
+package lockup
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+)
+
+const (
+	Type = "lockup"
+
+	// unix seconds. 이 시각 전에는 이전/상환 불가
+	ParamReleaseAt = "releaseAt"
+)
+
+type Module struct{}
+
+func (Module) Type() string {
+	return Type
+}
+
+func (Module) ValidateParams(params map[string]interface{}) error {
+	_, err := rules.ParamInt64(params, ParamReleaseAt)
+	return err
+}
+
+func (Module) Check(ctx contractapi.TransactionContextInterface, params map[string]interface{}, check rules.CheckStruct) error {
+
+	if check.Operation == rules.OperationIssuance {
+		return nil
+	}
+
+	releaseAt, err := rules.ParamInt64(params, ParamReleaseAt)
+	if err != nil {
+		return err
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if now < releaseAt {
+		return ccutils.CreateError(CodeErrorLockupActive, fmt.Errorf(ErrorCodeMessage[CodeErrorLockupActive]+" : released at %d", releaseAt))
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+package maxholders
+
+const CodeErrorMaxHoldersExceeded int = 711
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorMaxHoldersExceeded: "Rule error : maximum number of holders exceeded",
+}
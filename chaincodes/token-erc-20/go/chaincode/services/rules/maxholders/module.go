@@ -0,0 +1,90 @@
+package maxholders
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/holders"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+)
+
+const (
+	Type = "maxHolders"
+
+	ParamMaxHolders = "maxHolders"
+)
+
+type Module struct{}
+
+func (Module) Type() string {
+	return Type
+}
+
+func (Module) ValidateParams(params map[string]interface{}) error {
+	maxHolders, err := rules.ParamInt64(params, ParamMaxHolders)
+	if err != nil {
+		return err
+	}
+	if maxHolders <= 0 {
+		return ccutils.CreateError(rules.CodeErrorInvalidRuleParams, fmt.Errorf(rules.ErrorCodeMessage[rules.CodeErrorInvalidRuleParams]+" : %s must be positive", ParamMaxHolders))
+	}
+	return nil
+}
+
+// 수령인이 새 보유자가 되는 경우에만 보유자 수 확인
+func (Module) Check(ctx contractapi.TransactionContextInterface, params map[string]interface{}, check rules.CheckStruct) error {
+
+	if check.Operation == rules.OperationRedemption {
+		return nil
+	}
+
+	maxHolders, err := rules.ParamInt64(params, ParamMaxHolders)
+	if err != nil {
+		return err
+	}
+
+	toBalance, err := token.BalanceOfByPartitionOrZero(ctx, check.To, check.Partition)
+	if err != nil {
+		return err
+	}
+
+	if toBalance+check.PendingCredit > 0 {
+		return nil
+	}
+
+	holders, err := holderCount(ctx, check.Partition)
+	if err != nil {
+		return err
+	}
+	holders += check.PendingHolders
+
+	// 보내는 쪽이 전량 이전하면 보유자 수는 그대로. escrow 에서 나가는 경우 보내는 쪽 잔고는 그대로
+	if check.Operation == rules.OperationTransfer && !check.Escrowed {
+		fromBalance, err := token.BalanceOfByPartition(ctx, check.From, check.Partition)
+		if err != nil {
+			return err
+		}
+		if fromBalance-check.PendingDebit == check.Amount {
+			holders--
+		}
+	}
+
+	if holders+1 > maxHolders {
+		return ccutils.CreateError(CodeErrorMaxHoldersExceeded, fmt.Errorf(ErrorCodeMessage[CodeErrorMaxHoldersExceeded]+" : max %d", maxHolders))
+	}
+
+	return nil
+}
+
+func holderCount(ctx contractapi.TransactionContextInterface, partition string) (int64, error) {
+
+	holderCount, err := holders.GetHolderCount(ctx, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	return holderCount.Count, nil
+}
@@ -0,0 +1,7 @@
+package maxholding
+
+const CodeErrorMaxHoldingExceeded int = 712
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorMaxHoldingExceeded: "Rule error : maximum holding per investor exceeded",
+}
@@ -0,0 +1,59 @@
+package maxholding
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+)
+
+const (
+	Type = "maxHolding"
+
+	ParamMaxHolding = "maxHolding"
+)
+
+type Module struct{}
+
+func (Module) Type() string {
+	return Type
+}
+
+func (Module) ValidateParams(params map[string]interface{}) error {
+	maxHolding, err := rules.ParamInt64(params, ParamMaxHolding)
+	if err != nil {
+		return err
+	}
+	if maxHolding <= 0 {
+		return ccutils.CreateError(rules.CodeErrorInvalidRuleParams, fmt.Errorf(rules.ErrorCodeMessage[rules.CodeErrorInvalidRuleParams]+" : %s must be positive", ParamMaxHolding))
+	}
+	return nil
+}
+
+func (Module) Check(ctx contractapi.TransactionContextInterface, params map[string]interface{}, check rules.CheckStruct) error {
+
+	if check.Operation == rules.OperationRedemption {
+		return nil
+	}
+
+	maxHolding, err := rules.ParamInt64(params, ParamMaxHolding)
+	if err != nil {
+		return err
+	}
+
+	balance, err := token.BalanceOfByPartitionOrZero(ctx, check.To, check.Partition)
+	if err != nil {
+		return err
+	}
+
+	balance += check.PendingCredit
+
+	if balance+check.Amount > maxHolding {
+		return ccutils.CreateError(CodeErrorMaxHoldingExceeded, fmt.Errorf(ErrorCodeMessage[CodeErrorMaxHoldingExceeded]+" : balance %d, amount %d, max %d", balance, check.Amount, maxHolding))
+	}
+
+	return nil
+}
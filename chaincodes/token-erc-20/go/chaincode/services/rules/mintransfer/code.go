@@ -0,0 +1,7 @@
+package mintransfer
+
+const CodeErrorBelowMinTransfer int = 713
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorBelowMinTransfer: "Rule error : amount is below the minimum transfer size",
+}
@@ -0,0 +1,46 @@
+package mintransfer
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/rules"
+)
+
+const (
+	Type = "minTransfer"
+
+	ParamMinAmount = "minAmount"
+)
+
+type Module struct{}
+
+func (Module) Type() string {
+	return Type
+}
+
+func (Module) ValidateParams(params map[string]interface{}) error {
+	_, err := rules.ParamInt64(params, ParamMinAmount)
+	return err
+}
+
+// 2차 거래(transfer)에만 적용
+func (Module) Check(ctx contractapi.TransactionContextInterface, params map[string]interface{}, check rules.CheckStruct) error {
+
+	if check.Operation != rules.OperationTransfer {
+		return nil
+	}
+
+	minAmount, err := rules.ParamInt64(params, ParamMinAmount)
+	if err != nil {
+		return err
+	}
+
+	if check.Amount < minAmount {
+		return ccutils.CreateError(CodeErrorBelowMinTransfer, fmt.Errorf(ErrorCodeMessage[CodeErrorBelowMinTransfer]+" : amount %d, min %d", check.Amount, minAmount))
+	}
+
+	return nil
+}
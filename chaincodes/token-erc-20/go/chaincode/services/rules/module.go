@@ -0,0 +1,72 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+)
+
+// Module ERC-3643 compliance module 과 같은 역할.
+// 새 rule 은 별도 package 에서 이 interface 를 구현하고 Register 로 등록
+type Module interface {
+	// Type partition rule 설정에서 사용하는 이름
+	Type() string
+	// ValidateParams rule 설정 시 파라미터 확인
+	ValidateParams(params map[string]interface{}) error
+	// Check 잔고 변동이 허용되지 않으면 reason code 를 담은 error 반환
+	Check(ctx contractapi.TransactionContextInterface, params map[string]interface{}, check CheckStruct) error
+}
+
+var modules = map[string]Module{}
+
+func Register(newModules ...Module) {
+	for _, module := range newModules {
+		modules[module.Type()] = module
+	}
+}
+
+func GetModule(ruleType string) (Module, error) {
+	module, exist := modules[ruleType]
+	if !exist {
+		return nil, ccutils.CreateError(CodeErrorUnknownRuleType, fmt.Errorf(ErrorCodeMessage[CodeErrorUnknownRuleType]+" : "+ruleType))
+	}
+	return module, nil
+}
+
+func ModuleTypes() []string {
+	types := make([]string, 0, len(modules))
+	for ruleType := range modules {
+		types = append(types, ruleType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ParamInt64 json 숫자(float64)로 들어온 파라미터를 int64 로 읽음
+func ParamInt64(params map[string]interface{}, name string) (int64, error) {
+	value, ok := params[name].(float64)
+	if !ok || value != float64(int64(value)) {
+		return 0, ccutils.CreateError(CodeErrorInvalidRuleParams, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidRuleParams]+" : %s must be an integer", name))
+	}
+	return int64(value), nil
+}
+
+func ParamStringArray(params map[string]interface{}, name string) ([]string, error) {
+	values, ok := params[name].([]interface{})
+	if !ok {
+		return nil, ccutils.CreateError(CodeErrorInvalidRuleParams, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidRuleParams]+" : %s must be an array of string", name))
+	}
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil, ccutils.CreateError(CodeErrorInvalidRuleParams, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidRuleParams]+" : %s must be an array of string", name))
+		}
+		result = append(result, str)
+	}
+	return result, nil
+}
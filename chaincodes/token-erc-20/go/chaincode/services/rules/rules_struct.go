@@ -0,0 +1,47 @@
+package rules
+
+const (
+	DocType_PartitionRules = "DOCTYPE_PARTITIONRULES"
+)
+
+// rule 평가 대상 operation
+const (
+	OperationIssuance   = "issuance"
+	OperationTransfer   = "transfer"
+	OperationRedemption = "redemption"
+)
+
+type RuleConfig struct {
+	Type   string                 `json:"type"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// partition 에 설정된 rule 목록. 배열 순서대로 평가
+type PartitionRulesStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string       `json:"partition"`
+	Rules     []RuleConfig `json:"rules"`
+
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// 평가할 잔고 변동. issuance 는 From 이, redemption 은 To 가 비어 있음
+type CheckStruct struct {
+	Operation string `json:"operation"`
+	Partition string `json:"partition"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Amount    int64  `json:"amount"`
+	// 발행사(또는 위임 승인자)가 승인한 pending transfer 실행 여부
+	Approved bool `json:"approved"`
+
+	// 같은 트랜잭션에서 앞서 통과했지만 아직 잔고에 반영되지 않은 변동.
+	// To 가 이미 받기로 한 수량, From 이 이미 내보내기로 한 수량, 그로 인한 보유자 수 증감
+	PendingCredit  int64 `json:"pendingCredit"`
+	PendingDebit   int64 `json:"pendingDebit"`
+	PendingHolders int64 `json:"pendingHolders"`
+	// From 의 수량이 이미 escrow 에 있어 이번 변동으로 From 의 잔고가 바뀌지 않음
+	Escrowed bool `json:"escrowed"`
+}
@@ -0,0 +1,123 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// SetRules partition 의 rule 목록 전체를 교체
+func SetRules(ctx contractapi.TransactionContextInterface, partitionRules PartitionRulesStruct) (*PartitionRulesStruct, error) {
+
+	if partitionRules.Rules == nil {
+		partitionRules.Rules = []RuleConfig{}
+	}
+
+	for i, rule := range partitionRules.Rules {
+		module, err := GetModule(rule.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule.Params == nil {
+			partitionRules.Rules[i].Params = map[string]interface{}{}
+		}
+
+		err = module.ValidateParams(partitionRules.Rules[i].Params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+	partitionRules.UpdatedAt = now
+
+	rulesKey, err := ctx.GetStub().CreateCompositeKey(DocType_PartitionRules, []string{partitionRules.Partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PartitionRules, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(rulesKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		rulesToMap, err := ccutils.StructToMap(partitionRules)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_PartitionRules, rulesKey, rulesToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_PartitionRules, rulesKey, partitionRules, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &partitionRules, nil
+}
+
+// GetRules 설정이 없으면 빈 rule 목록
+func GetRules(ctx contractapi.TransactionContextInterface, partition string) (*PartitionRulesStruct, error) {
+
+	rulesKey, err := ctx.GetStub().CreateCompositeKey(DocType_PartitionRules, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PartitionRules, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(rulesKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &PartitionRulesStruct{DocType: DocType_PartitionRules, Partition: partition, Rules: []RuleConfig{}}, nil
+	}
+
+	rulesBytes, err := ledgermanager.GetState(DocType_PartitionRules, rulesKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	partitionRules := PartitionRulesStruct{}
+	if err := json.Unmarshal(rulesBytes, &partitionRules); err != nil {
+		return nil, err
+	}
+
+	return &partitionRules, nil
+}
+
+// Evaluate partition 의 rule 을 순서대로 평가. 처음 실패한 rule 의 error 반환
+func Evaluate(ctx contractapi.TransactionContextInterface, check CheckStruct) error {
+
+	partitionRules, err := GetRules(ctx, check.Partition)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range partitionRules.Rules {
+		module, err := GetModule(rule.Type)
+		if err != nil {
+			return err
+		}
+
+		err = module.Check(ctx, rule.Params, check)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
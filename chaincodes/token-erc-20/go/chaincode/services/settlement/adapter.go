@@ -0,0 +1,171 @@
+package settlement
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/pause"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// Adapter cash leg 의 입출금. Debit 은 from 에서 결제 계정으로, Credit 은 결제 계정에서 수령인들에게 한 번에 이전.
+// 같은 tx 에서 결제 계정을 두 번 쓰지 않도록 Credit 은 batch 로만 호출
+type Adapter interface {
+	Debit(ctx contractapi.TransactionContextInterface, from string, amount int64) error
+	Credit(ctx contractapi.TransactionContextInterface, payments []PaymentStruct) error
+}
+
+// DebitGuard 출금 제한 확인. in-process adapter 는 controller 의 transfer guard 를 주입받음
+type DebitGuard func(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64) error
+
+// PartitionAdapter 이 chaincode 의 다른 partition 을 cash 로 사용하는 in-process 구현
+type PartitionAdapter struct {
+	CashPartition string
+	Escrow        string
+	Guard         DebitGuard
+}
+
+func (a PartitionAdapter) Debit(ctx contractapi.TransactionContextInterface, from string, amount int64) error {
+
+	err := pause.CheckNotPaused(ctx, a.CashPartition, pause.OperationTransfer)
+	if err != nil {
+		return err
+	}
+
+	if a.Guard != nil {
+		err = a.Guard(ctx, from, a.CashPartition, amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	transfers := []token.TransferByPartitionStruct{{From: from, To: a.Escrow, Partition: a.CashPartition, Amount: amount}}
+	return wallet.TransferBatchByPartition(ctx, from, a.CashPartition, transfers)
+}
+
+func (a PartitionAdapter) Credit(ctx contractapi.TransactionContextInterface, payments []PaymentStruct) error {
+
+	if len(payments) == 0 {
+		return nil
+	}
+
+	err := pause.CheckNotPaused(ctx, a.CashPartition, pause.OperationTransfer)
+	if err != nil {
+		return err
+	}
+
+	transfers := []token.TransferByPartitionStruct{}
+	for _, payment := range payments {
+		transfers = append(transfers, token.TransferByPartitionStruct{From: a.Escrow, To: payment.To, Partition: a.CashPartition, Amount: payment.Amount})
+	}
+
+	return wallet.TransferBatchByPartition(ctx, a.Escrow, a.CashPartition, transfers)
+}
+
+// ChaincodeAdapter 같은 channel 의 외부 token chaincode 를 InvokeChaincode 로 호출하는 구현.
+// 외부 chaincode 는 이 tx 의 제출자 identity 로 실행되므로 Debit 의 from 은 제출자 본인이어야 하고,
+// Account 에서의 Credit 은 Account 를 관리하는 발행사/operator 가 제출한 push 지급만 가능
+type ChaincodeAdapter struct {
+	ChaincodeName  string
+	Channel        string
+	Account        string
+	CashPartition  string
+	DebitFunction  string
+	CreditFunction string
+	Submitter      string
+	Pusher         bool
+}
+
+func (a ChaincodeAdapter) Debit(ctx contractapi.TransactionContextInterface, from string, amount int64) error {
+
+	if from != a.Submitter {
+		return ccutils.CreateError(CodeErrorDebitNotSubmitter, fmt.Errorf(ErrorCodeMessage[CodeErrorDebitNotSubmitter]+" : from %s, submitter %s", from, a.Submitter))
+	}
+
+	args := map[string]interface{}{
+		FieldRecipient: a.Account,
+		FieldPartition: a.CashPartition,
+		FieldAmount:    amount,
+	}
+
+	return a.invoke(ctx, a.DebitFunction, args)
+}
+
+// Credit 외부 chaincode 안에서도 Account 를 한 번만 쓰도록 수령인을 모아 한 번 호출
+func (a ChaincodeAdapter) Credit(ctx contractapi.TransactionContextInterface, payments []PaymentStruct) error {
+
+	if len(payments) == 0 {
+		return nil
+	}
+
+	// holder 가 직접 제출한 claim / refund / 상환 대금 수령은 Account 로 서명할 수 없음
+	if !a.Pusher {
+		return ccutils.CreateError(CodeErrorCreditNotPushed, fmt.Errorf(ErrorCodeMessage[CodeErrorCreditNotPushed]+" : submitter %s", a.Submitter))
+	}
+
+	recipients := make(map[string]interface{})
+	for _, payment := range payments {
+		if _, exist := recipients[payment.To]; exist {
+			return fmt.Errorf("duplicated recipient in payments : %s", payment.To)
+		}
+		recipients[payment.To] = payment.Amount
+	}
+
+	args := map[string]interface{}{
+		FieldFrom:       a.Account,
+		FieldPartition:  a.CashPartition,
+		FieldRecipients: recipients,
+	}
+
+	return a.invoke(ctx, a.CreditFunction, args)
+}
+
+// invoke 외부 chaincode 호출 후 peer 응답 status 와 ccutils.Response code 를 모두 확인
+func (a ChaincodeAdapter) invoke(ctx contractapi.TransactionContextInterface, function string, args map[string]interface{}) error {
+
+	argsBytes, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	response := ctx.GetStub().InvokeChaincode(a.ChaincodeName, [][]byte{[]byte(function), argsBytes}, a.Channel)
+	if response.Status != shim.OK {
+		return ccutils.CreateError(CodeErrorExternalCallFailed, fmt.Errorf(ErrorCodeMessage[CodeErrorExternalCallFailed]+" : %s %s status %d, %s", a.ChaincodeName, function, response.Status, response.Message))
+	}
+
+	result := ccutils.Response{}
+	if err := json.Unmarshal(response.Payload, &result); err != nil {
+		return ccutils.CreateError(CodeErrorExternalCallFailed, fmt.Errorf(ErrorCodeMessage[CodeErrorExternalCallFailed]+" : %s %s invalid response, %v", a.ChaincodeName, function, err))
+	}
+
+	if result.Code != ccutils.ChaincodeSuccess {
+		return ccutils.CreateError(CodeErrorExternalRejected, fmt.Errorf(ErrorCodeMessage[CodeErrorExternalRejected]+" : %s %s code %d, %s", a.ChaincodeName, function, result.Code, result.Message))
+	}
+
+	return nil
+}
+
+// NewAdapter config 가 chaincode mode 면 외부 chaincode adapter, 아니면 cashPartition/escrow 의 in-process adapter.
+// config 의 Partition 이 아닌 flow 별 cash partition 을 사용. submitter / pusher 는 chaincode mode 에서만 사용
+func NewAdapter(config *SettlementConfigStruct, cashPartition string, escrow string, guard DebitGuard, submitter string, pusher bool) Adapter {
+
+	if config != nil && config.Mode == ModeChaincode {
+		return ChaincodeAdapter{
+			ChaincodeName:  config.ChaincodeName,
+			Channel:        config.Channel,
+			Account:        config.Account,
+			CashPartition:  cashPartition,
+			DebitFunction:  config.DebitFunction,
+			CreditFunction: config.CreditFunction,
+			Submitter:      submitter,
+			Pusher:         pusher,
+		}
+	}
+
+	return PartitionAdapter{CashPartition: cashPartition, Escrow: escrow, Guard: guard}
+}
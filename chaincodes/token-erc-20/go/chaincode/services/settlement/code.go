@@ -0,0 +1,19 @@
+package settlement
+
+const CodeErrorExternalCallFailed int = 840
+const CodeErrorExternalRejected int = 841
+const CodeErrorInsufficientRedemptionFunds int = 842
+const CodeErrorPayoutNotPending int = 843
+const CodeErrorOpenFlows int = 844
+const CodeErrorDebitNotSubmitter int = 845
+const CodeErrorCreditNotPushed int = 846
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorExternalCallFailed:          "Settlement error : external token chaincode call failed",
+	CodeErrorExternalRejected:            "Settlement error : external token chaincode rejected the request",
+	CodeErrorInsufficientRedemptionFunds: "Settlement error : insufficient redemption funds",
+	CodeErrorPayoutNotPending:            "Settlement error : redemption payout is not pending",
+	CodeErrorOpenFlows:                   "Settlement error : settlement routing cannot change while offerings, dividends or redemption payouts are open",
+	CodeErrorDebitNotSubmitter:           "Settlement error : chaincode mode debit must come from the submitter",
+	CodeErrorCreditNotPushed:             "Settlement error : chaincode mode payments must be pushed by the issuer or an operator",
+}
@@ -0,0 +1,22 @@
+package settlement
+
+const (
+	FieldPartition       string = "partition"
+	FieldMode            string = "mode"
+	FieldCashPartition   string = "cashPartition"
+	FieldChaincodeName   string = "chaincodeName"
+	FieldChannel         string = "channel"
+	FieldAccount         string = "account"
+	FieldDebitFunction   string = "debitFunction"
+	FieldCreditFunction  string = "creditFunction"
+	FieldRedemptionPrice string = "redemptionPrice"
+	FieldAmount          string = "amount"
+	FieldPayoutId        string = "payoutId"
+	FieldHolder          string = "holder"
+	FieldStatus          string = "status"
+
+	// 외부 token chaincode 호출 인자 (이 chaincode 의 TransferByPartition / OperatorTransferBatchByPartition 형식)
+	FieldRecipient  string = "recipient"
+	FieldFrom       string = "from"
+	FieldRecipients string = "recipients"
+)
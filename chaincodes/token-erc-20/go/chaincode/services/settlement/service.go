@@ -0,0 +1,304 @@
+package settlement
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// SetConfig partition 의 결제 방식 저장. 적립된 상환 재원은 유지
+func SetConfig(ctx contractapi.TransactionContextInterface, config SettlementConfigStruct) (*SettlementConfigStruct, error) {
+
+	switch config.Mode {
+	case ModeInternal:
+		config.ChaincodeName = ""
+		config.Channel = ""
+		config.Account = ""
+		config.DebitFunction = ""
+		config.CreditFunction = ""
+	case ModeChaincode:
+		if config.ChaincodeName == "" || config.Account == "" {
+			return nil, fmt.Errorf("chaincode name and account are required for mode %s", ModeChaincode)
+		}
+		if config.DebitFunction == "" {
+			config.DebitFunction = DefaultDebitFunction
+		}
+		if config.CreditFunction == "" {
+			config.CreditFunction = DefaultCreditFunction
+		}
+	default:
+		return nil, fmt.Errorf("unknown settlement mode : %s", config.Mode)
+	}
+
+	if config.RedemptionPrice < 0 {
+		return nil, fmt.Errorf("invalid redemption price : %d", config.RedemptionPrice)
+	}
+
+	if config.RedemptionPrice > 0 && config.CashPartition == "" {
+		return nil, fmt.Errorf("cash partition is required for redemption price")
+	}
+
+	if config.Partition == config.CashPartition && config.Mode == ModeInternal {
+		return nil, fmt.Errorf("cash partition must be different from the partition : %s", config.Partition)
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := GetConfig(ctx, config.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if existing.RedemptionFunds > 0 && (existing.Mode != config.Mode || existing.CashPartition != config.CashPartition) {
+			return nil, fmt.Errorf("redemption funds %d remain in %s %s", existing.RedemptionFunds, existing.Mode, existing.CashPartition)
+		}
+		config.RedemptionFunds = existing.RedemptionFunds
+	}
+
+	config.DocType = DocType_SettlementConfig
+	config.UpdatedAt = now
+
+	err = putConfig(ctx, config, existing != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// RoutingChanged cash leg 이 오가는 곳(mode, 외부 chaincode, 계정, 함수)이 바뀌는지. 설정이 없으면 internal
+func RoutingChanged(existing *SettlementConfigStruct, config SettlementConfigStruct) bool {
+
+	current := SettlementConfigStruct{Mode: ModeInternal}
+	if existing != nil {
+		current = *existing
+	}
+
+	if current.Mode != config.Mode {
+		return true
+	}
+
+	if config.Mode != ModeChaincode {
+		return false
+	}
+
+	if config.DebitFunction == "" {
+		config.DebitFunction = DefaultDebitFunction
+	}
+	if config.CreditFunction == "" {
+		config.CreditFunction = DefaultCreditFunction
+	}
+
+	return current.ChaincodeName != config.ChaincodeName || current.Channel != config.Channel || current.Account != config.Account ||
+		current.DebitFunction != config.DebitFunction || current.CreditFunction != config.CreditFunction
+}
+
+// GetConfig 설정이 없으면 nil
+func GetConfig(ctx contractapi.TransactionContextInterface, partition string) (*SettlementConfigStruct, error) {
+
+	configKey, err := ctx.GetStub().CreateCompositeKey(DocType_SettlementConfig, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_SettlementConfig, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(configKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	configBytes, err := ledgermanager.GetState(DocType_SettlementConfig, configKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := SettlementConfigStruct{}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// AddRedemptionFunds 상환 재원 적립 기록. 결제 계정으로의 이전은 controller 에서 처리
+func AddRedemptionFunds(ctx contractapi.TransactionContextInterface, config SettlementConfigStruct, amount int64) (*SettlementConfigStruct, error) {
+
+	if amount <= 0 {
+		return nil, fmt.Errorf("invalid amount : %d", amount)
+	}
+
+	config.RedemptionFunds += amount
+
+	err := putConfig(ctx, config, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// CreatePayout 상환 수량만큼의 대금을 재원에서 떼어 지급 대기로 기록
+func CreatePayout(ctx contractapi.TransactionContextInterface, config SettlementConfigStruct, holder string, redeemed int64) (*RedemptionPayoutStruct, error) {
+
+	amount := redeemed * config.RedemptionPrice
+	if amount > config.RedemptionFunds {
+		return nil, ccutils.CreateError(CodeErrorInsufficientRedemptionFunds, fmt.Errorf(ErrorCodeMessage[CodeErrorInsufficientRedemptionFunds]+" : funds %d, required %d", config.RedemptionFunds, amount))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config.RedemptionFunds -= amount
+	err = putConfig(ctx, config, true)
+	if err != nil {
+		return nil, err
+	}
+
+	payout := RedemptionPayoutStruct{
+		PayoutId:      ctx.GetStub().GetTxID(),
+		Partition:     config.Partition,
+		Holder:        holder,
+		Redeemed:      redeemed,
+		CashPartition: config.CashPartition,
+		Amount:        amount,
+		Status:        PayoutPending,
+		RedeemedAt:    now,
+	}
+
+	payoutKey, err := ctx.GetStub().CreateCompositeKey(DocType_RedemptionPayout, []string{payout.PayoutId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_RedemptionPayout, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_RedemptionPayout, payoutKey, payout, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payout, nil
+}
+
+func GetPayout(ctx contractapi.TransactionContextInterface, payoutId string) (*RedemptionPayoutStruct, error) {
+
+	payoutKey, err := ctx.GetStub().CreateCompositeKey(DocType_RedemptionPayout, []string{payoutId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_RedemptionPayout, err)
+	}
+
+	payoutBytes, err := ledgermanager.GetState(DocType_RedemptionPayout, payoutKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payout := RedemptionPayoutStruct{}
+	if err := json.Unmarshal(payoutBytes, &payout); err != nil {
+		return nil, err
+	}
+
+	return &payout, nil
+}
+
+// MarkPaid 지급 완료 기록. 실제 지급은 controller 에서 adapter 로 처리
+func MarkPaid(ctx contractapi.TransactionContextInterface, payout RedemptionPayoutStruct, paidTo string) (*RedemptionPayoutStruct, error) {
+
+	if payout.Status != PayoutPending {
+		return nil, ccutils.CreateError(CodeErrorPayoutNotPending, fmt.Errorf(ErrorCodeMessage[CodeErrorPayoutNotPending]+" : "+payout.PayoutId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payout.Status = PayoutPaid
+	payout.PaidTo = paidTo
+	payout.PaidAt = now
+
+	payoutKey, err := ctx.GetStub().CreateCompositeKey(DocType_RedemptionPayout, []string{payout.PayoutId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_RedemptionPayout, err)
+	}
+
+	payoutToMap, err := ccutils.StructToMap(payout)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_RedemptionPayout, payoutKey, payoutToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payout, nil
+}
+
+// HasPendingPayout partition 을 상환 대상 또는 대금 cash 로 쓰는 미지급 상환 대금이 있는지
+func HasPendingPayout(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_RedemptionPayout)
+	queryBuilder.AddSelectorGroup(FieldStatus, PayoutPending)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldPartition, partition)
+	queryBuilder.AddSelectorArrayGroupCondition("$or", FieldCashPartition, partition)
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetPayoutList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_RedemptionPayout)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldPartition, FieldHolder, FieldStatus}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func putConfig(ctx contractapi.TransactionContextInterface, config SettlementConfigStruct, exist bool) error {
+
+	configKey, err := ctx.GetStub().CreateCompositeKey(DocType_SettlementConfig, []string{config.Partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_SettlementConfig, err)
+	}
+
+	if exist {
+		configToMap, err := ccutils.StructToMap(config)
+		if err != nil {
+			return err
+		}
+
+		return ledgermanager.UpdateState(DocType_SettlementConfig, configKey, configToMap, ctx)
+	}
+
+	_, err = ledgermanager.PutState(DocType_SettlementConfig, configKey, config, ctx)
+	return err
+}
@@ -0,0 +1,244 @@
+package settlement_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/settlement"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/test"
+)
+
+const (
+	partition     = "BOND"
+	cashPartition = "KRW"
+)
+
+func expectErrorCode(t *testing.T, err error, code int) {
+
+	t.Helper()
+	var stackErr *ccutils.ErrorWithStack
+	if !errors.As(err, &stackErr) || stackErr.Code != code {
+		t.Errorf("error = %v, expected code %d", err, code)
+	}
+}
+
+func internalConfig(ledger *test.MockLedger, price int64) *settlement.SettlementConfigStruct {
+
+	ledger.T.Helper()
+	ledger.Tx()
+	config, err := settlement.SetConfig(ledger.Ctx, settlement.SettlementConfigStruct{Partition: partition, Mode: settlement.ModeInternal, CashPartition: cashPartition, RedemptionPrice: price})
+	ledger.Check(err)
+	return config
+}
+
+func TestSetConfigRejectsInvalidConfig(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Tx()
+
+	invalid := []settlement.SettlementConfigStruct{
+		{Partition: partition, Mode: "offchain"},
+		{Partition: partition, Mode: settlement.ModeChaincode, Account: "account"},
+		{Partition: partition, Mode: settlement.ModeInternal, CashPartition: partition},
+		{Partition: partition, Mode: settlement.ModeInternal, RedemptionPrice: 100},
+		{Partition: partition, Mode: settlement.ModeInternal, CashPartition: cashPartition, RedemptionPrice: -1},
+	}
+
+	for _, config := range invalid {
+		if _, err := settlement.SetConfig(ledger.Ctx, config); err == nil {
+			t.Errorf("SetConfig(%+v) should fail", config)
+		}
+	}
+
+	config, err := settlement.SetConfig(ledger.Ctx, settlement.SettlementConfigStruct{Partition: partition, Mode: settlement.ModeChaincode, ChaincodeName: "cash", Account: "account"})
+	ledger.Check(err)
+	if config.DebitFunction != settlement.DefaultDebitFunction || config.CreditFunction != settlement.DefaultCreditFunction {
+		t.Errorf("chaincode functions = %s, %s, expected defaults", config.DebitFunction, config.CreditFunction)
+	}
+}
+
+func TestSetConfigKeepsRedemptionFunds(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	config := internalConfig(ledger, 100)
+
+	ledger.Tx()
+	_, err := settlement.AddRedemptionFunds(ledger.Ctx, *config, 5000)
+	ledger.Check(err)
+
+	// 가격만 바꾸면 재원 유지
+	config = internalConfig(ledger, 200)
+	if config.RedemptionFunds != 5000 {
+		t.Errorf("redemption funds = %d, expected 5000", config.RedemptionFunds)
+	}
+
+	// 재원이 남아 있으면 cash partition 을 바꿀 수 없음
+	ledger.Tx()
+	_, err = settlement.SetConfig(ledger.Ctx, settlement.SettlementConfigStruct{Partition: partition, Mode: settlement.ModeInternal, CashPartition: "USD", RedemptionPrice: 200})
+	if err == nil {
+		t.Error("expected remaining funds error")
+	}
+}
+
+func TestRoutingChanged(t *testing.T) {
+
+	chaincode := settlement.SettlementConfigStruct{Mode: settlement.ModeChaincode, ChaincodeName: "cash", Account: "account", DebitFunction: settlement.DefaultDebitFunction, CreditFunction: settlement.DefaultCreditFunction}
+
+	moved := chaincode
+	moved.Account = "other"
+
+	cases := []struct {
+		existing *settlement.SettlementConfigStruct
+		config   settlement.SettlementConfigStruct
+		expected bool
+	}{
+		{existing: nil, config: settlement.SettlementConfigStruct{Mode: settlement.ModeInternal, RedemptionPrice: 100}, expected: false},
+		{existing: nil, config: chaincode, expected: true},
+		// 기본 함수 이름은 비워 둔 것과 같음
+		{existing: &chaincode, config: settlement.SettlementConfigStruct{Mode: settlement.ModeChaincode, ChaincodeName: "cash", Account: "account"}, expected: false},
+		{existing: &chaincode, config: moved, expected: true},
+		{existing: &chaincode, config: settlement.SettlementConfigStruct{Mode: settlement.ModeInternal}, expected: true},
+	}
+
+	for i, c := range cases {
+		if changed := settlement.RoutingChanged(c.existing, c.config); changed != c.expected {
+			t.Errorf("case %d : RoutingChanged = %v, expected %v", i, changed, c.expected)
+		}
+	}
+}
+
+func TestPartitionAdapterMovesCashThroughEscrow(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(cashPartition)
+	escrow := wallet.EscrowAddress(settlement.EscrowName)
+	ledger.Wallet("alice", "bob", "carol", escrow)
+	ledger.Mint("alice", cashPartition, 1000)
+
+	blocked := fmt.Errorf("blocked")
+	guard := func(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64) error {
+		if amount > 500 {
+			return blocked
+		}
+		return nil
+	}
+
+	adapter := settlement.NewAdapter(nil, cashPartition, escrow, guard, "", false)
+
+	ledger.Tx()
+	if err := adapter.Debit(ledger.Ctx, "alice", 600); err != blocked {
+		t.Errorf("debit error = %v, expected guard error", err)
+	}
+
+	ledger.Tx()
+	ledger.Check(adapter.Debit(ledger.Ctx, "alice", 300))
+
+	if balance := ledger.Balance("alice", cashPartition); balance != 700 {
+		t.Errorf("alice balance = %d, expected 700", balance)
+	}
+	if balance := ledger.Balance(escrow, cashPartition); balance != 300 {
+		t.Errorf("escrow balance = %d, expected 300", balance)
+	}
+
+	ledger.Tx()
+	ledger.Check(adapter.Credit(ledger.Ctx, []settlement.PaymentStruct{{To: "bob", Amount: 200}, {To: "carol", Amount: 100}}))
+
+	if balance := ledger.Balance("bob", cashPartition); balance != 200 {
+		t.Errorf("bob balance = %d, expected 200", balance)
+	}
+	if balance := ledger.Balance("carol", cashPartition); balance != 100 {
+		t.Errorf("carol balance = %d, expected 100", balance)
+	}
+	if balance := ledger.Balance(escrow, cashPartition); balance != 0 {
+		t.Errorf("escrow balance = %d, expected 0", balance)
+	}
+
+	// 지급할 대상이 없으면 아무것도 하지 않음
+	ledger.Tx()
+	ledger.Check(adapter.Credit(ledger.Ctx, nil))
+}
+
+func TestNewAdapterSelectsMode(t *testing.T) {
+
+	internal := settlement.NewAdapter(&settlement.SettlementConfigStruct{Mode: settlement.ModeInternal}, cashPartition, "escrow", nil, "alice", false)
+	if adapter, ok := internal.(settlement.PartitionAdapter); !ok || adapter.CashPartition != cashPartition || adapter.Escrow != "escrow" {
+		t.Errorf("internal adapter = %+v", internal)
+	}
+
+	config := settlement.SettlementConfigStruct{Mode: settlement.ModeChaincode, ChaincodeName: "cash", Channel: "channel", Account: "account", DebitFunction: "Debit", CreditFunction: "Credit"}
+	external := settlement.NewAdapter(&config, cashPartition, "escrow", nil, "alice", true)
+	adapter, ok := external.(settlement.ChaincodeAdapter)
+	if !ok {
+		t.Fatalf("chaincode adapter = %+v", external)
+	}
+	if adapter.ChaincodeName != "cash" || adapter.Account != "account" || adapter.CashPartition != cashPartition || adapter.Submitter != "alice" || !adapter.Pusher {
+		t.Errorf("chaincode adapter = %+v", adapter)
+	}
+}
+
+func TestChaincodeAdapterChecksSubmitter(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Tx()
+
+	config := settlement.SettlementConfigStruct{Mode: settlement.ModeChaincode, ChaincodeName: "cash", Account: "account"}
+
+	// 외부 chaincode 는 제출자 identity 로 실행되므로 다른 주소에서 출금할 수 없음
+	adapter := settlement.NewAdapter(&config, cashPartition, "", nil, "alice", false)
+	expectErrorCode(t, adapter.Debit(ledger.Ctx, "bob", 100), settlement.CodeErrorDebitNotSubmitter)
+
+	// Account 에서의 지급은 발행사/operator 의 push 만 가능
+	expectErrorCode(t, adapter.Credit(ledger.Ctx, []settlement.PaymentStruct{{To: "alice", Amount: 100}}), settlement.CodeErrorCreditNotPushed)
+}
+
+func TestRedemptionPayout(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	config := internalConfig(ledger, 100)
+
+	ledger.Tx()
+	config, err := settlement.AddRedemptionFunds(ledger.Ctx, *config, 1000)
+	ledger.Check(err)
+
+	ledger.Tx()
+	_, err = settlement.CreatePayout(ledger.Ctx, *config, "alice", 11)
+	expectErrorCode(t, err, settlement.CodeErrorInsufficientRedemptionFunds)
+
+	ledger.Tx()
+	payout, err := settlement.CreatePayout(ledger.Ctx, *config, "alice", 4)
+	ledger.Check(err)
+	if payout.Amount != 400 || payout.Status != settlement.PayoutPending || payout.CashPartition != cashPartition {
+		t.Errorf("unexpected payout %+v", payout)
+	}
+
+	config, err = settlement.GetConfig(ledger.Ctx, partition)
+	ledger.Check(err)
+	if config.RedemptionFunds != 600 {
+		t.Errorf("redemption funds = %d, expected 600", config.RedemptionFunds)
+	}
+
+	ledger.Tx()
+	stored, err := settlement.GetPayout(ledger.Ctx, payout.PayoutId)
+	ledger.Check(err)
+
+	paid, err := settlement.MarkPaid(ledger.Ctx, *stored, "alice-new")
+	ledger.Check(err)
+	if paid.Status != settlement.PayoutPaid || paid.PaidTo != "alice-new" || paid.PaidAt != ledger.Now {
+		t.Errorf("unexpected paid payout %+v", paid)
+	}
+
+	ledger.Tx()
+	stored, err = settlement.GetPayout(ledger.Ctx, payout.PayoutId)
+	ledger.Check(err)
+	if stored.Status != settlement.PayoutPaid {
+		t.Errorf("stored status = %s, expected %s", stored.Status, settlement.PayoutPaid)
+	}
+
+	_, err = settlement.MarkPaid(ledger.Ctx, *stored, "alice-new")
+	expectErrorCode(t, err, settlement.CodeErrorPayoutNotPending)
+}
@@ -0,0 +1,77 @@
+package settlement
+
+const (
+	DocType_SettlementConfig = "DOCTYPE_SETTLEMENTCONFIG"
+	DocType_RedemptionPayout = "DOCTYPE_REDEMPTIONPAYOUT"
+)
+
+const (
+	// 상환 대금을 보관하는 escrow wallet 이름 (internal mode, wallet.EscrowAddress)
+	EscrowName = "SETTLEMENT"
+)
+
+// Settlement mode
+const (
+	// 이 chaincode 의 cash partition 으로 결제
+	ModeInternal = "internal"
+	// 같은 channel 의 외부 token chaincode 로 결제 (InvokeChaincode)
+	ModeChaincode = "chaincode"
+)
+
+const (
+	// 외부 chaincode 기본 함수. 이 chaincode 를 cash token 으로 한 번 더 배포한 경우의 함수 이름
+	DefaultDebitFunction  = "TransferByPartition"
+	DefaultCreditFunction = "OperatorTransferBatchByPartition"
+)
+
+// Redemption payout status
+const (
+	PayoutPending = "pending"
+	PayoutPaid    = "paid"
+)
+
+// 증권 partition 의 cash leg 결제 방식. 설정이 없으면 각 flow 가 지정한 cash partition 으로 internal 결제
+type SettlementConfigStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string `json:"partition"`
+	Mode      string `json:"mode"`
+
+	// chaincode mode. Account 는 외부 chaincode 에서 대금을 모아 두는 계정으로,
+	// 호출자가 Account 의 operator 로 등록되어 있어야 CreditFunction 이 통과함
+	ChaincodeName  string `json:"chaincodeName"`
+	Channel        string `json:"channel"`
+	Account        string `json:"account"`
+	DebitFunction  string `json:"debitFunction"`
+	CreditFunction string `json:"creditFunction"`
+
+	// 상환 대금. 토큰 1 개당 CashPartition 수량, 0 이면 상환 대금 없음
+	CashPartition   string `json:"cashPartition"`
+	RedemptionPrice int64  `json:"redemptionPrice"`
+	RedemptionFunds int64  `json:"redemptionFunds"`
+
+	UpdatedBy string `json:"updatedBy"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// 상환된 토큰에 대한 대금. 상환 tx 에서 보유자 wallet 을 두 번 쓰지 않도록 별도로 지급
+type RedemptionPayoutStruct struct {
+	DocType string `json:"docType"`
+
+	PayoutId      string `json:"payoutId"`
+	Partition     string `json:"partition"`
+	Holder        string `json:"holder"`
+	Redeemed      int64  `json:"redeemed"`
+	CashPartition string `json:"cashPartition"`
+	Amount        int64  `json:"amount"`
+
+	Status     string `json:"status"`
+	RedeemedAt int64  `json:"redeemedAt"`
+	PaidTo     string `json:"paidTo"`
+	PaidAt     int64  `json:"paidAt"`
+}
+
+type PaymentStruct struct {
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+}
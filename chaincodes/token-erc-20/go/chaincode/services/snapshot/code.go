@@ -0,0 +1,7 @@
+package snapshot
+
+const CodeErrorSnapshotNotFound int = 770
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorSnapshotNotFound: "Snapshot error : snapshot does not exist",
+}
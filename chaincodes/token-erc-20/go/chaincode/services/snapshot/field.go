@@ -0,0 +1,11 @@
+package snapshot
+
+const (
+	FieldPartition   string = "partition"
+	FieldSnapshotId  string = "snapshotId"
+	FieldHolder      string = "holder"
+	FieldDescription string = "description"
+
+	FieldBalance     string = "balance"
+	FieldTotalSupply string = "totalSupply"
+)
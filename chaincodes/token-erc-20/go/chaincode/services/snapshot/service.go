@@ -0,0 +1,326 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/split"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+)
+
+// CreateSnapshot partition 의 다음 snapshot id 선언. 잔고는 복사하지 않음
+func CreateSnapshot(ctx contractapi.TransactionContextInterface, partition string, description string, createdBy string) (*SnapshotStruct, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	currentId, err := GetCurrentId(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := SnapshotCounterStruct{DocType: DocType_SnapshotCounter, Partition: partition, SnapshotId: currentId + 1}
+
+	counterKey, err := ctx.GetStub().CreateCompositeKey(DocType_SnapshotCounter, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_SnapshotCounter, err)
+	}
+
+	if currentId > 0 {
+		counterToMap, err := ccutils.StructToMap(counter)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_SnapshotCounter, counterKey, counterToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_SnapshotCounter, counterKey, counter, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scale, err := split.GetScale(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := SnapshotStruct{Partition: partition, SnapshotId: counter.SnapshotId, Description: description, ScaleEpoch: scale.Epoch, CreatedBy: createdBy, CreatedAt: now}
+
+	snapshotKey, err := ctx.GetStub().CreateCompositeKey(DocType_Snapshot, []string{partition, strconv.FormatInt(snapshot.SnapshotId, 10)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Snapshot, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_Snapshot, snapshotKey, snapshot, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// GetCurrentId 선언된 snapshot 이 없으면 0
+func GetCurrentId(ctx contractapi.TransactionContextInterface, partition string) (int64, error) {
+
+	counterKey, err := ctx.GetStub().CreateCompositeKey(DocType_SnapshotCounter, []string{partition})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_SnapshotCounter, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(counterKey, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exist {
+		return 0, nil
+	}
+
+	counterBytes, err := ledgermanager.GetState(DocType_SnapshotCounter, counterKey, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	counter := SnapshotCounterStruct{}
+	if err := json.Unmarshal(counterBytes, &counter); err != nil {
+		return 0, err
+	}
+
+	return counter.SnapshotId, nil
+}
+
+func GetSnapshot(ctx contractapi.TransactionContextInterface, partition string, snapshotId int64) (*SnapshotStruct, error) {
+
+	snapshotKey, err := ctx.GetStub().CreateCompositeKey(DocType_Snapshot, []string{partition, strconv.FormatInt(snapshotId, 10)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Snapshot, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(snapshotKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, ccutils.CreateError(CodeErrorSnapshotNotFound, fmt.Errorf(ErrorCodeMessage[CodeErrorSnapshotNotFound]+" : %s/%d", partition, snapshotId))
+	}
+
+	snapshotBytes, err := ledgermanager.GetState(DocType_Snapshot, snapshotKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := SnapshotStruct{}
+	if err := json.Unmarshal(snapshotBytes, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// Checkpoint holder 잔고가 바뀌기 직전에 호출. 현재 snapshot 이후 첫 변경일 때만 before 를 기록.
+// before 는 분할 환산 전 저장된 값과 그 epoch
+func Checkpoint(ctx contractapi.TransactionContextInterface, holder string, partition string, before int64, beforeEpoch int64) error {
+
+	return checkpoint(ctx, DocType_BalanceCheckpoint, []string{partition, holder}, holder, partition, before, beforeEpoch)
+}
+
+// CheckpointSupply partition 총발행량이 바뀌기 직전에 호출
+func CheckpointSupply(ctx contractapi.TransactionContextInterface, partition string, before int64, beforeEpoch int64) error {
+
+	return checkpoint(ctx, DocType_SupplyCheckpoint, []string{partition}, "", partition, before, beforeEpoch)
+}
+
+// BalanceOfAt snapshotId 선언 시점의 holder 잔고. 이진 탐색이라 snapshot 수에 대해 O(log n).
+// 이후 분할이 있어도 선언 시점 epoch 기준 수량을 돌려줌
+func BalanceOfAt(ctx contractapi.TransactionContextInterface, holder string, partition string, snapshotId int64) (int64, error) {
+
+	snapshot, err := GetSnapshot(ctx, partition, snapshotId)
+	if err != nil {
+		return 0, err
+	}
+
+	checkpoint, err := getCheckpoint(ctx, DocType_BalanceCheckpoint, []string{partition, holder}, holder, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	if value, epoch, ok := checkpoint.ValueAt(snapshotId); ok {
+		return scaleTo(ctx, partition, value, epoch, snapshot.ScaleEpoch)
+	}
+
+	// 선언 이후 바뀐 적 없음
+	balanceKey, err := ctx.GetStub().CreateCompositeKey(token.BalanceOfByPartitionPrefix, []string{holder, partition})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the composite key for prefix %s: %v", token.BalanceOfByPartitionPrefix, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(balanceKey, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exist {
+		return 0, nil
+	}
+
+	balance, err := token.GetBalanceRecord(ctx, holder, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	return scaleTo(ctx, partition, balance.Amount, balance.ScaleEpoch, snapshot.ScaleEpoch)
+}
+
+// TotalSupplyAt snapshotId 선언 시점의 partition 총발행량
+func TotalSupplyAt(ctx contractapi.TransactionContextInterface, partition string, snapshotId int64) (int64, error) {
+
+	snapshot, err := GetSnapshot(ctx, partition, snapshotId)
+	if err != nil {
+		return 0, err
+	}
+
+	checkpoint, err := getCheckpoint(ctx, DocType_SupplyCheckpoint, []string{partition}, "", partition)
+	if err != nil {
+		return 0, err
+	}
+
+	if value, epoch, ok := checkpoint.ValueAt(snapshotId); ok {
+		return scaleTo(ctx, partition, value, epoch, snapshot.ScaleEpoch)
+	}
+
+	totalSupplyByPartition, err := token.GetTotalSupplyRecord(ctx, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	return scaleTo(ctx, partition, totalSupplyByPartition.TotalSupply, totalSupplyByPartition.ScaleEpoch, snapshot.ScaleEpoch)
+}
+
+func GetSnapshotList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Snapshot)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldPartition}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+// 같은 tx 에서 여러 번 호출돼도 GetState 는 tx 이전 값을 읽으므로 같은 값을 다시 씀
+func checkpoint(ctx contractapi.TransactionContextInterface, docType string, attributes []string, holder string, partition string, before int64, beforeEpoch int64) error {
+
+	currentId, err := GetCurrentId(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	if currentId == 0 {
+		return nil
+	}
+
+	checkpoint, err := getCheckpoint(ctx, docType, attributes, holder, partition)
+	if err != nil {
+		return err
+	}
+
+	if !checkpoint.needsCheckpoint(currentId) {
+		return nil
+	}
+
+	exist := len(checkpoint.SnapshotIds) > 0
+	// 분할 기능 이전 checkpoint 의 epoch 는 0 으로 채움
+	for len(checkpoint.Epochs) < len(checkpoint.Values) {
+		checkpoint.Epochs = append(checkpoint.Epochs, 0)
+	}
+	checkpoint.SnapshotIds = append(checkpoint.SnapshotIds, currentId)
+	checkpoint.Values = append(checkpoint.Values, before)
+	checkpoint.Epochs = append(checkpoint.Epochs, beforeEpoch)
+
+	checkpointKey, err := ctx.GetStub().CreateCompositeKey(docType, attributes)
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", docType, err)
+	}
+
+	if exist {
+		checkpointToMap, err := ccutils.StructToMap(checkpoint)
+		if err != nil {
+			return err
+		}
+
+		return ledgermanager.UpdateState(docType, checkpointKey, checkpointToMap, ctx)
+	}
+
+	_, err = ledgermanager.PutState(docType, checkpointKey, *checkpoint, ctx)
+	return err
+}
+
+func getCheckpoint(ctx contractapi.TransactionContextInterface, docType string, attributes []string, holder string, partition string) (*CheckpointStruct, error) {
+
+	checkpointKey, err := ctx.GetStub().CreateCompositeKey(docType, attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", docType, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(checkpointKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &CheckpointStruct{DocType: docType, Holder: holder, Partition: partition, SnapshotIds: []int64{}, Values: []int64{}, Epochs: []int64{}}, nil
+	}
+
+	checkpointBytes, err := ledgermanager.GetState(docType, checkpointKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := CheckpointStruct{}
+	if err := json.Unmarshal(checkpointBytes, &checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// checkpoint 는 snapshot 선언 전(이하 epoch)에 기록된 값이므로 선언 시점 epoch 까지만 환산
+func scaleTo(ctx contractapi.TransactionContextInterface, partition string, value int64, fromEpoch int64, toEpoch int64) (int64, error) {
+
+	scale, err := split.GetScale(ctx, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	scaled, _ := scale.Scale(value, fromEpoch, toEpoch)
+
+	return scaled, nil
+}
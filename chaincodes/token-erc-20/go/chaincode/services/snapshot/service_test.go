@@ -0,0 +1,136 @@
+package snapshot_test
+
+import (
+	"testing"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/snapshot"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/split"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/test"
+)
+
+const partition = "BOND"
+
+func transfer(ledger *test.MockLedger, from string, to string, amount int64) {
+
+	ledger.T.Helper()
+	ledger.Tx()
+	err := wallet.TransferBatchByPartition(ledger.Ctx, from, partition, []token.TransferByPartitionStruct{{From: from, To: to, Partition: partition, Amount: amount}})
+	ledger.Check(err)
+}
+
+func createSnapshot(ledger *test.MockLedger) int64 {
+
+	ledger.T.Helper()
+	ledger.Tx()
+	created, err := snapshot.CreateSnapshot(ledger.Ctx, partition, "", "issuer")
+	ledger.Check(err)
+	return created.SnapshotId
+}
+
+func expectBalanceAt(t *testing.T, ledger *test.MockLedger, holder string, snapshotId int64, expected int64) {
+
+	t.Helper()
+	balance, err := snapshot.BalanceOfAt(ledger.Ctx, holder, partition, snapshotId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != expected {
+		t.Errorf("BalanceOfAt(%s, %d) = %d, expected %d", holder, snapshotId, balance, expected)
+	}
+}
+
+func expectSupplyAt(t *testing.T, ledger *test.MockLedger, snapshotId int64, expected int64) {
+
+	t.Helper()
+	supply, err := snapshot.TotalSupplyAt(ledger.Ctx, partition, snapshotId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if supply != expected {
+		t.Errorf("TotalSupplyAt(%d) = %d, expected %d", snapshotId, supply, expected)
+	}
+}
+
+func TestBalanceOfAtKeepsValueAtDeclaration(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice", "bob")
+	ledger.Mint("alice", partition, 100)
+
+	first := createSnapshot(ledger)
+
+	transfer(ledger, "alice", "bob", 30)
+	ledger.Mint("bob", partition, 50)
+
+	second := createSnapshot(ledger)
+
+	transfer(ledger, "bob", "alice", 10)
+
+	expectBalanceAt(t, ledger, "alice", first, 100)
+	expectBalanceAt(t, ledger, "bob", first, 0)
+	expectSupplyAt(t, ledger, first, 100)
+
+	expectBalanceAt(t, ledger, "alice", second, 70)
+	expectBalanceAt(t, ledger, "bob", second, 80)
+	expectSupplyAt(t, ledger, second, 150)
+
+	// 잔고 레코드가 없는 주소
+	expectBalanceAt(t, ledger, "carol", second, 0)
+
+	if balance := ledger.Balance("alice", partition); balance != 80 {
+		t.Errorf("current balance of alice = %d, expected 80", balance)
+	}
+}
+
+func TestBalanceOfAtWithoutLaterChangeUsesCurrentValue(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice")
+	ledger.Mint("alice", partition, 40)
+
+	snapshotId := createSnapshot(ledger)
+
+	// 선언 이후 바뀐 적이 없으면 checkpoint 없이 현재 값
+	expectBalanceAt(t, ledger, "alice", snapshotId, 40)
+	expectSupplyAt(t, ledger, snapshotId, 40)
+}
+
+func TestBalanceOfAtUsesDeclarationEpochAfterSplit(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice", "bob")
+	ledger.Mint("alice", partition, 100)
+
+	snapshotId := createSnapshot(ledger)
+
+	ledger.Tx()
+	_, err := split.ExecuteSplit(ledger.Ctx, partition, 2, 1, "", "issuer")
+	ledger.Check(err)
+
+	transfer(ledger, "alice", "bob", 50)
+
+	if balance := ledger.Balance("alice", partition); balance != 150 {
+		t.Errorf("current balance of alice = %d, expected 150", balance)
+	}
+
+	// 분할 후 이전이 있어도 선언 시점 epoch 기준 수량
+	expectBalanceAt(t, ledger, "alice", snapshotId, 100)
+	expectBalanceAt(t, ledger, "bob", snapshotId, 0)
+	expectSupplyAt(t, ledger, snapshotId, 100)
+}
+
+func TestBalanceOfAtUnknownSnapshot(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+
+	_, err := snapshot.BalanceOfAt(ledger.Ctx, "alice", partition, 1)
+	if err == nil {
+		t.Error("expected error for undeclared snapshot")
+	}
+}
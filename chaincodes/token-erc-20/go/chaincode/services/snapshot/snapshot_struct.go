@@ -0,0 +1,69 @@
+package snapshot
+
+import "sort"
+
+const (
+	DocType_Snapshot          = "DOCTYPE_SNAPSHOT"
+	DocType_SnapshotCounter   = "DOCTYPE_SNAPSHOTCOUNTER"
+	DocType_BalanceCheckpoint = "DOCTYPE_BALANCECHECKPOINT"
+	DocType_SupplyCheckpoint  = "DOCTYPE_SUPPLYCHECKPOINT"
+)
+
+// partition 의 기준일(record date) 선언. 이후 잔고가 처음 바뀔 때 직전 값이 checkpoint 로 남음
+type SnapshotStruct struct {
+	DocType string `json:"docType"`
+
+	Partition   string `json:"partition"`
+	SnapshotId  int64  `json:"snapshotId"`
+	Description string `json:"description"`
+	// 선언 시점의 분할 epoch. BalanceOfAt / TotalSupplyAt 은 이 epoch 기준 수량
+	ScaleEpoch int64 `json:"scaleEpoch"`
+
+	CreatedBy string `json:"createdBy"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// partition 별 마지막 snapshot id. 1 부터 증가
+type SnapshotCounterStruct struct {
+	DocType string `json:"docType"`
+
+	Partition  string `json:"partition"`
+	SnapshotId int64  `json:"snapshotId"`
+}
+
+// 잔고(또는 partition 총발행량)의 snapshot 별 값. SnapshotIds 는 오름차순.
+// Values[i] 는 SnapshotIds[i] 선언 후 처음 바뀌기 직전의 값, Epochs[i] 는 그 값이 기록된 분할 epoch
+type CheckpointStruct struct {
+	DocType string `json:"docType"`
+
+	Holder      string  `json:"holder"`
+	Partition   string  `json:"partition"`
+	SnapshotIds []int64 `json:"snapshotIds"`
+	Values      []int64 `json:"values"`
+	Epochs      []int64 `json:"epochs"`
+}
+
+// ValueAt snapshotId 시점의 값과 그 epoch. 그 이후 바뀐 적이 없으면 false (현재 값을 사용)
+func (c CheckpointStruct) ValueAt(snapshotId int64) (int64, int64, bool) {
+
+	index := sort.Search(len(c.SnapshotIds), func(i int) bool {
+		return c.SnapshotIds[i] >= snapshotId
+	})
+
+	if index == len(c.SnapshotIds) {
+		return 0, 0, false
+	}
+
+	// 분할 기능 이전의 checkpoint 는 epoch 0
+	var epoch int64
+	if index < len(c.Epochs) {
+		epoch = c.Epochs[index]
+	}
+
+	return c.Values[index], epoch, true
+}
+
+// needsCheckpoint 현재 snapshot 에 대한 값이 아직 기록되지 않았는지
+func (c CheckpointStruct) needsCheckpoint(currentId int64) bool {
+	return currentId > 0 && (len(c.SnapshotIds) == 0 || c.SnapshotIds[len(c.SnapshotIds)-1] < currentId)
+}
@@ -0,0 +1,11 @@
+package split
+
+const CodeErrorInvalidRatio int = 790
+const CodeErrorInvalidRoundingPolicy int = 791
+const CodeErrorPartitionEncumbered int = 792
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorInvalidRatio:          "Split error : numerator and denominator must be positive and different",
+	CodeErrorInvalidRoundingPolicy: "Split error : unknown rounding policy",
+	CodeErrorPartitionEncumbered:   "Split error : partition has records that are not scaled by the split",
+}
@@ -0,0 +1,9 @@
+package split
+
+const (
+	FieldPartition      string = "partition"
+	FieldNumerator      string = "numerator"
+	FieldDenominator    string = "denominator"
+	FieldRoundingPolicy string = "roundingPolicy"
+	FieldHolder         string = "holder"
+)
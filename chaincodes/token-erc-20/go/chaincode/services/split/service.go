@@ -0,0 +1,159 @@
+package split
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// ExecuteSplit partition 에 numerator:denominator 분할(병합) 추가. 잔고는 다음 조회/변경 시 환산됨
+func ExecuteSplit(ctx contractapi.TransactionContextInterface, partition string, numerator int64, denominator int64, roundingPolicy string, executedBy string) (*PartitionScaleStruct, error) {
+
+	if numerator <= 0 || denominator <= 0 || numerator == denominator {
+		return nil, ccutils.CreateError(CodeErrorInvalidRatio, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidRatio]+" : %d/%d", numerator, denominator))
+	}
+
+	if roundingPolicy == "" {
+		roundingPolicy = RoundingCashInLieu
+	}
+
+	if roundingPolicy != RoundingCashInLieu && roundingPolicy != RoundingUp {
+		return nil, ccutils.CreateError(CodeErrorInvalidRoundingPolicy, fmt.Errorf(ErrorCodeMessage[CodeErrorInvalidRoundingPolicy]+" : "+roundingPolicy))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scale, err := GetScale(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	exist := scale.Epoch > 0
+	scale.Epoch++
+	scale.Splits = append(scale.Splits, SplitStruct{Epoch: scale.Epoch, Numerator: numerator, Denominator: denominator, RoundingPolicy: roundingPolicy, ExecutedBy: executedBy, ExecutedAt: now})
+
+	scaleKey, err := ctx.GetStub().CreateCompositeKey(DocType_PartitionScale, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PartitionScale, err)
+	}
+
+	if exist {
+		scaleToMap, err := ccutils.StructToMap(scale)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_PartitionScale, scaleKey, scaleToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_PartitionScale, scaleKey, *scale, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scale, nil
+}
+
+// GetScale 분할한 적이 없으면 Epoch 0
+func GetScale(ctx contractapi.TransactionContextInterface, partition string) (*PartitionScaleStruct, error) {
+
+	scaleKey, err := ctx.GetStub().CreateCompositeKey(DocType_PartitionScale, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_PartitionScale, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(scaleKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &PartitionScaleStruct{DocType: DocType_PartitionScale, Partition: partition, Epoch: 0, Splits: []SplitStruct{}}, nil
+	}
+
+	scaleBytes, err := ledgermanager.GetState(DocType_PartitionScale, scaleKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := PartitionScaleStruct{}
+	if err := json.Unmarshal(scaleBytes, &scale); err != nil {
+		return nil, err
+	}
+
+	return &scale, nil
+}
+
+// RecordCashInLieu 잔고 환산 중 내림된 단수주 기록. 같은 보유자는 분할당 한 번만 환산되므로 덮어쓰지 않음
+func RecordCashInLieu(ctx contractapi.TransactionContextInterface, holder string, partition string, fractions []FractionStruct) error {
+
+	if len(fractions) == 0 {
+		return nil
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, fraction := range fractions {
+		entitlementKey, err := ctx.GetStub().CreateCompositeKey(DocType_CashInLieu, []string{partition, holder, strconv.FormatInt(fraction.Epoch, 10)})
+		if err != nil {
+			return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_CashInLieu, err)
+		}
+
+		exist, err := ledgermanager.CheckExistState(entitlementKey, ctx)
+		if err != nil {
+			return err
+		}
+
+		if exist {
+			continue
+		}
+
+		_, err = ledgermanager.PutState(DocType_CashInLieu, entitlementKey, CashInLieuStruct{Holder: holder, Partition: partition, FractionStruct: fraction, RecordedAt: now}, ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func GetCashInLieuList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_CashInLieu)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldPartition, FieldHolder}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
@@ -0,0 +1,82 @@
+package split
+
+import "math/big"
+
+const (
+	DocType_PartitionScale = "DOCTYPE_PARTITIONSCALE"
+	DocType_CashInLieu     = "DOCTYPE_CASHINLIEU"
+)
+
+// Reverse split rounding policy
+const (
+	// 단수주는 내림하고 cash-in-lieu entitlement 로 기록
+	RoundingCashInLieu = "cashInLieu"
+	// 단수주는 1주로 올림
+	RoundingUp = "roundUp"
+)
+
+// partition 의 분할/병합 이력. 잔고, 총발행량, allowance 는 기록된 Epoch 부터 현재 Epoch 까지
+// 분할을 차례로 적용해 읽고, 다음에 쓸 때 현재 Epoch 기준으로 다시 저장됨.
+// 동결 수량, vesting, 취득 한도, 예약/hold/질권 수량과 주문, DvP, 공모, 배당, 상환 대금의 escrow 수량은
+// 환산하지 않으므로 남아 있으면 분할할 수 없음 (취득 이력은 읽을 때 환산)
+type PartitionScaleStruct struct {
+	DocType string `json:"docType"`
+
+	Partition string        `json:"partition"`
+	Epoch     int64         `json:"epoch"`
+	Splits    []SplitStruct `json:"splits"`
+}
+
+// Epoch 번째 분할. 잔고 x 는 x * Numerator / Denominator
+type SplitStruct struct {
+	Epoch          int64  `json:"epoch"`
+	Numerator      int64  `json:"numerator"`
+	Denominator    int64  `json:"denominator"`
+	RoundingPolicy string `json:"roundingPolicy"`
+
+	ExecutedBy string `json:"executedBy"`
+	ExecutedAt int64  `json:"executedAt"`
+}
+
+// 분할 적용 중 내림된 단수주. 분할 후 단위로 FractionNumerator / FractionDenominator 주
+type FractionStruct struct {
+	Epoch               int64 `json:"epoch"`
+	FractionNumerator   int64 `json:"fractionNumerator"`
+	FractionDenominator int64 `json:"fractionDenominator"`
+}
+
+// Scale fromEpoch 기준 amount 를 toEpoch 기준으로 환산. cash-in-lieu 로 내림된 단수주도 함께 반환
+func (p PartitionScaleStruct) Scale(amount int64, fromEpoch int64, toEpoch int64) (int64, []FractionStruct) {
+
+	fractions := []FractionStruct{}
+	value := big.NewInt(amount)
+	for _, split := range p.Splits {
+		if split.Epoch <= fromEpoch || split.Epoch > toEpoch {
+			continue
+		}
+
+		value.Mul(value, big.NewInt(split.Numerator))
+		quotient, remainder := new(big.Int).QuoRem(value, big.NewInt(split.Denominator), new(big.Int))
+		if remainder.Sign() > 0 {
+			if split.RoundingPolicy == RoundingUp {
+				quotient.Add(quotient, big.NewInt(1))
+			} else {
+				fractions = append(fractions, FractionStruct{Epoch: split.Epoch, FractionNumerator: remainder.Int64(), FractionDenominator: split.Denominator})
+			}
+		}
+		value = quotient
+	}
+
+	return value.Int64(), fractions
+}
+
+// 보유자의 단수주 보상 권리. 발행사가 장외에서 현금으로 정산
+type CashInLieuStruct struct {
+	DocType string `json:"docType"`
+
+	Holder    string `json:"holder"`
+	Partition string `json:"partition"`
+	FractionStruct
+
+	RecordedAt int64 `json:"recordedAt"`
+}
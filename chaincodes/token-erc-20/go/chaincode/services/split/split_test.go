@@ -0,0 +1,175 @@
+package split_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/split"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/test"
+)
+
+const partition = "EQUITY"
+
+func TestScale(t *testing.T) {
+
+	scale := split.PartitionScaleStruct{
+		Epoch: 3,
+		Splits: []split.SplitStruct{
+			{Epoch: 1, Numerator: 3, Denominator: 2, RoundingPolicy: split.RoundingCashInLieu},
+			{Epoch: 2, Numerator: 1, Denominator: 4, RoundingPolicy: split.RoundingUp},
+			{Epoch: 3, Numerator: 10, Denominator: 1, RoundingPolicy: split.RoundingCashInLieu},
+		},
+	}
+
+	cases := []struct {
+		amount    int64
+		fromEpoch int64
+		toEpoch   int64
+		expected  int64
+		fractions int
+	}{
+		// 7 * 3/2 = 10.5 -> 10 (0.5 cash-in-lieu), 10 / 4 = 2.5 -> 3 (올림), 3 * 10 = 30
+		{amount: 7, fromEpoch: 0, toEpoch: 3, expected: 30, fractions: 1},
+		// 기록된 epoch 이후 분할만 적용
+		{amount: 9, fromEpoch: 1, toEpoch: 3, expected: 30, fractions: 0},
+		{amount: 5, fromEpoch: 2, toEpoch: 3, expected: 50, fractions: 0},
+		// toEpoch 까지만 적용
+		{amount: 8, fromEpoch: 0, toEpoch: 1, expected: 12, fractions: 0},
+		{amount: 5, fromEpoch: 0, toEpoch: 1, expected: 7, fractions: 1},
+		{amount: 5, fromEpoch: 3, toEpoch: 3, expected: 5, fractions: 0},
+		{amount: 0, fromEpoch: 0, toEpoch: 3, expected: 0, fractions: 0},
+	}
+
+	for _, c := range cases {
+		scaled, fractions := scale.Scale(c.amount, c.fromEpoch, c.toEpoch)
+		if scaled != c.expected || len(fractions) != c.fractions {
+			t.Errorf("Scale(%d, %d, %d) = %d with %d fractions, expected %d with %d", c.amount, c.fromEpoch, c.toEpoch, scaled, len(fractions), c.expected, c.fractions)
+		}
+	}
+
+	_, fractions := scale.Scale(7, 0, 1)
+	if len(fractions) != 1 || fractions[0].Epoch != 1 || fractions[0].FractionNumerator != 1 || fractions[0].FractionDenominator != 2 {
+		t.Errorf("unexpected fraction %+v", fractions)
+	}
+}
+
+func TestExecuteSplitRejectsInvalidInput(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Tx()
+
+	invalid := []struct {
+		numerator      int64
+		denominator    int64
+		roundingPolicy string
+	}{
+		{numerator: 0, denominator: 1},
+		{numerator: 2, denominator: -1},
+		{numerator: 3, denominator: 3},
+		{numerator: 2, denominator: 1, roundingPolicy: "nearest"},
+	}
+
+	for _, c := range invalid {
+		_, err := split.ExecuteSplit(ledger.Ctx, partition, c.numerator, c.denominator, c.roundingPolicy, "issuer")
+		if err == nil {
+			t.Errorf("ExecuteSplit(%d, %d, %q) should fail", c.numerator, c.denominator, c.roundingPolicy)
+		}
+	}
+
+	scale, err := split.GetScale(ledger.Ctx, partition)
+	ledger.Check(err)
+	if scale.Epoch != 0 {
+		t.Errorf("epoch = %d after rejected splits, expected 0", scale.Epoch)
+	}
+}
+
+func TestSplitScalesBalancesAndSupply(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice", "bob")
+	ledger.Mint("alice", partition, 101)
+	ledger.Mint("bob", partition, 40)
+
+	// 1:2 병합
+	ledger.Tx()
+	scale, err := split.ExecuteSplit(ledger.Ctx, partition, 1, 2, "", "issuer")
+	ledger.Check(err)
+	if scale.Epoch != 1 || scale.Splits[0].RoundingPolicy != split.RoundingCashInLieu {
+		t.Fatalf("unexpected scale %+v", scale)
+	}
+
+	// 잔고 레코드는 다음 변경까지 그대로이고 읽을 때 환산
+	if balance := ledger.Balance("alice", partition); balance != 50 {
+		t.Errorf("alice balance = %d, expected 50", balance)
+	}
+	if balance := ledger.Balance("bob", partition); balance != 20 {
+		t.Errorf("bob balance = %d, expected 20", balance)
+	}
+
+	supply, err := token.TotalSupplyByPartition(ledger.Ctx, partition)
+	ledger.Check(err)
+	// 단수주는 보유자 잔고가 처음 바뀔 때 빠지므로 그 전에는 잔고 합보다 클 수 있음
+	if supply.TotalSupply != 70 {
+		t.Errorf("total supply = %d, expected 70", supply.TotalSupply)
+	}
+
+	ledger.Tx()
+	err = wallet.TransferBatchByPartition(ledger.Ctx, "alice", partition, []token.TransferByPartitionStruct{{From: "alice", To: "bob", Partition: partition, Amount: 10}})
+	ledger.Check(err)
+
+	if balance := ledger.Balance("alice", partition); balance != 40 {
+		t.Errorf("alice balance after transfer = %d, expected 40", balance)
+	}
+	if balance := ledger.Balance("bob", partition); balance != 30 {
+		t.Errorf("bob balance after transfer = %d, expected 30", balance)
+	}
+
+	record, err := token.GetBalanceRecord(ledger.Ctx, "alice", partition)
+	ledger.Check(err)
+	if record.ScaleEpoch != 1 || record.Amount != 40 {
+		t.Errorf("alice record = %d at epoch %d, expected 40 at epoch 1", record.Amount, record.ScaleEpoch)
+	}
+
+	// alice 의 101 주 중 단수 1/2 주는 cash-in-lieu 로 기록, bob 은 나누어 떨어짐
+	expectCashInLieu(t, ledger, "alice", true)
+	expectCashInLieu(t, ledger, "bob", false)
+}
+
+func TestSplitRoundUpPolicy(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice")
+	ledger.Mint("alice", partition, 5)
+
+	ledger.Tx()
+	_, err := split.ExecuteSplit(ledger.Ctx, partition, 1, 2, split.RoundingUp, "issuer")
+	ledger.Check(err)
+
+	if balance := ledger.Balance("alice", partition); balance != 3 {
+		t.Errorf("alice balance = %d, expected 3", balance)
+	}
+
+	ledger.Tx()
+	_, err = wallet.SettleBalance(ledger.Ctx, "alice", partition)
+	ledger.Check(err)
+
+	expectCashInLieu(t, ledger, "alice", false)
+}
+
+func expectCashInLieu(t *testing.T, ledger *test.MockLedger, holder string, expected bool) {
+
+	t.Helper()
+	key, err := ledger.Stub.CreateCompositeKey(split.DocType_CashInLieu, []string{partition, holder, strconv.Itoa(1)})
+	ledger.Check(err)
+
+	exist, err := ledgermanager.CheckExistState(key, ledger.Ctx)
+	ledger.Check(err)
+	if exist != expected {
+		t.Errorf("cash-in-lieu of %s recorded = %v, expected %v", holder, exist, expected)
+	}
+}
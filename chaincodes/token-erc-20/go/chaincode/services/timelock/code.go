@@ -0,0 +1,15 @@
+package timelock
+
+const CodeErrorActionNotQueued int = 630
+const CodeErrorDelayNotElapsed int = 631
+const CodeErrorDelayElapsed int = 632
+const CodeErrorActionExpired int = 633
+const CodeErrorDelayReduction int = 634
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorActionNotQueued: "Timelock error : action is not queued",
+	CodeErrorDelayNotElapsed: "Timelock error : delay has not elapsed yet",
+	CodeErrorDelayElapsed:    "Timelock error : delay has already elapsed",
+	CodeErrorActionExpired:   "Timelock error : action is expired",
+	CodeErrorDelayReduction:  "Timelock error : delay can only be reduced through a scheduled action",
+}
@@ -0,0 +1,12 @@
+package timelock
+
+const (
+	FieldCategory     string = "category"
+	FieldDelaySeconds string = "delaySeconds"
+
+	FieldTimelockId string = "timelockId"
+	FieldActionType string = "actionType"
+	FieldArgs       string = "args"
+	FieldStatus     string = "status"
+	FieldProposer   string = "proposer"
+)
@@ -0,0 +1,266 @@
+package timelock
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+// SetDelay 단축 여부는 호출하는 쪽에서 확인 (CheckDelayIncrease)
+func SetDelay(ctx contractapi.TransactionContextInterface, category string, delaySeconds int64) (*TimelockDelayStruct, error) {
+
+	if !IsValidCategory(category) {
+		return nil, fmt.Errorf("unknown timelock category : %s", category)
+	}
+
+	if delaySeconds < MinDelaySeconds {
+		return nil, fmt.Errorf("delay cannot be less than %d seconds", MinDelaySeconds)
+	}
+
+	delay := TimelockDelayStruct{Category: category, DelaySeconds: delaySeconds}
+
+	delayKey, err := ctx.GetStub().CreateCompositeKey(DocType_TimelockDelay, []string{category})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TimelockDelay, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(delayKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if exist {
+		delayToMap, err := ccutils.StructToMap(delay)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_TimelockDelay, delayKey, delayToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_TimelockDelay, delayKey, delay, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &delay, nil
+}
+
+// CheckDelayIncrease 즉시 적용은 현재 지연 시간 이상으로만 가능
+func CheckDelayIncrease(ctx contractapi.TransactionContextInterface, category string, delaySeconds int64) error {
+
+	delay, err := GetDelay(ctx, category)
+	if err != nil {
+		return err
+	}
+
+	if delaySeconds < delay.DelaySeconds {
+		return ccutils.CreateError(CodeErrorDelayReduction, fmt.Errorf(ErrorCodeMessage[CodeErrorDelayReduction]+" : "+category))
+	}
+
+	return nil
+}
+
+// GetDelay 설정이 없으면 DefaultDelaySeconds
+func GetDelay(ctx contractapi.TransactionContextInterface, category string) (*TimelockDelayStruct, error) {
+
+	if !IsValidCategory(category) {
+		return nil, fmt.Errorf("unknown timelock category : %s", category)
+	}
+
+	delayKey, err := ctx.GetStub().CreateCompositeKey(DocType_TimelockDelay, []string{category})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TimelockDelay, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(delayKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &TimelockDelayStruct{DocType: DocType_TimelockDelay, Category: category, DelaySeconds: DefaultDelaySeconds}, nil
+	}
+
+	delayBytes, err := ledgermanager.GetState(DocType_TimelockDelay, delayKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delay := TimelockDelayStruct{}
+	if err := json.Unmarshal(delayBytes, &delay); err != nil {
+		return nil, err
+	}
+
+	return &delay, nil
+}
+
+// Schedule 실행 가능 시간은 트랜잭션 timestamp + category 지연 시간
+func Schedule(ctx contractapi.TransactionContextInterface, actionType string, category string, proposer string, args map[string]interface{}) (*TimelockActionStruct, error) {
+
+	delay, err := GetDelay(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if args == nil {
+		args = make(map[string]interface{})
+	}
+
+	action := TimelockActionStruct{}
+	action.TimelockId = ctx.GetStub().GetTxID()
+	action.ActionType = actionType
+	action.Category = category
+	action.Proposer = proposer
+	action.Args = args
+	action.Status = StatusQueued
+	action.ScheduledAt = now
+	action.ExecutableAt = now + delay.DelaySeconds
+
+	actionKey, err := ctx.GetStub().CreateCompositeKey(DocType_TimelockAction, []string{action.TimelockId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TimelockAction, err)
+	}
+
+	_, err = ledgermanager.PutState(DocType_TimelockAction, actionKey, action, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &action, nil
+}
+
+// GetAction 유예 기간까지 실행되지 않은 queued action 은 expired 로 보여줌
+func GetAction(ctx contractapi.TransactionContextInterface, timelockId string) (*TimelockActionStruct, error) {
+
+	actionKey, err := ctx.GetStub().CreateCompositeKey(DocType_TimelockAction, []string{timelockId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TimelockAction, err)
+	}
+
+	actionBytes, err := ledgermanager.GetState(DocType_TimelockAction, actionKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	action := TimelockActionStruct{}
+	if err := json.Unmarshal(actionBytes, &action); err != nil {
+		return nil, err
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if action.Status == StatusQueued && now > action.ExecutableAt+GracePeriodSeconds {
+		action.Status = StatusExpired
+	}
+
+	return &action, nil
+}
+
+// MarkExecuted 지연 시간이 지난 queued action 만 실행 처리
+func MarkExecuted(ctx contractapi.TransactionContextInterface, action TimelockActionStruct, executor string) (*TimelockActionStruct, error) {
+
+	if action.Status == StatusExpired {
+		return nil, ccutils.CreateError(CodeErrorActionExpired, fmt.Errorf(ErrorCodeMessage[CodeErrorActionExpired]+" : "+action.TimelockId))
+	}
+
+	if action.Status != StatusQueued {
+		return nil, ccutils.CreateError(CodeErrorActionNotQueued, fmt.Errorf(ErrorCodeMessage[CodeErrorActionNotQueued]+" : "+action.TimelockId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if now < action.ExecutableAt {
+		return nil, ccutils.CreateError(CodeErrorDelayNotElapsed, fmt.Errorf(ErrorCodeMessage[CodeErrorDelayNotElapsed]+" : executable at %d", action.ExecutableAt))
+	}
+
+	return closeAction(ctx, action, StatusExecuted, executor, now)
+}
+
+// Cancel 지연 시간이 지나기 전에만 취소 가능
+func Cancel(ctx contractapi.TransactionContextInterface, action TimelockActionStruct, canceller string) (*TimelockActionStruct, error) {
+
+	if action.Status != StatusQueued {
+		return nil, ccutils.CreateError(CodeErrorActionNotQueued, fmt.Errorf(ErrorCodeMessage[CodeErrorActionNotQueued]+" : "+action.TimelockId))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if now >= action.ExecutableAt {
+		return nil, ccutils.CreateError(CodeErrorDelayElapsed, fmt.Errorf(ErrorCodeMessage[CodeErrorDelayElapsed]+" : "+action.TimelockId))
+	}
+
+	return closeAction(ctx, action, StatusCancelled, canceller, now)
+}
+
+func GetActionList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_TimelockAction)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldActionType, FieldCategory, FieldStatus, FieldProposer}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func closeAction(ctx contractapi.TransactionContextInterface, action TimelockActionStruct, status string, closedBy string, closedAt int64) (*TimelockActionStruct, error) {
+
+	action.Status = status
+	action.ClosedAt = closedAt
+	action.ClosedBy = closedBy
+
+	actionKey, err := ctx.GetStub().CreateCompositeKey(DocType_TimelockAction, []string{action.TimelockId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TimelockAction, err)
+	}
+
+	actionToMap, err := ccutils.StructToMap(action)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_TimelockAction, actionKey, actionToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &action, nil
+}
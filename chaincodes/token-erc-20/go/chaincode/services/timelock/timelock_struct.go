@@ -0,0 +1,67 @@
+package timelock
+
+const (
+	DocType_TimelockDelay  = "DOCTYPE_TIMELOCKDELAY"
+	DocType_TimelockAction = "DOCTYPE_TIMELOCKACTION"
+
+	// 카테고리별 지연 시간이 설정되지 않았을 때 기본값 (2일)
+	DefaultDelaySeconds int64 = 2 * 24 * 60 * 60
+	// 설정 가능한 최소 지연 시간 (1일)
+	MinDelaySeconds int64 = 24 * 60 * 60
+	// 실행 가능 시점 이후 실행하지 않으면 만료 (14일)
+	GracePeriodSeconds int64 = 14 * 24 * 60 * 60
+)
+
+// Action category
+const (
+	CategorySupplyCap       = "supplyCap"
+	CategoryControllability = "controllability"
+	CategoryAdminKey        = "adminKey"
+	CategoryContractConfig  = "contractConfig"
+	// 지연 시간 단축 자체도 timelock 을 거침
+	CategoryTimelockDelay = "timelockDelay"
+)
+
+// Timelock action status
+const (
+	StatusQueued    = "queued"
+	StatusExecuted  = "executed"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+func IsValidCategory(category string) bool {
+	switch category {
+	case CategorySupplyCap, CategoryControllability, CategoryAdminKey, CategoryContractConfig, CategoryTimelockDelay:
+		return true
+	}
+	return false
+}
+
+// category 별 지연 시간
+type TimelockDelayStruct struct {
+	DocType string `json:"docType"`
+
+	Category     string `json:"category"`
+	DelaySeconds int64  `json:"delaySeconds"`
+}
+
+type TimelockActionStruct struct {
+	DocType string `json:"docType"`
+
+	TimelockId string `json:"timelockId"`
+	ActionType string `json:"actionType"`
+	Category   string `json:"category"`
+	Proposer   string `json:"proposer"`
+
+	// 실행 시 사용할 파라메터
+	Args map[string]interface{} `json:"args"`
+
+	Status string `json:"status"`
+
+	ScheduledAt int64 `json:"scheduledAt"`
+	// 실행 가능한 가장 빠른 시간 (scheduledAt + delay)
+	ExecutableAt int64  `json:"executableAt"`
+	ClosedAt     int64  `json:"closedAt"`
+	ClosedBy     string `json:"closedBy"`
+}
@@ -9,8 +9,12 @@ const (
 	FieldOwner   string = "owner"
 	FieldSpender string = "spender"
 
-	FieldRecipient string = "recipient"
+	FieldRecipient  string = "recipient"
+	FieldRecipients string = "recipients"
 
 	FieldFrom string = "from"
 	FieldTo   string = "to"
+
+	FieldSupplyCap      string = "supplyCap"
+	FieldIsControllable string = "isControllable"
 )
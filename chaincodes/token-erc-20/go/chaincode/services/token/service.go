@@ -8,6 +8,7 @@ import (
 
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/split"
 )
 
 func TotalSupply(ctx contractapi.TransactionContextInterface) (*TotalSupplyStruct, error) {
@@ -25,8 +26,42 @@ func TotalSupply(ctx contractapi.TransactionContextInterface) (*TotalSupplyStruc
 	return &totalSupply, nil
 }
 
+// AdjustTotalSupply 전체 총발행량에 delta 반영. 분할로 partition 총발행량이 바뀔 때 사용
+func AdjustTotalSupply(ctx contractapi.TransactionContextInterface, delta int64) error {
+
+	totalSupply, err := TotalSupply(ctx)
+	if err != nil {
+		return err
+	}
+
+	totalSupply.TotalSupply += delta
+
+	totalSupplyMap, err := ccutils.StructToMap(*totalSupply)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_TotalSupply, "TotalSupply", totalSupplyMap, ctx)
+}
+
 func TotalSupplyByPartition(ctx contractapi.TransactionContextInterface, partition string) (*TotalSupplyByPartitionStruct, error) {
 
+	totalSupplyByPartition, err := GetTotalSupplyRecord(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ScaleTotalSupply(ctx, partition, totalSupplyByPartition)
+	if err != nil {
+		return nil, err
+	}
+
+	return totalSupplyByPartition, nil
+}
+
+// GetTotalSupplyRecord 분할 환산 전 저장된 그대로의 총발행량 (snapshot checkpoint 용)
+func GetTotalSupplyRecord(ctx contractapi.TransactionContextInterface, partition string) (*TotalSupplyByPartitionStruct, error) {
+
 	// totalSupplyByPartition
 	totalKey, err := ctx.GetStub().CreateCompositeKey(DocType_TotalSupplyByPartition, []string{partition})
 	if err != nil {
@@ -46,25 +81,79 @@ func TotalSupplyByPartition(ctx contractapi.TransactionContextInterface, partiti
 	return &totalSupplyByPartition, nil
 }
 
+// ScaleTotalSupply 총발행량과 supply cap 을 현재 분할 epoch 기준으로 환산.
+// 보유자 잔고의 단수주는 처음 변경될 때 cash-in-lieu 로 빠지므로 그 전까지는 잔고 합보다 클 수 있음
+func ScaleTotalSupply(ctx contractapi.TransactionContextInterface, partition string, totalSupplyByPartition *TotalSupplyByPartitionStruct) error {
+
+	scale, err := split.GetScale(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	totalSupplyByPartition.TotalSupply, _ = scale.Scale(totalSupplyByPartition.TotalSupply, totalSupplyByPartition.ScaleEpoch, scale.Epoch)
+	totalSupplyByPartition.SupplyCap, _ = scale.Scale(totalSupplyByPartition.SupplyCap, totalSupplyByPartition.ScaleEpoch, scale.Epoch)
+	totalSupplyByPartition.ScaleEpoch = scale.Epoch
+
+	return nil
+}
+
 func BalanceOfByPartition(ctx contractapi.TransactionContextInterface, _tokenHolder string, _partition string) (int64, error) {
 
+	partitionToken, err := GetBalanceRecord(ctx, _tokenHolder, _partition)
+	if err != nil {
+		return 0, err
+	}
+
+	scale, err := split.GetScale(ctx, _partition)
+	if err != nil {
+		return 0, err
+	}
+
+	balance, _ := scale.Scale(partitionToken.Amount, partitionToken.ScaleEpoch, scale.Epoch)
+
+	return balance, nil
+}
+
+// BalanceOfByPartitionOrZero 잔고 레코드가 아직 없는 주소(처음 받는 수령인)는 0
+func BalanceOfByPartitionOrZero(ctx contractapi.TransactionContextInterface, holder string, partition string) (int64, error) {
+
+	balanceKey, err := ctx.GetStub().CreateCompositeKey(BalanceOfByPartitionPrefix, []string{holder, partition})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the composite key for prefix %s: %v", BalanceOfByPartitionPrefix, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(balanceKey, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exist {
+		return 0, nil
+	}
+
+	return BalanceOfByPartition(ctx, holder, partition)
+}
+
+// GetBalanceRecord 분할 환산 전 저장된 그대로의 잔고 레코드 (snapshot checkpoint 용)
+func GetBalanceRecord(ctx contractapi.TransactionContextInterface, _tokenHolder string, _partition string) (*PartitionToken, error) {
+
 	// Create allowanceKey
 	walletKey, err := ctx.GetStub().CreateCompositeKey(BalanceOfByPartitionPrefix, []string{_tokenHolder, _partition})
 	if err != nil {
-		return 0, fmt.Errorf("failed to create the composite key for prefix %s: %v", BalanceOfByPartitionPrefix, err)
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", BalanceOfByPartitionPrefix, err)
 	}
 
 	partitionTokenBytes, err := ledgermanager.GetState(DocType_Token, walletKey, ctx)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	partitionToken := PartitionToken{}
 	if err := json.Unmarshal(partitionTokenBytes, &partitionToken); err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return partitionToken.Amount, nil
+	return &partitionToken, nil
 }
 
 func AllowanceByPartition(ctx contractapi.TransactionContextInterface, owner string, spender string, partition string) (*AllowanceByPartitionStruct, error) {
@@ -85,11 +174,37 @@ func AllowanceByPartition(ctx contractapi.TransactionContextInterface, owner str
 		return nil, err
 	}
 
+	err = scaleAllowance(ctx, &allowanceByPartition)
+	if err != nil {
+		return nil, err
+	}
+
 	return &allowanceByPartition, nil
 }
 
+// scaleAllowance 분할 전 승인한 수량도 같은 비율로 환산
+func scaleAllowance(ctx contractapi.TransactionContextInterface, allowanceByPartition *AllowanceByPartitionStruct) error {
+
+	scale, err := split.GetScale(ctx, allowanceByPartition.Partition)
+	if err != nil {
+		return err
+	}
+
+	allowanceByPartition.Amount, _ = scale.Scale(allowanceByPartition.Amount, allowanceByPartition.ScaleEpoch, scale.Epoch)
+	allowanceByPartition.ScaleEpoch = scale.Epoch
+
+	return nil
+}
+
 func ApproveByPartition(ctx contractapi.TransactionContextInterface, allowanceByPartition AllowanceByPartitionStruct) error {
 
+	// Amount 는 현재 epoch 기준
+	scale, err := split.GetScale(ctx, allowanceByPartition.Partition)
+	if err != nil {
+		return err
+	}
+	allowanceByPartition.ScaleEpoch = scale.Epoch
+
 	allowanceByPartitionToMap, err := ccutils.StructToMap(allowanceByPartition)
 
 	// Create allowanceKey
@@ -210,6 +325,105 @@ func IsIssuable(ctx contractapi.TransactionContextInterface, partition string) e
 	return nil
 }
 
+func GetPartitionToken(ctx contractapi.TransactionContextInterface, partition string) (*PartitionToken, error) {
+
+	tokenBytes, err := ledgermanager.GetState(DocType_Token, partition, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenStruct := PartitionToken{}
+	err = json.Unmarshal(tokenBytes, &tokenStruct)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tokenStruct, nil
+}
+
+// SetSupplyCap 0 이면 무제한. 현재 발행량보다 작게 설정할 수 없음
+func SetSupplyCap(ctx contractapi.TransactionContextInterface, partition string, supplyCap int64) (*TotalSupplyByPartitionStruct, error) {
+
+	if supplyCap < 0 {
+		return nil, fmt.Errorf("supply cap cannot be negative")
+	}
+
+	totalSupplyByPartition, err := TotalSupplyByPartition(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	if supplyCap != 0 && supplyCap < totalSupplyByPartition.TotalSupply {
+		return nil, fmt.Errorf("supply cap %d is lower than the current total supply %d", supplyCap, totalSupplyByPartition.TotalSupply)
+	}
+
+	totalSupplyByPartition.SupplyCap = supplyCap
+
+	totalSupplyByPartitionMap, err := ccutils.StructToMap(totalSupplyByPartition)
+	if err != nil {
+		return nil, err
+	}
+
+	totalKey, err := ctx.GetStub().CreateCompositeKey(DocType_TotalSupplyByPartition, []string{partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TotalSupplyByPartition, err)
+	}
+
+	err = ledgermanager.UpdateState(DocType_TotalSupplyByPartition, totalKey, totalSupplyByPartitionMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return totalSupplyByPartition, nil
+}
+
+// CheckSupplyCap amount 만큼 추가 발행 시 supply cap 을 넘는지 확인
+func CheckSupplyCap(ctx contractapi.TransactionContextInterface, partition string, amount int64) error {
+
+	totalSupplyByPartition, err := TotalSupplyByPartition(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	if totalSupplyByPartition.SupplyCap != 0 && totalSupplyByPartition.TotalSupply+amount > totalSupplyByPartition.SupplyCap {
+		return fmt.Errorf("supply cap exceeded : cap %d, current %d, requested %d", totalSupplyByPartition.SupplyCap, totalSupplyByPartition.TotalSupply, amount)
+	}
+
+	return nil
+}
+
+func SetControllable(ctx contractapi.TransactionContextInterface, partition string, isControllable bool) (*PartitionToken, error) {
+
+	tokenStruct, err := GetPartitionToken(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenStruct.IsControllable = isControllable
+
+	tokenToMap, err := ccutils.StructToMap(tokenStruct)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_Token, partition, tokenToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenStruct, nil
+}
+
+func IsControllable(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	tokenStruct, err := GetPartitionToken(ctx, partition)
+	if err != nil {
+		return false, err
+	}
+
+	return tokenStruct.IsControllable, nil
+}
+
 func GetTokenList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
 
 	queryBuilder := ccutils.QueryBuilder{}
@@ -311,3 +525,211 @@ func GetTokenHolderList(args map[string]interface{}, partition string, pageSize
 
 	return bytes, nil
 }
+
+// MigrateAllowances oldAddress 가 owner 혹은 spender 인 allowance 를 newAddress 로 옮김
+func MigrateAllowances(ctx contractapi.TransactionContextInterface, oldAddress string, newAddress string) error {
+
+	// owner 인 allowance
+	ownerIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(allowanceByPartitionPrefix, []string{oldAddress})
+	if err != nil {
+		return err
+	}
+	defer ownerIterator.Close()
+
+	allowances := []AllowanceByPartitionStruct{}
+	for ownerIterator.HasNext() {
+		queryResponse, err := ownerIterator.Next()
+		if err != nil {
+			return err
+		}
+
+		allowance := AllowanceByPartitionStruct{}
+		if err := json.Unmarshal(queryResponse.Value, &allowance); err != nil {
+			return err
+		}
+
+		err = scaleAllowance(ctx, &allowance)
+		if err != nil {
+			return err
+		}
+		allowances = append(allowances, allowance)
+	}
+
+	// spender 인 allowance (spender 는 key 의 첫번째 attribute 가 아니므로 rich query 사용)
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Allowance)
+	queryBuilder.AddSelectorGroup(FieldSpender, oldAddress)
+
+	spenderIterator, err := ctx.GetStub().GetQueryResult(queryBuilder.MakeQueryString())
+	if err != nil {
+		return err
+	}
+	defer spenderIterator.Close()
+
+	for spenderIterator.HasNext() {
+		queryResponse, err := spenderIterator.Next()
+		if err != nil {
+			return err
+		}
+
+		allowance := AllowanceByPartitionStruct{}
+		if err := json.Unmarshal(queryResponse.Value, &allowance); err != nil {
+			return err
+		}
+
+		// owner 쪽에서 이미 처리
+		if allowance.Owner == oldAddress {
+			continue
+		}
+
+		err = scaleAllowance(ctx, &allowance)
+		if err != nil {
+			return err
+		}
+		allowances = append(allowances, allowance)
+	}
+
+	for _, allowance := range allowances {
+		oldKey, err := ctx.GetStub().CreateCompositeKey(allowanceByPartitionPrefix, []string{allowance.Owner, allowance.Spender, allowance.Partition})
+		if err != nil {
+			return fmt.Errorf("failed to create the composite key for prefix %s: %v", allowanceByPartitionPrefix, err)
+		}
+
+		err = ctx.GetStub().DelState(oldKey)
+		if err != nil {
+			return err
+		}
+
+		if allowance.Owner == oldAddress {
+			allowance.Owner = newAddress
+		}
+		if allowance.Spender == oldAddress {
+			allowance.Spender = newAddress
+		}
+
+		// 자기 자신에 대한 allowance 는 의미가 없으므로 삭제만
+		if allowance.Owner == allowance.Spender {
+			continue
+		}
+
+		newKey, err := ctx.GetStub().CreateCompositeKey(allowanceByPartitionPrefix, []string{allowance.Owner, allowance.Spender, allowance.Partition})
+		if err != nil {
+			return fmt.Errorf("failed to create the composite key for prefix %s: %v", allowanceByPartitionPrefix, err)
+		}
+
+		exist, err := ledgermanager.CheckExistState(newKey, ctx)
+		if err != nil {
+			return err
+		}
+
+		if exist {
+			existing, err := AllowanceByPartition(ctx, allowance.Owner, allowance.Spender, allowance.Partition)
+			if err != nil {
+				return err
+			}
+			allowance.Amount += existing.Amount
+		}
+
+		err = ApproveByPartition(ctx, allowance)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MigrateHolder partition holder list 의 oldAddress 항목을 newAddress 로 옮김
+func MigrateHolder(ctx contractapi.TransactionContextInterface, partition string, oldAddress string, newAddress string) error {
+
+	listKey, err := ctx.GetStub().CreateCompositeKey(DocType_TokenHolderList, []string{partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TokenHolderList, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(listKey, ctx)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		return nil
+	}
+
+	listBytes, err := ledgermanager.GetState(DocType_TokenHolderList, listKey, ctx)
+	if err != nil {
+		return err
+	}
+
+	list := TokenHolderList{}
+	err = json.Unmarshal(listBytes, &list)
+	if err != nil {
+		return err
+	}
+
+	oldEntry, exist := list.Recipients[oldAddress]
+	if !exist {
+		return nil
+	}
+
+	newEntry, exist := list.Recipients[newAddress]
+	if exist {
+		newEntry.Amount += oldEntry.Amount
+	} else {
+		newEntry = oldEntry
+	}
+
+	list.Recipients[newAddress] = newEntry
+	delete(list.Recipients, oldAddress)
+
+	listToMap, err := ccutils.StructToMap(list)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_TokenHolderList, listKey, listToMap, ctx)
+}
+
+// LockHolderList 배분(공모 배정 등) 결과를 holder list 에 더하고 잠금. 잠긴 뒤에는 DistributeToken 불가
+func LockHolderList(ctx contractapi.TransactionContextInterface, partition string, allocations map[string]int64) error {
+
+	listKey, err := ctx.GetStub().CreateCompositeKey(DocType_TokenHolderList, []string{partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_TokenHolderList, err)
+	}
+
+	listBytes, err := ledgermanager.GetState(DocType_TokenHolderList, listKey, ctx)
+	if err != nil {
+		return err
+	}
+
+	list := TokenHolderList{}
+	err = json.Unmarshal(listBytes, &list)
+	if err != nil {
+		return err
+	}
+
+	if list.IsLocked {
+		return fmt.Errorf("token holder list of partition %s is already locked", partition)
+	}
+
+	if list.Recipients == nil {
+		list.Recipients = make(map[string]PartitionToken)
+	}
+
+	for address, amount := range allocations {
+		entry := list.Recipients[address]
+		entry.Amount += amount
+		list.Recipients[address] = entry
+	}
+
+	list.PartitionToken = partition
+	list.IsLocked = true
+
+	listToMap, err := ccutils.StructToMap(list)
+	if err != nil {
+		return err
+	}
+
+	return ledgermanager.UpdateState(DocType_TokenHolderList, listKey, listToMap, ctx)
+}
@@ -27,6 +27,10 @@ type TotalSupplyByPartitionStruct struct {
 	TotalSupply int64 `json:"totalSupply"`
 	// Partition Address
 	Partition string `json:"partition"`
+	// 0 이면 무제한
+	SupplyCap int64 `json:"supplyCap"`
+	// TotalSupply, SupplyCap 이 기록된 분할 epoch
+	ScaleEpoch int64 `json:"scaleEpoch"`
 }
 
 type AllowanceByPartitionStruct struct {
@@ -36,6 +40,8 @@ type AllowanceByPartitionStruct struct {
 	Spender   string `json:"spender"`
 	Partition string `json:"partition"`
 	Amount    int64  `json:"amount"`
+	// Amount 가 기록된 분할 epoch
+	ScaleEpoch int64 `json:"scaleEpoch"`
 }
 
 type TransferByPartitionStruct struct {
@@ -64,6 +70,9 @@ type PartitionToken struct {
 	IsLocked  bool   `json:"islocked"`
 	TxId      string `json:"txId"`
 
+	// ERC1644 controller 강제 이전 가능 여부
+	IsControllable bool `json:"isControllable"`
+
 	Publisher string `json:"publisher"`
 
 	CreatedDate string `json:"createdDate"`
@@ -71,13 +80,15 @@ type PartitionToken struct {
 	ExpiredDate string `json:"expiredDate"`
 
 	Amount int64 `json:"amount"`
+	// Amount 가 기록된 분할 epoch. 조회 시 현재 epoch 으로 환산
+	ScaleEpoch int64 `json:"scaleEpoch"`
 }
 
 // 분배 받을 사람 배열
 type TokenHolderList struct {
 	DocType string `json:"docType"`
 
-	IsLocked bool `json:"isLocked`
+	IsLocked bool `json:"isLocked"`
 
 	PartitionToken string `json:"partitionToken"`
 	// recipient의 변동을 생각해 이도 map으로 짜는게 낫긴 함.
@@ -0,0 +1,13 @@
+package vesting
+
+const CodeErrorInsufficientVested int = 720
+const CodeErrorScheduleExists int = 721
+const CodeErrorScheduleNotFound int = 722
+const CodeErrorScheduleRevoked int = 723
+
+var ErrorCodeMessage = map[int]string{
+	CodeErrorInsufficientVested: "Vesting error : amount exceeds the vested balance",
+	CodeErrorScheduleExists:     "Vesting error : active vesting schedule already exists",
+	CodeErrorScheduleNotFound:   "Vesting error : vesting schedule does not exist",
+	CodeErrorScheduleRevoked:    "Vesting error : vesting schedule is already revoked",
+}
@@ -0,0 +1,20 @@
+package vesting
+
+const (
+	FieldHolder      string = "holder"
+	FieldPartition   string = "partition"
+	FieldKind        string = "kind"
+	FieldTotalAmount string = "totalAmount"
+	FieldStartAt     string = "startAt"
+	FieldCliffAt     string = "cliffAt"
+	FieldEndAt       string = "endAt"
+	FieldStepSeconds string = "stepSeconds"
+	FieldIsRevoked   string = "isRevoked"
+	FieldTreasury    string = "treasury"
+
+	FieldBalance      string = "balance"
+	FieldVested       string = "vested"
+	FieldUnvested     string = "unvested"
+	FieldFrozen       string = "frozen"
+	FieldTransferable string = "transferable"
+)
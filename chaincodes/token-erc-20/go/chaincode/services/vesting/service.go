@@ -0,0 +1,251 @@
+package vesting
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+)
+
+func CreateSchedule(ctx contractapi.TransactionContextInterface, schedule VestingScheduleStruct) (*VestingScheduleStruct, error) {
+
+	if !IsValidKind(schedule.Kind) {
+		return nil, fmt.Errorf("unknown vesting kind : %s", schedule.Kind)
+	}
+
+	if schedule.TotalAmount <= 0 {
+		return nil, fmt.Errorf("vesting amount must be a positive integer")
+	}
+
+	// cliff 생략 시 startAt
+	if schedule.CliffAt == 0 {
+		schedule.CliffAt = schedule.StartAt
+	}
+
+	switch schedule.Kind {
+	case KindCliff:
+		schedule.EndAt = schedule.CliffAt
+		schedule.StepSeconds = 0
+	case KindLinear:
+		schedule.StepSeconds = 0
+		if schedule.EndAt <= schedule.StartAt {
+			return nil, fmt.Errorf("vesting end must be after the start")
+		}
+	case KindStepped:
+		if schedule.StepSeconds <= 0 {
+			return nil, fmt.Errorf("step seconds must be a positive integer")
+		}
+		if schedule.EndAt-schedule.StartAt < schedule.StepSeconds {
+			return nil, fmt.Errorf("vesting period must contain at least one step")
+		}
+	}
+
+	if schedule.CliffAt < schedule.StartAt || schedule.CliffAt > schedule.EndAt {
+		return nil, fmt.Errorf("vesting cliff must be between the start and the end")
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := GetSchedule(ctx, schedule.Holder, schedule.Partition)
+	if err != nil {
+		return nil, err
+	}
+
+	// 전부 해제되었거나 회수된 schedule 은 새 schedule 로 교체 가능
+	if existing != nil && existing.UnvestedAmount(now) > 0 {
+		return nil, ccutils.CreateError(CodeErrorScheduleExists, fmt.Errorf(ErrorCodeMessage[CodeErrorScheduleExists]+" : "+schedule.Holder+", "+schedule.Partition))
+	}
+
+	schedule.IsRevoked = false
+	schedule.RevokedAt = 0
+	schedule.RevokedAmount = 0
+	schedule.CreatedAt = now
+
+	return putSchedule(ctx, schedule, existing != nil)
+}
+
+// GetSchedule 없으면 nil
+func GetSchedule(ctx contractapi.TransactionContextInterface, holder string, partition string) (*VestingScheduleStruct, error) {
+
+	scheduleKey, err := ctx.GetStub().CreateCompositeKey(DocType_Vesting, []string{holder, partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Vesting, err)
+	}
+
+	exist, err := ledgermanager.CheckExistState(scheduleKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, nil
+	}
+
+	scheduleBytes, err := ledgermanager.GetState(DocType_Vesting, scheduleKey, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := VestingScheduleStruct{}
+	if err := json.Unmarshal(scheduleBytes, &schedule); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// GetUnvestedAmount schedule 이 없으면 0
+func GetUnvestedAmount(ctx contractapi.TransactionContextInterface, holder string, partition string) (int64, error) {
+
+	schedule, err := GetSchedule(ctx, holder, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	if schedule == nil {
+		return 0, nil
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return schedule.UnvestedAmount(now), nil
+}
+
+// Revoke 미해제 수량을 회수 처리하고 회수 수량 반환. 실제 토큰 이동은 호출자가 처리
+func Revoke(ctx contractapi.TransactionContextInterface, holder string, partition string) (*VestingScheduleStruct, error) {
+
+	schedule, err := GetSchedule(ctx, holder, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule == nil {
+		return nil, ccutils.CreateError(CodeErrorScheduleNotFound, fmt.Errorf(ErrorCodeMessage[CodeErrorScheduleNotFound]+" : "+holder+", "+partition))
+	}
+
+	if schedule.IsRevoked {
+		return nil, ccutils.CreateError(CodeErrorScheduleRevoked, fmt.Errorf(ErrorCodeMessage[CodeErrorScheduleRevoked]+" : "+holder+", "+partition))
+	}
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.RevokedAmount = schedule.UnvestedAmount(now)
+	schedule.RevokedAt = now
+	schedule.IsRevoked = true
+
+	return putSchedule(ctx, *schedule, true)
+}
+
+// MigrateSchedule wallet 복구 시 schedule 을 새 주소로 이동
+func MigrateSchedule(ctx contractapi.TransactionContextInterface, partition string, oldAddress string, newAddress string) error {
+
+	schedule, err := GetSchedule(ctx, oldAddress, partition)
+	if err != nil {
+		return err
+	}
+
+	if schedule == nil {
+		return nil
+	}
+
+	oldKey, err := ctx.GetStub().CreateCompositeKey(DocType_Vesting, []string{oldAddress, partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Vesting, err)
+	}
+
+	err = ledgermanager.DeleteState(DocType_Vesting, oldKey, ctx)
+	if err != nil {
+		return err
+	}
+
+	existing, err := GetSchedule(ctx, newAddress, partition)
+	if err != nil {
+		return err
+	}
+
+	schedule.Holder = newAddress
+	_, err = putSchedule(ctx, *schedule, existing != nil)
+	return err
+}
+
+// HasUnvestedSchedule partition 에 아직 해제가 끝나지 않은 schedule 이 있는지
+func HasUnvestedSchedule(ctx contractapi.TransactionContextInterface, partition string) (bool, error) {
+
+	now, err := ccutils.GetTxTimestamp(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Vesting)
+	queryBuilder.AddSelectorGroup(FieldPartition, partition)
+	queryBuilder.AddSelectorGroup(FieldIsRevoked, false)
+	queryBuilder.AddSelectorGroupCondition(FieldEndAt, "$gt", now)
+	return ledgermanager.ExistQueryResult(queryBuilder.MakeQueryString(), ctx)
+}
+
+func GetScheduleList(args map[string]interface{}, pageSize int32, bookmark string, ctx contractapi.TransactionContextInterface) ([]byte, error) {
+
+	queryBuilder := ccutils.QueryBuilder{}
+	queryBuilder.AddSelectorGroup(ledgermanager.DocType, DocType_Vesting)
+
+	// 고유 필드
+	stringParameterFields := []string{FieldHolder, FieldPartition, FieldKind}
+	for _, stringField := range stringParameterFields {
+		if value, exist := args[stringField]; exist {
+			err := ccutils.CheckRequireTypeString([]string{stringField}, args)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder.AddSelectorGroup(stringField, value)
+		}
+	}
+
+	queryString := queryBuilder.MakeQueryString()
+
+	bytes, err := ledgermanager.GetQueryResultWithPagination(queryString, pageSize, bookmark, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes, nil
+}
+
+func putSchedule(ctx contractapi.TransactionContextInterface, schedule VestingScheduleStruct, exist bool) (*VestingScheduleStruct, error) {
+
+	scheduleKey, err := ctx.GetStub().CreateCompositeKey(DocType_Vesting, []string{schedule.Holder, schedule.Partition})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the composite key for prefix %s: %v", DocType_Vesting, err)
+	}
+
+	if exist {
+		scheduleToMap, err := ccutils.StructToMap(schedule)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ledgermanager.UpdateState(DocType_Vesting, scheduleKey, scheduleToMap, ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err = ledgermanager.PutState(DocType_Vesting, scheduleKey, schedule, ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &schedule, nil
+}
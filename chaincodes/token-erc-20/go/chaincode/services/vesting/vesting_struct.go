@@ -0,0 +1,86 @@
+package vesting
+
+import (
+	"math/big"
+)
+
+const (
+	DocType_Vesting = "DOCTYPE_VESTING"
+)
+
+// Release 방식
+const (
+	// cliffAt 에 전량 해제
+	KindCliff = "cliff"
+	// cliffAt 이후 startAt ~ endAt 비율만큼 해제
+	KindLinear = "linear"
+	// cliffAt 이후 stepSeconds 마다 같은 수량씩 해제
+	KindStepped = "stepped"
+)
+
+func IsValidKind(kind string) bool {
+	switch kind {
+	case KindCliff, KindLinear, KindStepped:
+		return true
+	}
+	return false
+}
+
+// (holder, partition) 당 하나. 시각은 모두 unix seconds (tx timestamp 기준)
+type VestingScheduleStruct struct {
+	DocType string `json:"docType"`
+
+	Holder      string `json:"holder"`
+	Partition   string `json:"partition"`
+	Kind        string `json:"kind"`
+	TotalAmount int64  `json:"totalAmount"`
+	StartAt     int64  `json:"startAt"`
+	CliffAt     int64  `json:"cliffAt"`
+	EndAt       int64  `json:"endAt"`
+	StepSeconds int64  `json:"stepSeconds"`
+
+	IsRevoked     bool  `json:"isRevoked"`
+	RevokedAt     int64 `json:"revokedAt"`
+	RevokedAmount int64 `json:"revokedAmount"`
+
+	CreatedBy string `json:"createdBy"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// VestedAmount now 시점까지 해제된 수량
+func (s VestingScheduleStruct) VestedAmount(now int64) int64 {
+
+	if s.IsRevoked {
+		return s.TotalAmount - s.RevokedAmount
+	}
+
+	if now < s.CliffAt {
+		return 0
+	}
+
+	if s.Kind == KindCliff || now >= s.EndAt {
+		return s.TotalAmount
+	}
+
+	elapsed := now - s.StartAt
+	duration := s.EndAt - s.StartAt
+
+	if s.Kind == KindStepped {
+		elapsed = elapsed / s.StepSeconds
+		duration = duration / s.StepSeconds
+	}
+
+	// totalAmount * elapsed 가 int64 를 넘을 수 있어 big.Int 로 계산
+	vested := new(big.Int).Mul(big.NewInt(s.TotalAmount), big.NewInt(elapsed))
+	vested.Quo(vested, big.NewInt(duration))
+
+	return vested.Int64()
+}
+
+// UnvestedAmount now 시점에 아직 잠겨 있는 수량
+func (s VestingScheduleStruct) UnvestedAmount(now int64) int64 {
+	if s.IsRevoked {
+		return 0
+	}
+	return s.TotalAmount - s.VestedAmount(now)
+}
@@ -0,0 +1,216 @@
+package wallet_test
+
+import (
+	"testing"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/holders"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/test"
+)
+
+const (
+	partition     = "BOND"
+	cashPartition = "KRW"
+)
+
+func expectHolders(t *testing.T, ledger *test.MockLedger, partition string, expected int64) {
+
+	t.Helper()
+	holderCount, err := holders.GetHolderCount(ledger.Ctx, partition)
+	ledger.Check(err)
+	if holderCount.Count != expected {
+		t.Errorf("%s holder count = %d, expected %d", partition, holderCount.Count, expected)
+	}
+
+	// 카운터와 잔고 레코드 전체를 센 값이 같아야 함
+	counted, err := wallet.CountHolders(ledger.Ctx, partition)
+	ledger.Check(err)
+	if counted != holderCount.Count {
+		t.Errorf("%s counted holders = %d, counter = %d", partition, counted, holderCount.Count)
+	}
+}
+
+func TestHolderCountOnMint(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice", "bob")
+
+	ledger.Mint("alice", partition, 10)
+	expectHolders(t, ledger, partition, 1)
+
+	ledger.Mint("alice", partition, 5)
+	expectHolders(t, ledger, partition, 1)
+
+	ledger.Mint("bob", partition, 1)
+	expectHolders(t, ledger, partition, 2)
+}
+
+func TestHolderCountOnTransferBatch(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice", "bob")
+	ledger.Mint("alice", partition, 100)
+	ledger.Mint("bob", partition, 10)
+
+	escrow := wallet.EscrowAddress("ORDERBOOK")
+
+	// alice 는 전량을 보내 보유자에서 빠지고, 새 wallet carol 은 추가, escrow 는 세지 않음
+	ledger.Tx()
+	err := wallet.TransferBatchByPartition(ledger.Ctx, "alice", partition, []token.TransferByPartitionStruct{
+		{From: "alice", To: "bob", Partition: partition, Amount: 30},
+		{From: "alice", To: "carol", Partition: partition, Amount: 50},
+		{From: "alice", To: escrow, Partition: partition, Amount: 20},
+	})
+	ledger.Check(err)
+
+	expectHolders(t, ledger, partition, 2)
+
+	// escrow 에서 나가는 이전도 escrow 는 세지 않음
+	ledger.Tx()
+	err = wallet.TransferBatchByPartition(ledger.Ctx, escrow, partition, []token.TransferByPartitionStruct{
+		{From: escrow, To: "dave", Partition: partition, Amount: 20},
+	})
+	ledger.Check(err)
+
+	expectHolders(t, ledger, partition, 3)
+}
+
+func TestHolderCountOnTransferBatchRejectsDuplicateRecipient(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice")
+	ledger.Mint("alice", partition, 100)
+
+	ledger.Tx()
+	err := wallet.TransferBatchByPartition(ledger.Ctx, "alice", partition, []token.TransferByPartitionStruct{
+		{From: "alice", To: "bob", Partition: partition, Amount: 10},
+		{From: "alice", To: "bob", Partition: partition, Amount: 10},
+	})
+	if err == nil {
+		t.Error("expected duplicate recipient error")
+	}
+}
+
+func TestHolderCountOnSettleBatch(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Issue(cashPartition)
+	escrow := wallet.EscrowAddress("ORDERBOOK")
+	ledger.Wallet("seller", "buyer", escrow)
+	ledger.Mint(escrow, partition, 40)
+	ledger.Mint(escrow, cashPartition, 4000)
+	ledger.Mint("seller", partition, 5)
+
+	expectHolders(t, ledger, partition, 1)
+	expectHolders(t, ledger, cashPartition, 0)
+
+	// 주문 체결처럼 escrow 에서 두 partition 을 여러 수령인에게 한 번에 지급
+	ledger.Tx()
+	err := wallet.SettleBatch(ledger.Ctx, []token.TransferByPartitionStruct{
+		{From: escrow, To: "buyer", Partition: partition, Amount: 25},
+		{From: escrow, To: "seller", Partition: cashPartition, Amount: 2500},
+		{From: escrow, To: "buyer", Partition: partition, Amount: 15},
+		{From: escrow, To: "buyer", Partition: cashPartition, Amount: 100},
+	})
+	ledger.Check(err)
+
+	expectHolders(t, ledger, partition, 2)
+	expectHolders(t, ledger, cashPartition, 2)
+
+	if balance := ledger.Balance("buyer", partition); balance != 40 {
+		t.Errorf("buyer balance = %d, expected 40", balance)
+	}
+}
+
+func TestHolderCountOnMigrateWallet(t *testing.T) {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("lost", "recovered", "other")
+	ledger.Mint("lost", partition, 10)
+	ledger.Mint("other", partition, 10)
+
+	ledger.Tx()
+	partitions, err := wallet.MigrateWallet(ledger.Ctx, "lost", "recovered")
+	ledger.Check(err)
+	if len(partitions) != 1 || partitions[0] != partition {
+		t.Errorf("migrated partitions = %v", partitions)
+	}
+
+	expectHolders(t, ledger, partition, 2)
+
+	recovered, err := wallet.GetWallet(ledger.Ctx, "recovered")
+	ledger.Check(err)
+	if len(recovered.RecoveredFrom) != 1 || recovered.RecoveredFrom[0] != "lost" {
+		t.Errorf("recoveredFrom = %v, expected [lost]", recovered.RecoveredFrom)
+	}
+
+	// 이미 보유자인 wallet 으로 합치면 보유자 수가 줄어듦
+	ledger.Tx()
+	_, err = wallet.MigrateWallet(ledger.Ctx, "other", "recovered")
+	ledger.Check(err)
+
+	expectHolders(t, ledger, partition, 1)
+
+	recovered, err = wallet.GetWallet(ledger.Ctx, "recovered")
+	ledger.Check(err)
+	if len(recovered.RecoveredFrom) != 2 {
+		t.Errorf("recoveredFrom = %v, expected two wallets", recovered.RecoveredFrom)
+	}
+
+	ledger.Tx()
+	err = wallet.CheckNotSuperseded(ledger.Ctx, "lost")
+	if err == nil {
+		t.Error("expected superseded wallet error")
+	}
+}
+
+// MockStub 은 실패한 tx 의 쓰기를 되돌리지 않으므로 거절과 성공을 원장을 나누어 확인
+func cappedLedger(t *testing.T, maxHolders int64) *test.MockLedger {
+
+	ledger := test.NewMockLedger(t)
+	ledger.Issue(partition)
+	ledger.Wallet("alice")
+	ledger.Mint("alice", partition, 100)
+
+	ledger.Tx()
+	_, err := holders.SetMaxHolders(ledger.Ctx, partition, maxHolders)
+	ledger.Check(err)
+
+	return ledger
+}
+
+func TestMaxHoldersBlocksNewHolderInTransferBatch(t *testing.T) {
+
+	ledger := cappedLedger(t, 2)
+
+	// alice 가 남아 있으면 새 보유자 둘로 3 명이 됨
+	ledger.Tx()
+	err := wallet.TransferBatchByPartition(ledger.Ctx, "alice", partition, []token.TransferByPartitionStruct{
+		{From: "alice", To: "bob", Partition: partition, Amount: 10},
+		{From: "alice", To: "carol", Partition: partition, Amount: 10},
+	})
+	if err == nil {
+		t.Error("expected max holders error")
+	}
+}
+
+func TestMaxHoldersAllowsDrainingTransferBatch(t *testing.T) {
+
+	ledger := cappedLedger(t, 2)
+
+	// alice 가 전량을 보내면 2 명
+	ledger.Tx()
+	err := wallet.TransferBatchByPartition(ledger.Ctx, "alice", partition, []token.TransferByPartitionStruct{
+		{From: "alice", To: "bob", Partition: partition, Amount: 60},
+		{From: "alice", To: "carol", Partition: partition, Amount: 40},
+	})
+	ledger.Check(err)
+
+	expectHolders(t, ledger, partition, 2)
+}
@@ -4,11 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/holders"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/snapshot"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/split"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
 )
 
@@ -53,28 +57,43 @@ func TransferByPartition(ctx contractapi.TransactionContextInterface, transferBy
 	// 	return fmt.Errorf("partition data is not exist")
 	// }
 
-	fromCurrentBalance := fromWallet.PartitionTokens[transferByPartition.Partition][0].Amount
+	fromEntry := &fromWallet.PartitionTokens[transferByPartition.Partition][0]
+	fromRawBalance, err := touchBalance(ctx, transferByPartition.From, transferByPartition.Partition, fromEntry)
+	if err != nil {
+		return err
+	}
+
+	fromCurrentBalance := fromEntry.Amount
 	if fromCurrentBalance < transferByPartition.Amount {
 		return fmt.Errorf("client account %s has insufficient funds", fromWallet.TokenWalletId)
 	}
 
 	// Math
 	fromUpdatedBalance := fromCurrentBalance - transferByPartition.Amount
-	fromWallet.PartitionTokens[transferByPartition.Partition][0].Amount = fromUpdatedBalance
-
-	// toUpdatedBalance := toCurrentBalance + transferByPartition.Amount
-	// toWallet.PartitionTokens[transferByPartition.Partition][0].Amount = toUpdatedBalance
+	fromEntry.Amount = fromUpdatedBalance
 
-	var toUpdatedBalance int64
 	// 우선 이렇게 처리
 	if reflect.ValueOf(toWallet.PartitionTokens[transferByPartition.Partition]).IsZero() {
-		partitionTokenMap := make(map[string][]token.PartitionToken)
-		partitionTokenMap[transferByPartition.Partition] = append(partitionTokenMap[transferByPartition.Partition], token.PartitionToken{Amount: transferByPartition.Amount})
-		toWallet.PartitionTokens = partitionTokenMap
-		toUpdatedBalance = transferByPartition.Amount
-	} else {
-		toWallet.PartitionTokens[transferByPartition.Partition][0].Amount += transferByPartition.Amount
-		toUpdatedBalance = toWallet.PartitionTokens[transferByPartition.Partition][0].Amount
+		// 다른 partition 잔고를 덮어쓰지 않도록 해당 partition 만 추가
+		if toWallet.PartitionTokens == nil {
+			toWallet.PartitionTokens = make(map[string][]token.PartitionToken)
+		}
+		toWallet.PartitionTokens[transferByPartition.Partition] = []token.PartitionToken{{}}
+	}
+
+	toEntry := &toWallet.PartitionTokens[transferByPartition.Partition][0]
+	toRawBalance, err := touchBalance(ctx, transferByPartition.To, transferByPartition.Partition, toEntry)
+	if err != nil {
+		return err
+	}
+
+	toEntry.Amount += transferByPartition.Amount
+	toUpdatedBalance := toEntry.Amount
+
+	holderDelta := HolderDelta(transferByPartition.From, fromRawBalance, fromUpdatedBalance) + HolderDelta(transferByPartition.To, toRawBalance, toUpdatedBalance)
+	err = holders.Apply(ctx, transferByPartition.Partition, holderDelta)
+	if err != nil {
+		return err
 	}
 
 	fromToMap, err := ccutils.StructToMap(fromWallet)
@@ -96,45 +115,314 @@ func TransferByPartition(ctx contractapi.TransactionContextInterface, transferBy
 	}
 
 	// balanceOf
-	fromBalanceKey, err := ctx.GetStub().CreateCompositeKey(token.BalanceOfByPartitionPrefix, []string{transferByPartition.From, transferByPartition.Partition})
+	err = putBalance(ctx, transferByPartition.From, transferByPartition.Partition, fromUpdatedBalance, fromEntry.ScaleEpoch)
 	if err != nil {
 		return err
 	}
 
-	fromPartitionToken := token.PartitionToken{}
-	fromPartitionToken.DocType = token.DocType_Token
-	fromPartitionToken.Amount = fromUpdatedBalance
-	fromPartitionTokenBytes, err := json.Marshal(fromPartitionToken)
+	return putBalance(ctx, transferByPartition.To, transferByPartition.Partition, toUpdatedBalance, toEntry.ScaleEpoch)
+}
+
+// TransferBatchByPartition from 의 partition 잔고에서 여러 수령인에게 한 번에 이전.
+// 같은 tx 에서 from wallet 을 여러 번 쓰면 마지막 값만 남으므로 배당/정산 일괄 지급은 이 함수로 처리.
+// 수령인 wallet 이 없으면 새로 만듦 (escrow wallet 최초 입금)
+func TransferBatchByPartition(ctx contractapi.TransactionContextInterface, from string, partition string, transfers []token.TransferByPartitionStruct) error {
+
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	fromWallet, err := GetWallet(ctx, from)
 	if err != nil {
 		return err
 	}
 
-	err = ctx.GetStub().PutState(fromBalanceKey, fromPartitionTokenBytes)
+	if reflect.ValueOf(fromWallet.PartitionTokens[partition]).IsZero() {
+		return fmt.Errorf("partition data in From Wallet does not exist")
+	}
+
+	var totalAmount int64
+	seen := make(map[string]bool)
+	for _, transfer := range transfers {
+		if transfer.Amount <= 0 {
+			return fmt.Errorf("invalid amount for recipient %s", transfer.To)
+		}
+
+		if transfer.To == from || seen[transfer.To] {
+			return fmt.Errorf("duplicate recipient in batch transfer : %s", transfer.To)
+		}
+		seen[transfer.To] = true
+
+		totalAmount += transfer.Amount
+	}
+
+	fromEntry := &fromWallet.PartitionTokens[partition][0]
+	fromRawBalance, err := touchBalance(ctx, from, partition, fromEntry)
 	if err != nil {
 		return err
 	}
 
-	toBalanceKey, err := ctx.GetStub().CreateCompositeKey(token.BalanceOfByPartitionPrefix, []string{transferByPartition.To, transferByPartition.Partition})
+	fromCurrentBalance := fromEntry.Amount
+	if fromCurrentBalance < totalAmount {
+		return fmt.Errorf("client account %s has insufficient funds", from)
+	}
+
+	fromUpdatedBalance := fromCurrentBalance - totalAmount
+	fromEntry.Amount = fromUpdatedBalance
+
+	holderDelta := HolderDelta(from, fromRawBalance, fromUpdatedBalance)
+
+	for _, transfer := range transfers {
+		toExist, err := ledgermanager.CheckExistState(transfer.To, ctx)
+		if err != nil {
+			return err
+		}
+
+		toWallet := &TokenWallet{TokenWalletId: transfer.To}
+		if toExist {
+			toWallet, err = GetWallet(ctx, transfer.To)
+			if err != nil {
+				return err
+			}
+		}
+
+		if toWallet.PartitionTokens == nil {
+			toWallet.PartitionTokens = make(map[string][]token.PartitionToken)
+		}
+
+		if reflect.ValueOf(toWallet.PartitionTokens[partition]).IsZero() {
+			toWallet.PartitionTokens[partition] = []token.PartitionToken{{}}
+		}
+
+		toEntry := &toWallet.PartitionTokens[partition][0]
+		toRawBalance, err := touchBalance(ctx, transfer.To, partition, toEntry)
+		if err != nil {
+			return err
+		}
+
+		toEntry.Amount += transfer.Amount
+		toUpdatedBalance := toEntry.Amount
+
+		holderDelta += HolderDelta(transfer.To, toRawBalance, toUpdatedBalance)
+
+		if toExist {
+			toToMap, err := ccutils.StructToMap(toWallet)
+			if err != nil {
+				return err
+			}
+
+			err = ledgermanager.UpdateState(DocType_TokenWallet, transfer.To, toToMap, ctx)
+			if err != nil {
+				return err
+			}
+		} else {
+			_, err = CreateWallet(ctx, *toWallet)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = putBalance(ctx, transfer.To, partition, toUpdatedBalance, toEntry.ScaleEpoch)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = holders.Apply(ctx, partition, holderDelta)
 	if err != nil {
 		return err
 	}
 
-	toPartitionToken := token.PartitionToken{}
-	toPartitionToken.DocType = token.DocType_Token
-	toPartitionToken.Amount = toUpdatedBalance
-	toPartitionTokenBytes, err := json.Marshal(toPartitionToken)
+	fromToMap, err := ccutils.StructToMap(fromWallet)
 	if err != nil {
 		return err
 	}
 
-	err = ctx.GetStub().PutState(toBalanceKey, toPartitionTokenBytes)
+	err = ledgermanager.UpdateState(DocType_TokenWallet, from, fromToMap, ctx)
 	if err != nil {
 		return err
 	}
 
+	return putBalance(ctx, from, partition, fromUpdatedBalance, fromEntry.ScaleEpoch)
+}
+
+// SettleBatch 여러 partition, 여러 송신자의 이전을 wallet 당 한 번의 쓰기로 반영.
+// 같은 tx 에서 한 wallet 을 두 번 쓰면 앞의 변경이 사라지므로, 한 wallet 이 여러 partition 을 주고받는 정산(주문 체결 등)에 사용
+func SettleBatch(ctx contractapi.TransactionContextInterface, transfers []token.TransferByPartitionStruct) error {
+
+	deltas := make(map[string]map[string]int64)
+	for _, transfer := range transfers {
+		if transfer.Amount <= 0 {
+			return fmt.Errorf("invalid amount for recipient %s", transfer.To)
+		}
+
+		if transfer.From == transfer.To {
+			return fmt.Errorf("cannot transfer to the same wallet")
+		}
+
+		for address, delta := range map[string]int64{transfer.From: -transfer.Amount, transfer.To: transfer.Amount} {
+			if deltas[address] == nil {
+				deltas[address] = make(map[string]int64)
+			}
+			deltas[address][transfer.Partition] += delta
+		}
+	}
+
+	// endorsing peer 마다 같은 순서로 기록되도록 정렬
+	addresses := make([]string, 0, len(deltas))
+	for address := range deltas {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	holderDeltas := make(map[string]int64)
+	for _, address := range addresses {
+		exist, err := ledgermanager.CheckExistState(address, ctx)
+		if err != nil {
+			return err
+		}
+
+		tokenWallet := &TokenWallet{TokenWalletId: address}
+		if exist {
+			tokenWallet, err = GetWallet(ctx, address)
+			if err != nil {
+				return err
+			}
+		}
+
+		if tokenWallet.PartitionTokens == nil {
+			tokenWallet.PartitionTokens = make(map[string][]token.PartitionToken)
+		}
+
+		partitions := make([]string, 0, len(deltas[address]))
+		for partition := range deltas[address] {
+			partitions = append(partitions, partition)
+		}
+		sort.Strings(partitions)
+
+		for _, partition := range partitions {
+			delta := deltas[address][partition]
+			if delta == 0 {
+				continue
+			}
+
+			if reflect.ValueOf(tokenWallet.PartitionTokens[partition]).IsZero() {
+				tokenWallet.PartitionTokens[partition] = []token.PartitionToken{{}}
+			}
+
+			entry := &tokenWallet.PartitionTokens[partition][0]
+			rawBalance, err := touchBalance(ctx, address, partition, entry)
+			if err != nil {
+				return err
+			}
+
+			if entry.Amount+delta < 0 {
+				return fmt.Errorf("client account %s has insufficient funds", address)
+			}
+			entry.Amount += delta
+
+			holderDeltas[partition] += HolderDelta(address, rawBalance, entry.Amount)
+
+			err = putBalance(ctx, address, partition, entry.Amount, entry.ScaleEpoch)
+			if err != nil {
+				return err
+			}
+		}
+
+		if exist {
+			walletToMap, err := ccutils.StructToMap(tokenWallet)
+			if err != nil {
+				return err
+			}
+
+			err = ledgermanager.UpdateState(DocType_TokenWallet, address, walletToMap, ctx)
+			if err != nil {
+				return err
+			}
+		} else {
+			_, err = CreateWallet(ctx, *tokenWallet)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	partitions := make([]string, 0, len(holderDeltas))
+	for partition := range holderDeltas {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+
+	for _, partition := range partitions {
+		err := holders.Apply(ctx, partition, holderDeltas[partition])
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// EscrowAddress 배당/청약/정산 등 모듈이 자금을 보관하는 chaincode 소유 wallet 주소
+func EscrowAddress(name string) string {
+	return ccutils.GetAddress([]byte(EscrowPrefix + name))
+}
+
+func IsEscrowAddress(address string) bool {
+	for _, name := range EscrowNames {
+		if EscrowAddress(name) == address {
+			return true
+		}
+	}
+	return false
+}
+
+// HolderDelta escrow wallet 은 보유자 수에 넣지 않음
+func HolderDelta(address string, before int64, after int64) int64 {
+	if IsEscrowAddress(address) {
+		return 0
+	}
+	return holders.Delta(before, after)
+}
+
+// CountHolders partition 잔고(환산 전)가 0 보다 큰 escrow 가 아닌 주소 수. 전체 잔고 레코드를 훑으므로 보유자 수 초기화에만 사용
+func CountHolders(ctx contractapi.TransactionContextInterface, partition string) (int64, error) {
+
+	balanceIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(token.BalanceOfByPartitionPrefix, []string{})
+	if err != nil {
+		return 0, err
+	}
+	defer balanceIterator.Close()
+
+	var count int64
+	for balanceIterator.HasNext() {
+		queryResponse, err := balanceIterator.Next()
+		if err != nil {
+			return 0, err
+		}
+
+		_, keyParts, err := ctx.GetStub().SplitCompositeKey(queryResponse.Key)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(keyParts) != 2 || keyParts[1] != partition || IsEscrowAddress(keyParts[0]) {
+			continue
+		}
+
+		balance := token.PartitionToken{}
+		if err := json.Unmarshal(queryResponse.Value, &balance); err != nil {
+			return 0, err
+		}
+
+		if balance.Amount > 0 {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
 func MintByPartition(ctx contractapi.TransactionContextInterface, mintByPartition token.MintByPartitionStruct) error {
 
 	walletBytes, err := ledgermanager.GetState(DocType_TokenWallet, mintByPartition.Minter, ctx)
@@ -154,6 +442,26 @@ func MintByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		exist = false
 	}
 
+	entry := token.PartitionToken{}
+	if exist {
+		entry = wallet.PartitionTokens[mintByPartition.Partition][0]
+	}
+
+	rawBalance, err := touchBalance(ctx, mintByPartition.Minter, mintByPartition.Partition, &entry)
+	if err != nil {
+		return err
+	}
+
+	beforeBalance := entry.Amount
+	if exist {
+		wallet.PartitionTokens[mintByPartition.Partition][0] = entry
+	}
+
+	err = holders.Apply(ctx, mintByPartition.Partition, HolderDelta(mintByPartition.Minter, rawBalance, beforeBalance+mintByPartition.Amount))
+	if err != nil {
+		return err
+	}
+
 	// wallet.PartitionTokens[mintByPartition.Partition] = append(wallet.PartitionTokens[mintByPartition.Partition], token.PartitionToken{Amount: mintByPartition.Amount})
 	// wallet.PartitionTokens[mintByPartition.Partition] = append(wallet.PartitionTokens[mintByPartition.Partition], token.PartitionToken{Amount: mintByPartition.Amount})
 
@@ -196,7 +504,7 @@ func MintByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		}
 
 		afterBalance = wallet.PartitionTokens[mintByPartition.Partition][0].Amount
-		partitionToken := token.PartitionToken{Amount: afterBalance}
+		partitionToken := token.PartitionToken{Amount: afterBalance, ScaleEpoch: entry.ScaleEpoch}
 		balanceOfByPartitionToMap, err := ccutils.StructToMap(partitionToken)
 		if err != nil {
 			return err
@@ -225,7 +533,7 @@ func MintByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		partitionTokenMap[mintByPartition.Partition] = append(partitionTokenMap[mintByPartition.Partition], token.PartitionToken{Amount: mintByPartition.Amount})
 
 		wallet.PartitionTokens[mintByPartition.Partition] = partitionTokenMap[mintByPartition.Partition]
-		wallet.PartitionTokens[mintByPartition.Partition][0] = token.PartitionToken{Amount: mintByPartition.Amount}
+		wallet.PartitionTokens[mintByPartition.Partition][0] = token.PartitionToken{Amount: mintByPartition.Amount, ScaleEpoch: entry.ScaleEpoch}
 
 		mintByPartitionToMap, err := ccutils.StructToMap(wallet)
 		if err != nil {
@@ -240,6 +548,7 @@ func MintByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		afterBalance = wallet.PartitionTokens[mintByPartition.Partition][0].Amount
 		partitionToken := token.PartitionToken{}
 		partitionToken.Amount = afterBalance
+		partitionToken.ScaleEpoch = entry.ScaleEpoch
 		partitionToken.DocType = token.DocType_Token
 
 		partitionTokenBytes, err := json.Marshal(partitionToken)
@@ -293,13 +602,18 @@ func MintByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		return fmt.Errorf("failed to create the composite key for prefix %s: %v", token.DocType_TotalSupplyByPartition, err)
 	}
 
-	totalSupplyByPartitionBytes, err := ledgermanager.GetState(token.DocType_TotalSupplyByPartition, totalKey, ctx)
+	totalSupplyByPartition, err := token.GetTotalSupplyRecord(ctx, mintByPartition.Partition)
+	if err != nil {
+		return err
+	}
+
+	err = snapshot.CheckpointSupply(ctx, mintByPartition.Partition, totalSupplyByPartition.TotalSupply, totalSupplyByPartition.ScaleEpoch)
 	if err != nil {
 		return err
 	}
 
-	totalSupplyByPartition := token.TotalSupplyByPartitionStruct{}
-	if err := json.Unmarshal(totalSupplyByPartitionBytes, &totalSupplyByPartition); err != nil {
+	err = token.ScaleTotalSupply(ctx, mintByPartition.Partition, totalSupplyByPartition)
+	if err != nil {
 		return err
 	}
 
@@ -335,11 +649,24 @@ func BurnByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		return fmt.Errorf("partition data is not exist")
 	}
 
-	if wallet.PartitionTokens[mintByPartition.Partition][0].Amount < mintByPartition.Amount {
+	entry := &wallet.PartitionTokens[mintByPartition.Partition][0]
+	rawBalance, err := touchBalance(ctx, mintByPartition.Minter, mintByPartition.Partition, entry)
+	if err != nil {
+		return err
+	}
+
+	beforeBalance := entry.Amount
+	if beforeBalance < mintByPartition.Amount {
 		return fmt.Errorf("currentBalance is lower than input amount")
 	}
 
-	wallet.PartitionTokens[mintByPartition.Partition][0].Amount -= mintByPartition.Amount
+	entry.Amount -= mintByPartition.Amount
+	afterBalance := entry.Amount
+
+	err = holders.Apply(ctx, mintByPartition.Partition, HolderDelta(mintByPartition.Minter, rawBalance, afterBalance))
+	if err != nil {
+		return err
+	}
 
 	mintByPartitionToMap, err := ccutils.StructToMap(wallet)
 	if err != nil {
@@ -351,8 +678,18 @@ func BurnByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		return err
 	}
 
+	err = putBalance(ctx, mintByPartition.Minter, mintByPartition.Partition, afterBalance, entry.ScaleEpoch)
+	if err != nil {
+		return err
+	}
+
 	// Distribute List
-	listBytes, err := ledgermanager.GetState(token.DocType_TokenHolderList, mintByPartition.Partition, ctx)
+	listKey, err := ctx.GetStub().CreateCompositeKey(token.DocType_TokenHolderList, []string{mintByPartition.Partition})
+	if err != nil {
+		return fmt.Errorf("failed to create the composite key for prefix %s: %v", token.DocType_TokenHolderList, err)
+	}
+
+	listBytes, err := ledgermanager.GetState(token.DocType_TokenHolderList, listKey, ctx)
 	if err != nil {
 		return err
 	}
@@ -372,7 +709,7 @@ func BurnByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		return err
 	}
 
-	err = ledgermanager.UpdateState(token.DocType_TokenHolderList, mintByPartition.Partition, listToMap, ctx)
+	err = ledgermanager.UpdateState(token.DocType_TokenHolderList, listKey, listToMap, ctx)
 	if err != nil {
 		return err
 	}
@@ -405,13 +742,18 @@ func BurnByPartition(ctx contractapi.TransactionContextInterface, mintByPartitio
 		return fmt.Errorf("failed to create the composite key for prefix %s: %v", token.DocType_TotalSupplyByPartition, err)
 	}
 
-	totalSupplyByPartitionBytes, err := ledgermanager.GetState(token.DocType_TotalSupplyByPartition, totalKey, ctx)
+	totalSupplyByPartition, err := token.GetTotalSupplyRecord(ctx, mintByPartition.Partition)
 	if err != nil {
 		return err
 	}
 
-	totalSupplyByPartition := token.TotalSupplyByPartitionStruct{}
-	if err := json.Unmarshal(totalSupplyByPartitionBytes, &totalSupplyByPartition); err != nil {
+	err = snapshot.CheckpointSupply(ctx, mintByPartition.Partition, totalSupplyByPartition.TotalSupply, totalSupplyByPartition.ScaleEpoch)
+	if err != nil {
+		return err
+	}
+
+	err = token.ScaleTotalSupply(ctx, mintByPartition.Partition, totalSupplyByPartition)
+	if err != nil {
 		return err
 	}
 
@@ -611,6 +953,21 @@ func RedeemToken(ctx contractapi.TransactionContextInterface, redeemToken token.
 		return nil, err
 	}
 
+	if reflect.ValueOf(wallet.PartitionTokens[redeemToken.Partition]).IsZero() {
+		return nil, fmt.Errorf("partition data is not exist")
+	}
+
+	entry := &wallet.PartitionTokens[redeemToken.Partition][0]
+	rawBalance, err := touchBalance(ctx, redeemToken.Holder, redeemToken.Partition, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	err = holders.Apply(ctx, redeemToken.Partition, HolderDelta(redeemToken.Holder, rawBalance, 0))
+	if err != nil {
+		return nil, err
+	}
+
 	wallet.PartitionTokens[redeemToken.Partition][0].Amount = 0
 
 	mintByPartitionToMap, err := ccutils.StructToMap(wallet)
@@ -624,7 +981,7 @@ func RedeemToken(ctx contractapi.TransactionContextInterface, redeemToken token.
 	}
 
 	afterBalance = 0
-	partitionToken := token.PartitionToken{Amount: afterBalance}
+	partitionToken := token.PartitionToken{Amount: afterBalance, ScaleEpoch: entry.ScaleEpoch}
 	balanceOfByPartitionToMap, err := ccutils.StructToMap(partitionToken)
 	if err != nil {
 		return nil, err
@@ -637,3 +994,241 @@ func RedeemToken(ctx contractapi.TransactionContextInterface, redeemToken token.
 
 	return nil, nil
 }
+
+func GetWallet(ctx contractapi.TransactionContextInterface, address string) (*TokenWallet, error) {
+
+	walletBytes, err := ledgermanager.GetState(DocType_TokenWallet, address, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet := TokenWallet{}
+	err = json.Unmarshal(walletBytes, &wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+// CheckNotSuperseded 복구로 대체된 wallet 은 더 이상 사용할 수 없음
+func CheckNotSuperseded(ctx contractapi.TransactionContextInterface, address string) error {
+
+	wallet, err := GetWallet(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if wallet.SupersededBy != "" {
+		return fmt.Errorf("wallet %s is superseded by %s", address, wallet.SupersededBy)
+	}
+
+	return nil
+}
+
+// MigrateWallet oldAddress 의 모든 partition 잔고를 newAddress 로 옮기고 old wallet 을 superseded 로 표시.
+// 옮긴 partition 목록을 돌려줌
+func MigrateWallet(ctx contractapi.TransactionContextInterface, oldAddress string, newAddress string) ([]string, error) {
+
+	if oldAddress == newAddress {
+		return nil, fmt.Errorf("old and new wallet must be different")
+	}
+
+	oldWallet, err := GetWallet(ctx, oldAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldWallet.SupersededBy != "" {
+		return nil, fmt.Errorf("wallet %s is already superseded by %s", oldAddress, oldWallet.SupersededBy)
+	}
+
+	newWallet, err := GetWallet(ctx, newAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if newWallet.SupersededBy != "" {
+		return nil, fmt.Errorf("wallet %s is superseded by %s", newAddress, newWallet.SupersededBy)
+	}
+
+	if newWallet.PartitionTokens == nil {
+		newWallet.PartitionTokens = make(map[string][]token.PartitionToken)
+	}
+
+	partitions := []string{}
+	for partition := range oldWallet.PartitionTokens {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+
+	for _, partition := range partitions {
+		oldEntry := token.PartitionToken{}
+		if len(oldWallet.PartitionTokens[partition]) > 0 {
+			oldEntry = oldWallet.PartitionTokens[partition][0]
+		}
+
+		oldRawBalance, err := touchBalance(ctx, oldAddress, partition, &oldEntry)
+		if err != nil {
+			return nil, err
+		}
+		amount := oldEntry.Amount
+
+		if len(newWallet.PartitionTokens[partition]) == 0 {
+			newWallet.PartitionTokens[partition] = []token.PartitionToken{{}}
+		}
+
+		newEntry := &newWallet.PartitionTokens[partition][0]
+		newRawBalance, err := touchBalance(ctx, newAddress, partition, newEntry)
+		if err != nil {
+			return nil, err
+		}
+
+		newEntry.Amount += amount
+		newBalance := newEntry.Amount
+
+		err = holders.Apply(ctx, partition, HolderDelta(oldAddress, oldRawBalance, 0)+HolderDelta(newAddress, newRawBalance, newBalance))
+		if err != nil {
+			return nil, err
+		}
+
+		err = putBalance(ctx, oldAddress, partition, 0, oldEntry.ScaleEpoch)
+		if err != nil {
+			return nil, err
+		}
+
+		err = putBalance(ctx, newAddress, partition, newBalance, newEntry.ScaleEpoch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	oldWallet.PartitionTokens = make(map[string][]token.PartitionToken)
+	oldWallet.SupersededBy = newAddress
+
+	// snapshot 잔고와 의결권을 new wallet 에서 찾을 수 있도록 기록
+	newWallet.RecoveredFrom = append(append(newWallet.RecoveredFrom, oldAddress), oldWallet.RecoveredFrom...)
+
+	oldToMap, err := ccutils.StructToMap(oldWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_TokenWallet, oldAddress, oldToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newToMap, err := ccutils.StructToMap(newWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_TokenWallet, newAddress, newToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return partitions, nil
+}
+
+// SettleBalance holder 의 partition 잔고를 현재 분할 epoch 기준으로 다시 저장. 단수주는 cash-in-lieu 로 기록
+func SettleBalance(ctx contractapi.TransactionContextInterface, holder string, partition string) (*token.PartitionToken, error) {
+
+	wallet, err := GetWallet(ctx, holder)
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.ValueOf(wallet.PartitionTokens[partition]).IsZero() {
+		return nil, fmt.Errorf("partition data is not exist")
+	}
+
+	entry := &wallet.PartitionTokens[partition][0]
+	rawBalance, err := touchBalance(ctx, holder, partition, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	err = holders.Apply(ctx, partition, HolderDelta(holder, rawBalance, entry.Amount))
+	if err != nil {
+		return nil, err
+	}
+
+	walletToMap, err := ccutils.StructToMap(wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ledgermanager.UpdateState(DocType_TokenWallet, holder, walletToMap, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = putBalance(ctx, holder, partition, entry.Amount, entry.ScaleEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// putBalance balanceOf 조회용 레코드 기록. amount 는 scaleEpoch 기준
+func putBalance(ctx contractapi.TransactionContextInterface, holder string, partition string, amount int64, scaleEpoch int64) error {
+
+	balanceKey, err := ctx.GetStub().CreateCompositeKey(token.BalanceOfByPartitionPrefix, []string{holder, partition})
+	if err != nil {
+		return err
+	}
+
+	partitionToken := token.PartitionToken{}
+	partitionToken.DocType = token.DocType_Token
+	partitionToken.Amount = amount
+	partitionToken.ScaleEpoch = scaleEpoch
+	partitionTokenBytes, err := json.Marshal(partitionToken)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(balanceKey, partitionTokenBytes)
+}
+
+// touchBalance 잔고를 바꾸기 직전에 호출. 환산 전 값으로 snapshot checkpoint 를 남긴 뒤 현재 분할 epoch 기준으로 환산.
+// 보유자 수 계산용으로 환산 전 값을 돌려줌
+func touchBalance(ctx contractapi.TransactionContextInterface, holder string, partition string, entry *token.PartitionToken) (int64, error) {
+
+	rawBalance := entry.Amount
+
+	err := snapshot.Checkpoint(ctx, holder, partition, entry.Amount, entry.ScaleEpoch)
+	if err != nil {
+		return 0, err
+	}
+
+	err = NormalizeBalance(ctx, holder, partition, entry)
+	if err != nil {
+		return 0, err
+	}
+
+	return rawBalance, nil
+}
+
+// NormalizeBalance wallet 의 partition 잔고를 현재 분할 epoch 기준으로 환산. 내림된 단수주는 cash-in-lieu 로 기록
+func NormalizeBalance(ctx contractapi.TransactionContextInterface, holder string, partition string, entry *token.PartitionToken) error {
+
+	scale, err := split.GetScale(ctx, partition)
+	if err != nil {
+		return err
+	}
+
+	amount, fractions := scale.Scale(entry.Amount, entry.ScaleEpoch, scale.Epoch)
+
+	err = split.RecordCashInLieu(ctx, holder, partition, fractions)
+	if err != nil {
+		return err
+	}
+
+	entry.Amount = amount
+	entry.ScaleEpoch = scale.Epoch
+
+	return nil
+}
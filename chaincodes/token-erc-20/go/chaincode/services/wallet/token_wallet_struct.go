@@ -7,6 +7,15 @@ const (
 	DocType_AdminWallet = "DOCTYPE_ADMIN_WALLET"
 )
 
+const (
+	// EscrowAddress 계산에 쓰는 prefix. 사용자 identity 로는 만들 수 없는 주소가 됨
+	EscrowPrefix = "ESCROW:"
+)
+
+// 모듈별 escrow wallet 이름 (orderbook, dvp, offering, dividend, settlement 의 EscrowName 과 같아야 함).
+// 보유자 수에서 제외
+var EscrowNames = []string{"ORDERBOOK", "DVP_SECURITY", "DVP_CASH", "OFFERING", "OFFERING_CASH", "DIVIDEND", "SETTLEMENT"}
+
 type TokenWallet struct {
 	DocType string `json:"docType"`
 
@@ -28,6 +37,12 @@ type TokenWallet struct {
 
 	// PartitionTokens map[string][]interface{}
 	PartitionTokens map[string][]token.PartitionToken `json:"partitionTokens"`
+
+	// 키 분실 복구로 이전된 경우 새 wallet 주소
+	SupersededBy string `json:"supersededBy"`
+	// 복구로 이 wallet 에 합쳐진 이전 wallet 주소 (이전 wallet 의 RecoveredFrom 포함).
+	// UpdateState 가 null 로 저장된 필드와 병합하지 못하므로 비어 있으면 생략
+	RecoveredFrom []string `json:"recoveredFrom,omitempty"`
 }
 
 type AdminWallet struct {
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
+)
+
+// MockLedger service 단위 테스트용 MockStub 원장. 쓰기 전에 Tx 로 트랜잭션을 시작해야 함.
+// MockStub 은 같은 tx 에서 쓴 값을 바로 읽으므로 한 tx 에 한 번만 쓰는 service 는 호출마다 Tx 를 새로 시작
+type MockLedger struct {
+	T    *testing.T
+	Ctx  *contractapi.TransactionContext
+	Stub *shimtest.MockStub
+
+	txCount int
+	Now     int64
+}
+
+func NewMockLedger(t *testing.T) *MockLedger {
+
+	stub := shimtest.NewMockStub("token", nil)
+	ctx := new(contractapi.TransactionContext)
+	ctx.SetStub(stub)
+
+	ledger := &MockLedger{T: t, Ctx: ctx, Stub: stub, Now: 1700000000}
+
+	ledger.Tx()
+	_, err := ledgermanager.PutState(token.DocType_TotalSupply, "TotalSupply", token.TotalSupplyStruct{TotalSupply: 0}, ctx)
+	ledger.Check(err)
+
+	return ledger
+}
+
+// Tx 새 트랜잭션 시작. timestamp 는 Now
+func (l *MockLedger) Tx() {
+
+	if l.txCount > 0 {
+		l.Stub.MockTransactionEnd(l.Stub.TxID)
+	}
+	l.txCount++
+
+	l.Stub.MockTransactionStart("tx" + strconv.Itoa(l.txCount))
+	l.Stub.TxTimestamp.Seconds = l.Now
+	l.Stub.TxTimestamp.Nanos = 0
+}
+
+// Check 준비 단계의 에러는 바로 실패 처리
+func (l *MockLedger) Check(err error) {
+
+	l.T.Helper()
+	if err != nil {
+		l.T.Fatal(err)
+	}
+}
+
+// Issue partition 발행 (총발행량, holder list 포함)
+func (l *MockLedger) Issue(partition string) {
+
+	l.T.Helper()
+	l.Tx()
+	_, err := token.IssueToken(l.Ctx, token.PartitionToken{TokenID: partition, TokenName: partition})
+	l.Check(err)
+}
+
+// Wallet 빈 wallet 생성
+func (l *MockLedger) Wallet(addresses ...string) {
+
+	l.T.Helper()
+	for _, address := range addresses {
+		l.Tx()
+		_, err := wallet.CreateWallet(l.Ctx, wallet.TokenWallet{TokenWalletId: address, PartitionTokens: map[string][]token.PartitionToken{}})
+		l.Check(err)
+	}
+}
+
+// Mint address 에 partition 토큰 발행
+func (l *MockLedger) Mint(address string, partition string, amount int64) {
+
+	l.T.Helper()
+	l.Tx()
+	err := wallet.MintByPartition(l.Ctx, token.MintByPartitionStruct{Minter: address, Partition: partition, Amount: amount})
+	l.Check(err)
+}
+
+// Balance 현재 분할 기준 잔고
+func (l *MockLedger) Balance(address string, partition string) int64 {
+
+	l.T.Helper()
+	balance, err := token.BalanceOfByPartition(l.Ctx, address, partition)
+	l.Check(err)
+	return balance
+}
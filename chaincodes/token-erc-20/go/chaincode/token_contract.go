@@ -9,7 +9,9 @@ import (
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ccutils"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/controller"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/ledgermanager"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/access"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/operator"
+	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/ownership"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/token"
 	"github.com/the-medium-tech/mdl-chaincodes/chaincode/services/wallet"
 )
@@ -35,17 +37,43 @@ type Event struct {
 }
 
 /** 체인코드 init 위해 임시로 코드 작성
+ * 최초 호출자가 owner 가 되며, 두번째 호출부터는 에러
  */
-func (s *SmartContract) IsInit(ctx contractapi.TransactionContextInterface) error {
+func (s *SmartContract) IsInit(ctx contractapi.TransactionContextInterface, args map[string]interface{}) error {
 
 	log.Printf("Initial Isinit run")
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := _getMSPID(ctx)
 	if err != nil {
 		return err
 	}
 
+	id, err := _msgSender(ctx)
+	if err != nil {
+		return err
+	}
+
+	mspId, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("failed to get client msp id: %v", err)
+	}
+
+	// config 는 선택 입력
+	// {"defaultPageSize":10,"timezone":"Asia/Seoul","features":{"AirDrop":false}}
+	config, err := ownership.ParseConfig(args)
+	if err != nil {
+		return err
+	}
+
+	ownerAddress := ccutils.GetAddress([]byte(id))
+
+	// Init Ownership (이미 초기화 되어 있으면 에러)
+	_, err = ownership.InitOwnership(ctx, ownership.OwnershipStruct{Owner: ownerAddress, OwnerMSP: mspId, Version: ChaincodeVersion, Config: *config})
+	if err != nil {
+		return err
+	}
+
 	// Initial Isinit run
 	err = ctx.GetStub().PutState("Isinit", []byte("Isinit"))
 	if err != nil {
@@ -78,14 +106,44 @@ func (s *SmartContract) IsInit(ctx contractapi.TransactionContextInterface) erro
 		return err
 	}
 
+	// Init Admin Role
+	adminRole := access.RoleStruct{Role: access.RoleAdmin, SubjectType: access.SubjectTypeAddress, Subject: ownerAddress, GrantedBy: ownerAddress}
+
+	_, err = access.GrantRole(ctx, adminRole)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// GetContractInfo 체인코드 버전, owner, config 조회
+func (s *SmartContract) GetContractInfo(ctx contractapi.TransactionContextInterface) (*ccutils.Response, error) {
+
+	ownershipStruct, err := ownership.GetOwnership(ctx)
+	if err != nil {
+		return ccutils.GenerateErrorResponse(err)
+	}
+
+	retData := map[string]interface{}{
+		"name":            ChaincodeName,
+		"author":          Author,
+		"version":         ChaincodeVersion,
+		"deployedVersion": ownershipStruct.Version,
+		"owner":           ownershipStruct.Owner,
+		"ownerMSP":        ownershipStruct.OwnerMSP,
+		"pendingOwner":    ownershipStruct.PendingOwner,
+		"config":          ownershipStruct.Config,
+	}
+
+	return ccutils.GenerateSuccessResponse(ctx.GetStub().GetTxID(), ccutils.ChaincodeSuccess, ccutils.CodeMessage[ccutils.ChaincodeSuccess], retData)
+}
+
 /** org1, org2, 피어(관리자, 클라이언트) 노드 주소 생성
  */
 func (s *SmartContract) Init(ctx contractapi.TransactionContextInterface) (string, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := _getMSPID(ctx)
 	if err != nil {
 		return "", err
@@ -133,7 +191,7 @@ func _getMSPID(ctx contractapi.TransactionContextInterface) error {
 // Users can use this function to get their own account id, which they can then give to others as the payment address
 func (s *SmartContract) ClientAccountID(ctx contractapi.TransactionContextInterface) (string, error) {
 
-	// Check minter authorization - this sample assumes Org1 is the central banker with privilege to mint new tokens
+	// Read caller MSP ID (no authorization - roles are checked only by _checkFunctionRoles)
 	err := _getMSPID(ctx)
 	if err != nil {
 		return "", err
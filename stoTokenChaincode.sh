@@ -492,7 +492,9 @@ function IsInit() {
     # set query
     echo ${FUNCNAME[0]}
 
-    param="{\"Args\":[\"${FUNCNAME[0]}\"]}"
+    ## config sample
+    # '{"Args":["IsInit","{\"defaultPageSize\":10,\"timezone\":\"Asia/Seoul\"}"]}'
+    param="{\"Args\":[\"${FUNCNAME[0]}\",\"{}\"]}"
     echo $param
 
     # set